@@ -13,7 +13,9 @@ import (
 	"k8s.io/client-go/rest"
 
 	"github.com/aerogear/unifiedpush-operator/pkg/apis"
+	operatorcache "github.com/aerogear/unifiedpush-operator/pkg/cache"
 	"github.com/aerogear/unifiedpush-operator/pkg/controller"
+	"github.com/aerogear/unifiedpush-operator/pkg/webhook"
 
 	enmassev1beta "github.com/enmasseproject/enmasse/pkg/apis/enmasse/v1beta1"
 	messaginguserv1beta "github.com/enmasseproject/enmasse/pkg/apis/user/v1beta1"
@@ -37,17 +39,20 @@ import (
 	v1 "k8s.io/api/core/v1"
 	kruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
 	"sigs.k8s.io/controller-runtime/pkg/runtime/signals"
 )
 
-// Change below variables to serve metrics on different host or port.
+// Change below variables to serve metrics and webhooks on different host or port.
 var (
 	metricsHost               = "0.0.0.0"
 	metricsPort         int32 = 8383
 	operatorMetricsPort int32 = 8686
+	webhookPort         int32 = 8443
+	webhookCertDir            = "/tmp/k8s-webhook-server/serving-certs"
 	syncperiod                = time.Duration(time.Minute * 5)
 )
 var log = logf.Log.WithName("cmd")
@@ -82,11 +87,14 @@ func main() {
 
 	printVersion()
 
-	namespace, err := k8sutil.GetWatchNamespace()
+	watchNamespace, err := k8sutil.GetWatchNamespace()
 	if err != nil {
 		log.Error(err, "Failed to get watch namespace")
 		os.Exit(1)
 	}
+	// WATCH_NAMESPACE is either a single namespace, a comma-separated
+	// list of them or empty to watch all namespaces
+	namespaces := operatorcache.WatchNamespaces(watchNamespace)
 
 	// Get a config to talk to the apiserver
 	cfg, err := config.GetConfig()
@@ -104,13 +112,22 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Create a new Cmd to provide shared dependencies and start components
-	mgr, err := manager.New(cfg, manager.Options{
-		Namespace:          namespace,
+	options := manager.Options{
 		MapperProvider:     restmapper.NewDynamicRESTMapper,
 		MetricsBindAddress: fmt.Sprintf("%s:%d", metricsHost, metricsPort),
 		SyncPeriod:         &syncperiod,
-	})
+	}
+	if len(namespaces) == 1 {
+		options.Namespace = namespaces[0]
+	} else if len(namespaces) > 1 {
+		log.Info("Watching several namespaces", "Namespaces", namespaces)
+		options.NewCache = operatorcache.MultiNamespacedCacheBuilder(namespaces)
+	} else {
+		log.Info("Watching all namespaces")
+	}
+
+	// Create a new Cmd to provide shared dependencies and start components
+	mgr, err := manager.New(cfg, options)
 	if err != nil {
 		log.Error(err, "")
 		os.Exit(1)
@@ -159,7 +176,13 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err = serveCRMetrics(cfg); err != nil {
+	// Setup all admission webhooks
+	if err := webhook.AddToManager(mgr, webhookPort, webhookCertDir); err != nil {
+		log.Error(err, "")
+		os.Exit(1)
+	}
+
+	if err = serveCRMetrics(cfg, namespaces); err != nil {
 		log.Info("Could not generate and serve custom resource metrics", "error", err.Error())
 	}
 
@@ -175,12 +198,20 @@ func main() {
 		log.Info("Could not create metrics Service", "error", err.Error())
 	}
 
+	// The monitoring objects of the operator go to its own namespace,
+	// which isn't necessarily watched by the cache of the manager
+	operatorClient, err := client.New(cfg, client.Options{Scheme: mgr.GetScheme(), Mapper: mgr.GetRESTMapper()})
+	if err != nil {
+		log.Error(err, "")
+		os.Exit(1)
+	}
+
 	if service != nil {
 
 		serviceMonitor := &monitoringv1.ServiceMonitor{
 			ObjectMeta: metav1.ObjectMeta{Name: service.Name, Namespace: service.Namespace},
 		}
-		op, err := controllerutil.CreateOrUpdate(context.TODO(), mgr.GetClient(), serviceMonitor, func(ignore kruntime.Object) error {
+		op, err := controllerutil.CreateOrUpdate(context.TODO(), operatorClient, serviceMonitor, func(ignore kruntime.Object) error {
 
 			// Set defaults
 			defaultServiceMonitor := metrics.GenerateServiceMonitor(service)
@@ -215,10 +246,9 @@ func main() {
 			log.Error(err, "")
 			os.Exit(1)
 		}
-		client := mgr.GetClient()
 		prometheusRule := &monitoringv1.PrometheusRule{ObjectMeta: metav1.ObjectMeta{Name: "unifiedpush-operator", Namespace: operatorNamespace}}
 
-		controllerutil.CreateOrUpdate(ctx, client, prometheusRule, func(ignore k8sruntime.Object) error {
+		controllerutil.CreateOrUpdate(ctx, operatorClient, prometheusRule, func(ignore k8sruntime.Object) error {
 			reconcilePrometheusRule(prometheusRule)
 			// Set owner reference to be the Service
 			err = controllerutil.SetControllerReference(service, prometheusRule, mgr.GetScheme())
@@ -227,7 +257,7 @@ func main() {
 
 		grafanaDashboard := &integreatlyv1alpha1.GrafanaDashboard{ObjectMeta: metav1.ObjectMeta{Name: "unifiedpush-operator", Namespace: operatorNamespace}}
 
-		controllerutil.CreateOrUpdate(ctx, client, grafanaDashboard, func(ignore k8sruntime.Object) error {
+		controllerutil.CreateOrUpdate(ctx, operatorClient, grafanaDashboard, func(ignore k8sruntime.Object) error {
 			reconcileGrafanaDashboard(grafanaDashboard)
 			// Set owner reference to be the Service
 			err = controllerutil.SetControllerReference(service, grafanaDashboard, mgr.GetScheme())
@@ -246,20 +276,18 @@ func main() {
 
 // serveCRMetrics gets the Operator/CustomResource GVKs and generates metrics based on those types.
 // It serves those metrics on "http://metricsHost:operatorMetricsPort".
-func serveCRMetrics(cfg *rest.Config) error {
+func serveCRMetrics(cfg *rest.Config, namespaces []string) error {
 	// Below function returns filtered operator/CustomResource specific GVKs.
 	// For more control override the below GVK list with your own custom logic.
 	filteredGVK, err := k8sutil.GetGVKsFromAddToScheme(apis.AddToScheme)
 	if err != nil {
 		return err
 	}
-	// Get the namespace the operator is currently deployed in.
-	operatorNs, err := k8sutil.GetOperatorNamespace()
-	if err != nil {
-		return err
+	// Generate metrics for the watched namespaces, or all of them
+	ns := namespaces
+	if len(ns) == 0 {
+		ns = []string{metav1.NamespaceAll}
 	}
-	// To generate metrics in other namespaces, add the values below.
-	ns := []string{operatorNs}
 	// Generate and serve custom resource specific metrics.
 	err = kubemetrics.GenerateAndServeCRMetrics(cfg, ns, filteredGVK, metricsHost, operatorMetricsPort)
 	if err != nil {
@@ -0,0 +1,78 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AndroidVariantSpec defines the desired state of AndroidVariant
+// +k8s:openapi-gen=true
+type AndroidVariantSpec struct {
+	// Name is the name of the variant in UPS. Defaults to the name
+	// of the CR.
+	Name string `json:"name,omitempty"`
+
+	// Description is a human friendly description for the variant.
+	Description string `json:"description,omitempty"`
+
+	// PushApplication is the name of the PushApplication CR, in the
+	// same namespace, that this variant will be added to
+	PushApplication string `json:"pushApplication"`
+
+	// CredentialsSecret is the name of a Secret, in the same
+	// namespace, holding the FCM credentials of the variant. It must
+	// contain the following keys:
+	//
+	// serverKey: the FCM server key
+	// senderId: the FCM sender id
+	//
+	CredentialsSecret string `json:"credentialsSecret"`
+}
+
+// AndroidVariantStatus defines the observed state of AndroidVariant
+// +k8s:openapi-gen=true
+type AndroidVariantStatus struct {
+	// Phase indicates whether the CR is reconciling(good), failing(bad), or initializing.
+	Phase StatusPhase `json:"phase"`
+
+	// Message is a more human-readable message indicating details about current phase or error.
+	Message string `json:"message,omitempty"`
+
+	// PushApplicationId is the id of the application in UPS that the
+	// variant was created in
+	PushApplicationId string `json:"pushApplicationId,omitempty"`
+
+	// VariantId is the id of the variant in UPS
+	VariantId string `json:"variantId,omitempty"`
+
+	// Secret is the variant secret, used by devices to register
+	// with the variant
+	Secret string `json:"secret,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AndroidVariant is the Schema for the androidvariants API
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:path=androidvariants
+// +kubebuilder:singular=androidvariant
+// +kubebuilder:subresource:status
+type AndroidVariant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AndroidVariantSpec   `json:"spec,omitempty"`
+	Status AndroidVariantStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AndroidVariantList contains a list of AndroidVariant
+type AndroidVariantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AndroidVariant `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AndroidVariant{}, &AndroidVariantList{})
+}
@@ -0,0 +1,93 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DeviceImportSpec defines the desired state of DeviceImport
+// +k8s:openapi-gen=true
+type DeviceImportSpec struct {
+	// Variant is the variant CR, in the same namespace, that the
+	// devices will be registered with
+	Variant VariantReference `json:"variant"`
+
+	// ConfigMapKeyRef selects a key of a ConfigMap holding a JSON
+	// array of installations, e.g.
+	//
+	// [{"deviceToken": "abc...", "alias": "alice", "categories": ["news"]}]
+	//
+	// Only one of ConfigMapKeyRef or SecretKeyRef should be set.
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+
+	// SecretKeyRef selects a key of a Secret holding a JSON array of
+	// installations, in the same format as ConfigMapKeyRef.
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+
+	// BatchSize is the number of installations sent to UPS in each
+	// request. Defaults to 1000.
+	BatchSize int `json:"batchSize,omitempty"`
+}
+
+// VariantReference points at one of the variant CRs
+type VariantReference struct {
+	// Kind is the kind of the variant CR: AndroidVariant,
+	// IOSTokenVariant or WebPushVariant
+	Kind string `json:"kind"`
+
+	// Name is the name of the variant CR
+	Name string `json:"name"`
+}
+
+// DeviceImportStatus defines the observed state of DeviceImport
+// +k8s:openapi-gen=true
+type DeviceImportStatus struct {
+	// Phase indicates whether the import is waiting to start(initializing), in progress(reconciling), failing(bad), or done(complete).
+	Phase StatusPhase `json:"phase"`
+
+	// Message is a more human-readable message indicating details about current phase or error.
+	Message string `json:"message,omitempty"`
+
+	// Imported is the number of installations accepted by UPS
+	Imported int `json:"imported"`
+
+	// Failed is the number of installations in batches that UPS
+	// returned an error for
+	Failed int `json:"failed"`
+
+	// Rejected is the number of installations that were not sent to
+	// UPS because they aren't valid, e.g. they have no deviceToken
+	Rejected int `json:"rejected"`
+
+	// CompletedAt is the time at which all of the installations had
+	// been processed. A completed import is never run again.
+	CompletedAt *metav1.Time `json:"completedAt,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DeviceImport is the Schema for the deviceimports API
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:path=deviceimports
+// +kubebuilder:singular=deviceimport
+// +kubebuilder:subresource:status
+type DeviceImport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DeviceImportSpec   `json:"spec,omitempty"`
+	Status DeviceImportStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DeviceImportList contains a list of DeviceImport
+type DeviceImportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DeviceImport `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DeviceImport{}, &DeviceImportList{})
+}
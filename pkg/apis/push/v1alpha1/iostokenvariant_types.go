@@ -0,0 +1,87 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// IOSTokenVariantSpec defines the desired state of IOSTokenVariant
+// +k8s:openapi-gen=true
+type IOSTokenVariantSpec struct {
+	// Name is the name of the variant in UPS. Defaults to the name
+	// of the CR.
+	Name string `json:"name,omitempty"`
+
+	// Description is a human friendly description for the variant.
+	Description string `json:"description,omitempty"`
+
+	// PushApplication is the name of the PushApplication CR, in the
+	// same namespace, that this variant will be added to
+	PushApplication string `json:"pushApplication"`
+
+	// CredentialsSecret is the name of a Secret, in the same
+	// namespace, holding the APNs token credentials of the variant.
+	// It must contain the following keys:
+	//
+	// privateKey: the contents of the .p8 private key file
+	// keyId: the id of the private key
+	// teamId: the Apple developer team id
+	// bundleId: the bundle id of the app
+	//
+	// It can optionally contain "production: true" to use the
+	// production APNs servers instead of the development ones.
+	//
+	CredentialsSecret string `json:"credentialsSecret"`
+}
+
+// IOSTokenVariantStatus defines the observed state of IOSTokenVariant
+// +k8s:openapi-gen=true
+type IOSTokenVariantStatus struct {
+	// Phase indicates whether the CR is reconciling(good), failing(bad), or initializing.
+	Phase StatusPhase `json:"phase"`
+
+	// Message is a more human-readable message indicating details about current phase or error.
+	Message string `json:"message,omitempty"`
+
+	// PushApplicationId is the id of the application in UPS that the
+	// variant was created in
+	PushApplicationId string `json:"pushApplicationId,omitempty"`
+
+	// VariantId is the id of the variant in UPS
+	VariantId string `json:"variantId,omitempty"`
+
+	// Secret is the variant secret, used by devices to register
+	// with the variant
+	Secret string `json:"secret,omitempty"`
+
+	// CredentialsHash is a hash of the private key that was last sent
+	// to UPS, which is used to tell whether it has changed since
+	CredentialsHash string `json:"credentialsHash,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// IOSTokenVariant is the Schema for the iostokenvariants API
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:path=iostokenvariants
+// +kubebuilder:singular=iostokenvariant
+// +kubebuilder:subresource:status
+type IOSTokenVariant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   IOSTokenVariantSpec   `json:"spec,omitempty"`
+	Status IOSTokenVariantStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// IOSTokenVariantList contains a list of IOSTokenVariant
+type IOSTokenVariantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []IOSTokenVariant `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&IOSTokenVariant{}, &IOSTokenVariantList{})
+}
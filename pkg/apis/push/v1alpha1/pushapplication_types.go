@@ -0,0 +1,73 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PushApplicationSpec defines the desired state of PushApplication
+// +k8s:openapi-gen=true
+type PushApplicationSpec struct {
+	// Name is the name of the application in UPS. Defaults to the name
+	// of the CR.
+	Name string `json:"name,omitempty"`
+
+	// UnifiedPushServer is the name of the UnifiedPushServer CR, in
+	// the same namespace, that this PushApplication will be
+	// registered with
+	UnifiedPushServer string `json:"unifiedPushServer"`
+
+	// Description is a description of the app to be displayed in
+	// the UnifiedPush Server admin UI
+	Description string `json:"description,omitempty"`
+}
+
+// PushApplicationStatus defines the observed state of PushApplication
+// +k8s:openapi-gen=true
+type PushApplicationStatus struct {
+	// Phase indicates whether the CR is reconciling(good), failing(bad), or initializing.
+	Phase StatusPhase `json:"phase"`
+
+	// Message is a more human-readable message indicating details about current phase or error.
+	Message string `json:"message,omitempty"`
+
+	// PushApplicationId is an identifer used to register Variants
+	// with this PushApplication
+	PushApplicationId string `json:"pushApplicationId,omitempty"`
+
+	// SecretName is the name of the Secret holding the
+	// pushApplicationID and masterSecret of this PushApplication
+	SecretName string `json:"secretName,omitempty"`
+
+	// MobileServicesSecret is the name of the Secret holding the
+	// mobile-services.json client configuration for the variants of
+	// this PushApplication
+	MobileServicesSecret string `json:"mobileServicesSecret,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PushApplication is the Schema for the pushapplications API
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:path=pushapplications
+// +kubebuilder:singular=pushapplication
+// +kubebuilder:subresource:status
+type PushApplication struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PushApplicationSpec   `json:"spec,omitempty"`
+	Status PushApplicationStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PushApplicationList contains a list of PushApplication
+type PushApplicationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PushApplication `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PushApplication{}, &PushApplicationList{})
+}
@@ -0,0 +1,92 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PushMessageSpec defines the desired state of PushMessage
+// +k8s:openapi-gen=true
+type PushMessageSpec struct {
+	// PushApplication is the name of the PushApplication CR, in the
+	// same namespace, whose devices the message will be sent to
+	PushApplication string `json:"pushApplication"`
+
+	// Alert is the text of the notification
+	Alert string `json:"alert,omitempty"`
+
+	// Sound is the name of a sound file to play on the device
+	Sound string `json:"sound,omitempty"`
+
+	// Badge is the number to show on the app icon, on platforms that
+	// support it
+	Badge int `json:"badge,omitempty"`
+
+	// Data is arbitrary data delivered to the app along with the
+	// notification
+	Data map[string]string `json:"data,omitempty"`
+
+	// Aliases restricts the message to the devices registered with
+	// one of these aliases
+	Aliases []string `json:"aliases,omitempty"`
+
+	// Categories restricts the message to the devices registered
+	// with one of these categories
+	Categories []string `json:"categories,omitempty"`
+
+	// Variants restricts the message to the devices of the variants
+	// with these ids
+	Variants []string `json:"variants,omitempty"`
+
+	// TTL is the number of seconds that the push network should keep
+	// trying to deliver the message for
+	TTL int `json:"ttl,omitempty"`
+}
+
+// PushMessageStatus defines the observed state of PushMessage
+// +k8s:openapi-gen=true
+type PushMessageStatus struct {
+	// Phase indicates whether the message is waiting to be sent(initializing), being sent(sending), failing(bad), or has been sent(complete).
+	// A message left sending may have been accepted by UPS, so it isn't sent again either.
+	Phase StatusPhase `json:"phase"`
+
+	// Message is a more human-readable message indicating details about current phase or error.
+	Message string `json:"message,omitempty"`
+
+	// Delivered is true once the message has been accepted by UPS.
+	// A delivered message is never sent again.
+	Delivered bool `json:"delivered,omitempty"`
+
+	// SentAt is the time at which the message was accepted by UPS
+	SentAt *metav1.Time `json:"sentAt,omitempty"`
+
+	// Response is the body of the response from UPS
+	Response string `json:"response,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PushMessage is the Schema for the pushmessages API
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:path=pushmessages
+// +kubebuilder:singular=pushmessage
+// +kubebuilder:subresource:status
+type PushMessage struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PushMessageSpec   `json:"spec,omitempty"`
+	Status PushMessageStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PushMessageList contains a list of PushMessage
+type PushMessageList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PushMessage `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PushMessage{}, &PushMessageList{})
+}
@@ -0,0 +1,48 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetCondition returns the condition of the given type, or nil if it
+// isn't set
+func (s *UnifiedPushServerStatus) GetCondition(conditionType UnifiedPushServerConditionType) *UnifiedPushServerCondition {
+	for i := range s.Conditions {
+		if s.Conditions[i].Type == conditionType {
+			return &s.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// SetCondition adds or updates the condition of the given type. The
+// lastTransitionTime is only changed when the status changes.
+func (s *UnifiedPushServerStatus) SetCondition(conditionType UnifiedPushServerConditionType, status corev1.ConditionStatus, reason string, message string) {
+	condition := s.GetCondition(conditionType)
+	if condition == nil {
+		s.Conditions = append(s.Conditions, UnifiedPushServerCondition{Type: conditionType})
+		condition = &s.Conditions[len(s.Conditions)-1]
+	}
+	if condition.Status != status {
+		condition.Status = status
+		condition.LastTransitionTime = metav1.Now()
+	}
+	condition.Reason = reason
+	condition.Message = message
+}
+
+// RemoveCondition removes the condition of the given type, e.g. when
+// the component that it describes isn't used anymore
+func (s *UnifiedPushServerStatus) RemoveCondition(conditionType UnifiedPushServerConditionType) {
+	if s.GetCondition(conditionType) == nil {
+		return
+	}
+	conditions := []UnifiedPushServerCondition{}
+	for _, c := range s.Conditions {
+		if c.Type != conditionType {
+			conditions = append(conditions, c)
+		}
+	}
+	s.Conditions = conditions
+}
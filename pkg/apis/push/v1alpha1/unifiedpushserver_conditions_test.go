@@ -0,0 +1,45 @@
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestUnifiedPushServerStatus_SetCondition(t *testing.T) {
+	status := UnifiedPushServerStatus{}
+
+	status.SetCondition(ConditionDatabaseReady, corev1.ConditionFalse, "DeploymentNotReady", "Waiting")
+	condition := status.GetCondition(ConditionDatabaseReady)
+	if condition == nil {
+		t.Fatal("expected DatabaseReady condition to be set")
+	}
+	if condition.Status != corev1.ConditionFalse || condition.Reason != "DeploymentNotReady" || condition.Message != "Waiting" {
+		t.Errorf("unexpected condition %+v", condition)
+	}
+
+	// The transition time is kept while the status doesn't change
+	transitioned := metav1.NewTime(time.Now().Add(-time.Hour))
+	condition.LastTransitionTime = transitioned
+	status.SetCondition(ConditionDatabaseReady, corev1.ConditionFalse, "DeploymentNotReady", "Still waiting")
+	condition = status.GetCondition(ConditionDatabaseReady)
+	if !condition.LastTransitionTime.Equal(&transitioned) || condition.Message != "Still waiting" {
+		t.Errorf("expected only the message to change, got %+v", condition)
+	}
+
+	status.SetCondition(ConditionDatabaseReady, corev1.ConditionTrue, "DeploymentReady", "")
+	condition = status.GetCondition(ConditionDatabaseReady)
+	if condition.Status != corev1.ConditionTrue || condition.LastTransitionTime.Equal(&transitioned) {
+		t.Errorf("expected status and transition time to change, got %+v", condition)
+	}
+	if len(status.Conditions) != 1 {
+		t.Errorf("expected a single condition, got %+v", status.Conditions)
+	}
+
+	status.RemoveCondition(ConditionDatabaseReady)
+	if status.GetCondition(ConditionDatabaseReady) != nil {
+		t.Errorf("expected DatabaseReady condition to be removed, got %+v", status.Conditions)
+	}
+}
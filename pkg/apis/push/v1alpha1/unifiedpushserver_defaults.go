@@ -0,0 +1,53 @@
+package v1alpha1
+
+import (
+	"github.com/aerogear/unifiedpush-operator/pkg/config"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// SetDefaults fills in the resource requirements and PVC size that
+// aren't set in the spec with the operator defaults from cfg, so that
+// the values in effect are visible in the CR, and changing the
+// defaults doesn't affect existing CRs. It returns true if the spec
+// was changed.
+func (ups *UnifiedPushServer) SetDefaults(cfg config.Config) bool {
+	changed := setDefaultResourceRequirements(&ups.Spec.UnifiedPushResourceRequirements, cfg.UPSMemoryLimit, cfg.UPSCpuLimit, cfg.UPSMemoryRequest, cfg.UPSCpuRequest)
+	changed = setDefaultResourceRequirements(&ups.Spec.OAuthResourceRequirements, cfg.OauthMemoryLimit, cfg.OauthCpuLimit, cfg.OauthMemoryRequest, cfg.OauthCpuRequest) || changed
+
+	// The PostgreSQL settings are only used when the operator manages
+	// the database
+	if !ups.Spec.ExternalDB {
+		changed = setDefaultResourceRequirements(&ups.Spec.PostgresResourceRequirements, cfg.PostgresMemoryLimit, cfg.PostgresCpuLimit, cfg.PostgresMemoryRequest, cfg.PostgresCpuRequest) || changed
+		if ups.Spec.PostgresPVCSize == "" {
+			ups.Spec.PostgresPVCSize = cfg.PostgresPVCSize
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+func setDefaultResourceRequirements(reqs *corev1.ResourceRequirements, memoryLimit string, cpuLimit string, memoryRequest string, cpuRequest string) bool {
+	if reqs.Limits == nil {
+		reqs.Limits = corev1.ResourceList{}
+	}
+	if reqs.Requests == nil {
+		reqs.Requests = corev1.ResourceList{}
+	}
+
+	changed := setDefaultQuantity(reqs.Limits, corev1.ResourceMemory, memoryLimit)
+	changed = setDefaultQuantity(reqs.Limits, corev1.ResourceCPU, cpuLimit) || changed
+	changed = setDefaultQuantity(reqs.Requests, corev1.ResourceMemory, memoryRequest) || changed
+	changed = setDefaultQuantity(reqs.Requests, corev1.ResourceCPU, cpuRequest) || changed
+	return changed
+}
+
+func setDefaultQuantity(resources corev1.ResourceList, name corev1.ResourceName, value string) bool {
+	if _, ok := resources[name]; ok {
+		return false
+	}
+	resources[name] = resource.MustParse(value)
+	return true
+}
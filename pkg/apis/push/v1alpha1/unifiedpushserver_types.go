@@ -2,6 +2,7 @@ package v1alpha1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
@@ -54,6 +55,130 @@ type UnifiedPushServerSpec struct {
 
 	Affinity    *corev1.Affinity    `json:"affinity,omitempty"`
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Replicas is the number of UnifiedPush Server pods. With more than one, the pods are
+	// updated one at a time, spread across nodes unless Affinity is set, and protected by a
+	// PodDisruptionBudget. Defaults to 1.
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// MinAvailable is the number of UnifiedPush Server pods that have to be available for the
+	// UnifiedPushServer to be ready, and that voluntary disruptions such as node drains have
+	// to leave running. Defaults to one less than Replicas, and to 1 for a single replica.
+	MinAvailable *int32 `json:"minAvailable,omitempty"`
+
+	// Autoscaling enables a HorizontalPodAutoscaler for the UnifiedPush Server Deployment,
+	// which then manages the number of replicas instead of Replicas.
+	Autoscaling *UnifiedPushServerAutoscaling `json:"autoscaling,omitempty"`
+
+	// Images overrides the container images of this UnifiedPushServer. Both tags and
+	// digests are accepted, e.g. quay.io/aerogear/unifiedpush-configurable-container@sha256:...
+	// Images that aren't set default to the ones the operator was configured with.
+	Images UnifiedPushServerImages `json:"images,omitempty"`
+
+	// ImagePullPolicy is the pull policy of all of the containers. Defaults to Always.
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
+	// ImagePullSecrets are the Secrets used to pull the images of all of the pods, e.g. for a
+	// private mirror registry
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+
+	// CredentialRotationDays is the number of days after which the passwords of the
+	// PostgreSQL and AMQ users that are managed by the operator are rotated. Rotation is
+	// disabled when it's not set, but can still be requested with the
+	// push.aerogear.org/rotate-credentials annotation.
+	CredentialRotationDays int32 `json:"credentialRotationDays,omitempty"`
+
+	// Ingress configures the Ingress that exposes the OAuth proxy on clusters without the
+	// route.openshift.io API, where it's created instead of a Route
+	Ingress *UnifiedPushServerIngress `json:"ingress,omitempty"`
+
+	// Auth configures how the proxy in front of the admin console authenticates users. They
+	// log in with the OpenShift OAuth server when it's not set.
+	Auth *UnifiedPushServerAuth `json:"auth,omitempty"`
+}
+
+// UnifiedPushServerAuth configures the authentication of the admin console
+// +k8s:openapi-gen=true
+type UnifiedPushServerAuth struct {
+	// OpenShift restricts which of the users that log in with the OpenShift OAuth server can
+	// open the admin console. All of them can when it's not set.
+	OpenShift *UnifiedPushServerOpenShiftAuth `json:"openshift,omitempty"`
+
+	// OIDC has users log in with an OpenID Connect provider, e.g. Keycloak, instead of the
+	// OpenShift OAuth server
+	OIDC *UnifiedPushServerOIDC `json:"oidc,omitempty"`
+}
+
+// UnifiedPushServerOpenShiftAuth configures the access checks of the OpenShift OAuth proxy
+// +k8s:openapi-gen=true
+type UnifiedPushServerOpenShiftAuth struct {
+	// SubjectAccessReview is the access that users need to open the admin console, or to
+	// call its API with a bearer token. It defaults to creating RoleBindings in the namespace,
+	// which only its admins can do, unless AllowedGroups is set.
+	SubjectAccessReview *UnifiedPushServerSubjectAccessReview `json:"subjectAccessReview,omitempty"`
+
+	// AllowedGroups restricts the admin console to the users in any of these OpenShift
+	// groups. They don't need to be admins of the namespace, unless SubjectAccessReview is
+	// also set.
+	AllowedGroups []string `json:"allowedGroups,omitempty"`
+
+	// BearerTokens lets the API of the admin console be called with the OpenShift bearer
+	// token of a user that passes SubjectAccessReview. The ServiceAccount of the
+	// UnifiedPushServer needs the system:auth-delegator cluster role to check the tokens.
+	BearerTokens bool `json:"bearerTokens,omitempty"`
+}
+
+// UnifiedPushServerSubjectAccessReview is an access check, passed to the OpenShift OAuth
+// proxy in --openshift-sar and --openshift-delegate-urls
+// +k8s:openapi-gen=true
+type UnifiedPushServerSubjectAccessReview struct {
+	// Namespace of the resource. It defaults to the namespace of the UnifiedPushServer.
+	Namespace string `json:"namespace,omitempty"`
+
+	// Group is the API group of the resource, empty for the core group
+	Group string `json:"group,omitempty"`
+
+	// Resource is the plural name of the resource, e.g. pushapplications
+	Resource string `json:"resource"`
+
+	// ResourceName is the name of a single resource, all of them when it's not set
+	ResourceName string `json:"resourceName,omitempty"`
+
+	// Verb is the verb that users need to be allowed, e.g. update
+	Verb string `json:"verb"`
+}
+
+// UnifiedPushServerOIDC configures an OpenID Connect provider for the admin console
+// +k8s:openapi-gen=true
+type UnifiedPushServerOIDC struct {
+	// IssuerURL is the URL of the OpenID Connect issuer, e.g.
+	// https://keycloak.example.com/auth/realms/push
+	IssuerURL string `json:"issuerURL"`
+
+	// ClientSecret is the name of a Secret with the client-id and client-secret of the
+	// OpenID Connect client
+	ClientSecret string `json:"clientSecret"`
+
+	// AllowedGroups restricts the admin console to the users in any of these groups, taken
+	// from the groups claim of their ID token. All authenticated users are allowed when it's
+	// empty.
+	AllowedGroups []string `json:"allowedGroups,omitempty"`
+}
+
+// UnifiedPushServerIngress configures the Ingress of a UnifiedPushServer
+// +k8s:openapi-gen=true
+type UnifiedPushServerIngress struct {
+	// ClassName is the class of the ingress controller that serves the Ingress, set in the
+	// kubernetes.io/ingress.class annotation. The default one is used when it's not set.
+	ClassName string `json:"className,omitempty"`
+
+	// Host is the host name that the admin console is served on. The Ingress matches all
+	// hosts when it's not set.
+	Host string `json:"host,omitempty"`
+
+	// TLSSecretName is the name of a Secret with the TLS certificate and key of the host.
+	// The admin console is served over plain HTTP when it's not set.
+	TLSSecretName string `json:"tlsSecretName,omitempty"`
 }
 
 // UnifiedPushServerStatus defines the observed state of UnifiedPushServer
@@ -73,6 +198,95 @@ type UnifiedPushServerStatus struct {
 	// SecondaryResources is a map of all the secondary resources types and names created for
 	// this CR.  e.g "Deployment": [ "DeploymentName1", "DeploymentName2" ]
 	SecondaryResources map[string][]string `json:"secondaryResources,omitempty"`
+
+	// Conditions describe the state of the UnifiedPushServer as a whole (Available, Progressing
+	// and Degraded) and of each of its components (DatabaseReady, MessageBrokerReady,
+	// RouteAdmitted or IngressReady and BackupsConfigured), so that it's possible to tell which
+	// part is at fault when something is wrong.
+	Conditions []UnifiedPushServerCondition `json:"conditions,omitempty"`
+
+	// AdminConsoleURL is the public URL of the UnifiedPush Server admin console, behind the
+	// OAuth proxy. It's empty until the Route has been admitted, or the Ingress has been given
+	// an address.
+	AdminConsoleURL string `json:"adminConsoleURL,omitempty"`
+
+	// InternalServiceURL is the in-cluster URL of the UnifiedPush Server REST API, which
+	// doesn't go through the OAuth proxy.
+	InternalServiceURL string `json:"internalServiceURL,omitempty"`
+
+	// Images are the container images that are running for this CR
+	Images UnifiedPushServerImages `json:"images,omitempty"`
+
+	// ObservedGeneration is the generation of the spec that was last reconciled successfully,
+	// with all of the components ready. Once it's equal to metadata.generation, all of the
+	// changes to the spec have been applied.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LastCredentialRotation is the time at which the password of the PostgreSQL or AMQ user
+	// was last rotated
+	LastCredentialRotation *metav1.Time `json:"lastCredentialRotation,omitempty"`
+}
+
+// UnifiedPushServerImages are the container images used by a UnifiedPushServer
+// +k8s:openapi-gen=true
+type UnifiedPushServerImages struct {
+	// UnifiedPush is the image of the UnifiedPush Server container
+	UnifiedPush string `json:"unifiedPush,omitempty"`
+
+	// OAuthProxy is the image of the OAuth proxy container
+	OAuthProxy string `json:"oauthProxy,omitempty"`
+
+	// Postgres is the image of the PostgreSQL container. It's empty in the status when an
+	// external database is used.
+	Postgres string `json:"postgres,omitempty"`
+
+	// Backup is the image of the backup CronJobs. It's empty in the status when there are no
+	// backups.
+	Backup string `json:"backup,omitempty"`
+}
+
+// UnifiedPushServerConditionType is the type of a UnifiedPushServerCondition
+type UnifiedPushServerConditionType string
+
+const (
+	// ConditionAvailable is True when all of the components are ready
+	ConditionAvailable UnifiedPushServerConditionType = "Available"
+	// ConditionProgressing is True while the operator is waiting for a component to be ready
+	ConditionProgressing UnifiedPushServerConditionType = "Progressing"
+	// ConditionDegraded is True when the last reconcile failed
+	ConditionDegraded UnifiedPushServerConditionType = "Degraded"
+	// ConditionDatabaseReady is True when the PostgreSQL database is ready, or an external one is used
+	ConditionDatabaseReady UnifiedPushServerConditionType = "DatabaseReady"
+	// ConditionMessageBrokerReady is True when the AddressSpace, MessagingUser and addresses are
+	// ready. It's only set when UseMessageBroker is true.
+	ConditionMessageBrokerReady UnifiedPushServerConditionType = "MessageBrokerReady"
+	// ConditionRouteAdmitted is True when the Route of the UnifiedPush Server has been admitted
+	ConditionRouteAdmitted UnifiedPushServerConditionType = "RouteAdmitted"
+	// ConditionIngressReady is True when the Ingress of the UnifiedPush Server has been given an
+	// address by the ingress controller. It's used instead of ConditionRouteAdmitted on clusters
+	// without Routes.
+	ConditionIngressReady UnifiedPushServerConditionType = "IngressReady"
+	// ConditionBackupsConfigured is True when the CronJobs for the requested backups are in place
+	ConditionBackupsConfigured UnifiedPushServerConditionType = "BackupsConfigured"
+)
+
+// UnifiedPushServerCondition describes one aspect of the state of a UnifiedPushServer
+// +k8s:openapi-gen=true
+type UnifiedPushServerCondition struct {
+	// Type of the condition
+	Type UnifiedPushServerConditionType `json:"type"`
+
+	// Status of the condition, one of True, False or Unknown
+	Status corev1.ConditionStatus `json:"status"`
+
+	// Reason is a CamelCase reason for the last transition of the condition
+	Reason string `json:"reason,omitempty"`
+
+	// Message is a human-readable message with details about the last transition
+	Message string `json:"message,omitempty"`
+
+	// LastTransitionTime is the last time that the status of the condition changed
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -82,6 +296,12 @@ type UnifiedPushServerStatus struct {
 // +kubebuilder:resource:path=unifiedpushservers,shortName=ups
 // +kubebuilder:singular=unifiedpushserver
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="Whether the CR is reconciling or failing"
+// +kubebuilder:printcolumn:name="Ready",type="boolean",JSONPath=".status.ready",description="Whether all of the resources are ready"
+// +kubebuilder:printcolumn:name="Console",type="string",JSONPath=".status.adminConsoleURL",description="URL of the admin console"
+// +kubebuilder:printcolumn:name="Image",type="string",JSONPath=".status.images.unifiedPush",description="Image of the UnifiedPush Server",priority=1
+// +kubebuilder:printcolumn:name="Service",type="string",JSONPath=".status.internalServiceURL",description="In-cluster URL of the REST API",priority=1
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 type UnifiedPushServer struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -129,6 +349,37 @@ type UnifiedPushServerBackup struct {
 	BackendSecretNamespace string `json:"backendSecretNamespace,omitempty"`
 }
 
+// UnifiedPushServerAutoscaling configures the HorizontalPodAutoscaler of the UnifiedPush Server
+// +k8s:openapi-gen=true
+type UnifiedPushServerAutoscaling struct {
+	// MinReplicas is the number of pods that the Deployment is never scaled below. Defaults
+	// to Replicas, or 1.
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// MaxReplicas is the number of pods that the Deployment is never scaled above
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// TargetCPUUtilizationPercentage is the average CPU usage of the pods, as a percentage of
+	// the requested CPU, that the autoscaler aims for. Defaults to 80.
+	TargetCPUUtilizationPercentage *int32 `json:"targetCPUUtilizationPercentage,omitempty"`
+
+	// CustomMetric is an optional per-pod metric that the autoscaler scales on as well, e.g.
+	// the rate of aerogear_ups_push_requests_total exposed through the custom metrics API.
+	CustomMetric *UnifiedPushServerCustomMetric `json:"customMetric,omitempty"`
+}
+
+// UnifiedPushServerCustomMetric is a metric from the custom metrics API that the UnifiedPush
+// Server is scaled on
+// +k8s:openapi-gen=true
+type UnifiedPushServerCustomMetric struct {
+	// Name is the name of the metric in the custom metrics API
+	Name string `json:"name"`
+
+	// TargetAverageValue is the average value of the metric across the pods that the
+	// autoscaler aims for
+	TargetAverageValue resource.Quantity `json:"targetAverageValue"`
+}
+
 // UnifiedPushServerDatabase contains the data needed to connect to external database
 type UnifiedPushServerDatabase struct {
 	//Name for external database support
@@ -150,6 +401,8 @@ var (
 	PhaseFailing      StatusPhase = "Failing"
 	PhaseReconciling  StatusPhase = "Reconciling"
 	PhaseInitializing StatusPhase = "Initializing"
+	PhaseComplete     StatusPhase = "Complete"
+	PhaseSending      StatusPhase = "Sending"
 )
 
 func init() {
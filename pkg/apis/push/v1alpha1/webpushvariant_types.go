@@ -0,0 +1,96 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WebPushVariantSpec defines the desired state of WebPushVariant
+// +k8s:openapi-gen=true
+type WebPushVariantSpec struct {
+	// Name is the name of the variant in UPS. Defaults to the name
+	// of the CR.
+	Name string `json:"name,omitempty"`
+
+	// Description is a human friendly description for the variant.
+	Description string `json:"description,omitempty"`
+
+	// PushApplication is the name of the PushApplication CR, in the
+	// same namespace, that this variant will be added to
+	PushApplication string `json:"pushApplication"`
+
+	// Alias is a "mailto:" address or URL that push services can use
+	// to contact the sender of the notifications
+	Alias string `json:"alias"`
+
+	// CredentialsSecret is the name of a Secret, in the same
+	// namespace, holding an existing VAPID key pair for the variant.
+	// It must contain the following keys:
+	//
+	// publicKey: the URL-safe base64 encoded public key
+	// privateKey: the URL-safe base64 encoded private key
+	//
+	// If it is not set, a new key pair will be generated and stored
+	// in a Secret owned by the WebPushVariant.
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+}
+
+// WebPushVariantStatus defines the observed state of WebPushVariant
+// +k8s:openapi-gen=true
+type WebPushVariantStatus struct {
+	// Phase indicates whether the CR is reconciling(good), failing(bad), or initializing.
+	Phase StatusPhase `json:"phase"`
+
+	// Message is a more human-readable message indicating details about current phase or error.
+	Message string `json:"message,omitempty"`
+
+	// PushApplicationId is the id of the application in UPS that the
+	// variant was created in
+	PushApplicationId string `json:"pushApplicationId,omitempty"`
+
+	// VariantId is the id of the variant in UPS
+	VariantId string `json:"variantId,omitempty"`
+
+	// Secret is the variant secret, used by devices to register
+	// with the variant
+	Secret string `json:"secret,omitempty"`
+
+	// PublicKey is the VAPID public key of the variant, which web
+	// frontends need in order to subscribe to push notifications
+	PublicKey string `json:"publicKey,omitempty"`
+
+	// CredentialsSecret is the name of the Secret holding the VAPID
+	// key pair in use, whether it was generated or not
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+
+	// CredentialsHash is a hash of the private key that was last sent
+	// to UPS, which is used to tell whether it has changed since
+	CredentialsHash string `json:"credentialsHash,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// WebPushVariant is the Schema for the webpushvariants API
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:path=webpushvariants
+// +kubebuilder:singular=webpushvariant
+// +kubebuilder:subresource:status
+type WebPushVariant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   WebPushVariantSpec   `json:"spec,omitempty"`
+	Status WebPushVariantStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// WebPushVariantList contains a list of WebPushVariant
+type WebPushVariantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []WebPushVariant `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&WebPushVariant{}, &WebPushVariantList{})
+}
@@ -9,6 +9,512 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AndroidVariant) DeepCopyInto(out *AndroidVariant) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	out.Status = in.Status
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AndroidVariant.
+func (in *AndroidVariant) DeepCopy() *AndroidVariant {
+	if in == nil {
+		return nil
+	}
+	out := new(AndroidVariant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AndroidVariant) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AndroidVariantList) DeepCopyInto(out *AndroidVariantList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AndroidVariant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AndroidVariantList.
+func (in *AndroidVariantList) DeepCopy() *AndroidVariantList {
+	if in == nil {
+		return nil
+	}
+	out := new(AndroidVariantList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AndroidVariantList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AndroidVariantSpec) DeepCopyInto(out *AndroidVariantSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AndroidVariantSpec.
+func (in *AndroidVariantSpec) DeepCopy() *AndroidVariantSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AndroidVariantSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AndroidVariantStatus) DeepCopyInto(out *AndroidVariantStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AndroidVariantStatus.
+func (in *AndroidVariantStatus) DeepCopy() *AndroidVariantStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AndroidVariantStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceImport) DeepCopyInto(out *DeviceImport) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceImport.
+func (in *DeviceImport) DeepCopy() *DeviceImport {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceImport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DeviceImport) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceImportList) DeepCopyInto(out *DeviceImportList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DeviceImport, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceImportList.
+func (in *DeviceImportList) DeepCopy() *DeviceImportList {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceImportList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DeviceImportList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceImportSpec) DeepCopyInto(out *DeviceImportSpec) {
+	*out = *in
+	out.Variant = in.Variant
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceImportSpec.
+func (in *DeviceImportSpec) DeepCopy() *DeviceImportSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceImportSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceImportStatus) DeepCopyInto(out *DeviceImportStatus) {
+	*out = *in
+	if in.CompletedAt != nil {
+		in, out := &in.CompletedAt, &out.CompletedAt
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceImportStatus.
+func (in *DeviceImportStatus) DeepCopy() *DeviceImportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceImportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IOSTokenVariant) DeepCopyInto(out *IOSTokenVariant) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	out.Status = in.Status
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IOSTokenVariant.
+func (in *IOSTokenVariant) DeepCopy() *IOSTokenVariant {
+	if in == nil {
+		return nil
+	}
+	out := new(IOSTokenVariant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IOSTokenVariant) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IOSTokenVariantList) DeepCopyInto(out *IOSTokenVariantList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]IOSTokenVariant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IOSTokenVariantList.
+func (in *IOSTokenVariantList) DeepCopy() *IOSTokenVariantList {
+	if in == nil {
+		return nil
+	}
+	out := new(IOSTokenVariantList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IOSTokenVariantList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IOSTokenVariantSpec) DeepCopyInto(out *IOSTokenVariantSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IOSTokenVariantSpec.
+func (in *IOSTokenVariantSpec) DeepCopy() *IOSTokenVariantSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IOSTokenVariantSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IOSTokenVariantStatus) DeepCopyInto(out *IOSTokenVariantStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IOSTokenVariantStatus.
+func (in *IOSTokenVariantStatus) DeepCopy() *IOSTokenVariantStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IOSTokenVariantStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PushApplication) DeepCopyInto(out *PushApplication) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	out.Status = in.Status
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushApplication.
+func (in *PushApplication) DeepCopy() *PushApplication {
+	if in == nil {
+		return nil
+	}
+	out := new(PushApplication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PushApplication) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PushApplicationList) DeepCopyInto(out *PushApplicationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PushApplication, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushApplicationList.
+func (in *PushApplicationList) DeepCopy() *PushApplicationList {
+	if in == nil {
+		return nil
+	}
+	out := new(PushApplicationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PushApplicationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PushApplicationSpec) DeepCopyInto(out *PushApplicationSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushApplicationSpec.
+func (in *PushApplicationSpec) DeepCopy() *PushApplicationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PushApplicationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PushApplicationStatus) DeepCopyInto(out *PushApplicationStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushApplicationStatus.
+func (in *PushApplicationStatus) DeepCopy() *PushApplicationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PushApplicationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PushMessage) DeepCopyInto(out *PushMessage) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushMessage.
+func (in *PushMessage) DeepCopy() *PushMessage {
+	if in == nil {
+		return nil
+	}
+	out := new(PushMessage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PushMessage) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PushMessageList) DeepCopyInto(out *PushMessageList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PushMessage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushMessageList.
+func (in *PushMessageList) DeepCopy() *PushMessageList {
+	if in == nil {
+		return nil
+	}
+	out := new(PushMessageList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PushMessageList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PushMessageSpec) DeepCopyInto(out *PushMessageSpec) {
+	*out = *in
+	if in.Data != nil {
+		in, out := &in.Data, &out.Data
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Aliases != nil {
+		in, out := &in.Aliases, &out.Aliases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Categories != nil {
+		in, out := &in.Categories, &out.Categories
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Variants != nil {
+		in, out := &in.Variants, &out.Variants
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushMessageSpec.
+func (in *PushMessageSpec) DeepCopy() *PushMessageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PushMessageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PushMessageStatus) DeepCopyInto(out *PushMessageStatus) {
+	*out = *in
+	if in.SentAt != nil {
+		in, out := &in.SentAt, &out.SentAt
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushMessageStatus.
+func (in *PushMessageStatus) DeepCopy() *PushMessageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PushMessageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UnifiedPushServer) DeepCopyInto(out *UnifiedPushServer) {
 	*out = *in
@@ -37,6 +543,63 @@ func (in *UnifiedPushServer) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UnifiedPushServerAuth) DeepCopyInto(out *UnifiedPushServerAuth) {
+	*out = *in
+	if in.OpenShift != nil {
+		in, out := &in.OpenShift, &out.OpenShift
+		*out = new(UnifiedPushServerOpenShiftAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OIDC != nil {
+		in, out := &in.OIDC, &out.OIDC
+		*out = new(UnifiedPushServerOIDC)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnifiedPushServerAuth.
+func (in *UnifiedPushServerAuth) DeepCopy() *UnifiedPushServerAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(UnifiedPushServerAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UnifiedPushServerAutoscaling) DeepCopyInto(out *UnifiedPushServerAutoscaling) {
+	*out = *in
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetCPUUtilizationPercentage != nil {
+		in, out := &in.TargetCPUUtilizationPercentage, &out.TargetCPUUtilizationPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CustomMetric != nil {
+		in, out := &in.CustomMetric, &out.CustomMetric
+		*out = new(UnifiedPushServerCustomMetric)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnifiedPushServerAutoscaling.
+func (in *UnifiedPushServerAutoscaling) DeepCopy() *UnifiedPushServerAutoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(UnifiedPushServerAutoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UnifiedPushServerBackup) DeepCopyInto(out *UnifiedPushServerBackup) {
 	*out = *in
@@ -53,6 +616,40 @@ func (in *UnifiedPushServerBackup) DeepCopy() *UnifiedPushServerBackup {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UnifiedPushServerCondition) DeepCopyInto(out *UnifiedPushServerCondition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnifiedPushServerCondition.
+func (in *UnifiedPushServerCondition) DeepCopy() *UnifiedPushServerCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(UnifiedPushServerCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UnifiedPushServerCustomMetric) DeepCopyInto(out *UnifiedPushServerCustomMetric) {
+	*out = *in
+	out.TargetAverageValue = in.TargetAverageValue.DeepCopy()
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnifiedPushServerCustomMetric.
+func (in *UnifiedPushServerCustomMetric) DeepCopy() *UnifiedPushServerCustomMetric {
+	if in == nil {
+		return nil
+	}
+	out := new(UnifiedPushServerCustomMetric)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UnifiedPushServerDatabase) DeepCopyInto(out *UnifiedPushServerDatabase) {
 	*out = *in
@@ -70,6 +667,38 @@ func (in *UnifiedPushServerDatabase) DeepCopy() *UnifiedPushServerDatabase {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UnifiedPushServerImages) DeepCopyInto(out *UnifiedPushServerImages) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnifiedPushServerImages.
+func (in *UnifiedPushServerImages) DeepCopy() *UnifiedPushServerImages {
+	if in == nil {
+		return nil
+	}
+	out := new(UnifiedPushServerImages)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UnifiedPushServerIngress) DeepCopyInto(out *UnifiedPushServerIngress) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnifiedPushServerIngress.
+func (in *UnifiedPushServerIngress) DeepCopy() *UnifiedPushServerIngress {
+	if in == nil {
+		return nil
+	}
+	out := new(UnifiedPushServerIngress)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UnifiedPushServerList) DeepCopyInto(out *UnifiedPushServerList) {
 	*out = *in
@@ -103,6 +732,53 @@ func (in *UnifiedPushServerList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UnifiedPushServerOIDC) DeepCopyInto(out *UnifiedPushServerOIDC) {
+	*out = *in
+	if in.AllowedGroups != nil {
+		in, out := &in.AllowedGroups, &out.AllowedGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnifiedPushServerOIDC.
+func (in *UnifiedPushServerOIDC) DeepCopy() *UnifiedPushServerOIDC {
+	if in == nil {
+		return nil
+	}
+	out := new(UnifiedPushServerOIDC)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UnifiedPushServerOpenShiftAuth) DeepCopyInto(out *UnifiedPushServerOpenShiftAuth) {
+	*out = *in
+	if in.SubjectAccessReview != nil {
+		in, out := &in.SubjectAccessReview, &out.SubjectAccessReview
+		*out = new(UnifiedPushServerSubjectAccessReview)
+		**out = **in
+	}
+	if in.AllowedGroups != nil {
+		in, out := &in.AllowedGroups, &out.AllowedGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnifiedPushServerOpenShiftAuth.
+func (in *UnifiedPushServerOpenShiftAuth) DeepCopy() *UnifiedPushServerOpenShiftAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(UnifiedPushServerOpenShiftAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UnifiedPushServerSpec) DeepCopyInto(out *UnifiedPushServerSpec) {
 	*out = *in
@@ -127,6 +803,37 @@ func (in *UnifiedPushServerSpec) DeepCopyInto(out *UnifiedPushServerSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(UnifiedPushServerAutoscaling)
+		(*in).DeepCopyInto(*out)
+	}
+	out.Images = in.Images
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(UnifiedPushServerIngress)
+		**out = **in
+	}
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(UnifiedPushServerAuth)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -163,6 +870,18 @@ func (in *UnifiedPushServerStatus) DeepCopyInto(out *UnifiedPushServerStatus) {
 			(*out)[key] = outVal
 		}
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]UnifiedPushServerCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.Images = in.Images
+	if in.LastCredentialRotation != nil {
+		in, out := &in.LastCredentialRotation, &out.LastCredentialRotation
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
@@ -175,3 +894,128 @@ func (in *UnifiedPushServerStatus) DeepCopy() *UnifiedPushServerStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UnifiedPushServerSubjectAccessReview) DeepCopyInto(out *UnifiedPushServerSubjectAccessReview) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnifiedPushServerSubjectAccessReview.
+func (in *UnifiedPushServerSubjectAccessReview) DeepCopy() *UnifiedPushServerSubjectAccessReview {
+	if in == nil {
+		return nil
+	}
+	out := new(UnifiedPushServerSubjectAccessReview)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VariantReference) DeepCopyInto(out *VariantReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VariantReference.
+func (in *VariantReference) DeepCopy() *VariantReference {
+	if in == nil {
+		return nil
+	}
+	out := new(VariantReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebPushVariant) DeepCopyInto(out *WebPushVariant) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	out.Status = in.Status
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebPushVariant.
+func (in *WebPushVariant) DeepCopy() *WebPushVariant {
+	if in == nil {
+		return nil
+	}
+	out := new(WebPushVariant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WebPushVariant) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebPushVariantList) DeepCopyInto(out *WebPushVariantList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]WebPushVariant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebPushVariantList.
+func (in *WebPushVariantList) DeepCopy() *WebPushVariantList {
+	if in == nil {
+		return nil
+	}
+	out := new(WebPushVariantList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WebPushVariantList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebPushVariantSpec) DeepCopyInto(out *WebPushVariantSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebPushVariantSpec.
+func (in *WebPushVariantSpec) DeepCopy() *WebPushVariantSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WebPushVariantSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebPushVariantStatus) DeepCopyInto(out *WebPushVariantStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebPushVariantStatus.
+func (in *WebPushVariantStatus) DeepCopy() *WebPushVariantStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WebPushVariantStatus)
+	in.DeepCopyInto(out)
+	return out
+}
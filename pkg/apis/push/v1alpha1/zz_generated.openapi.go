@@ -11,9 +11,774 @@ import (
 
 func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
 	return map[string]common.OpenAPIDefinition{
-		"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServer":       schema_pkg_apis_push_v1alpha1_UnifiedPushServer(ref),
-		"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerSpec":   schema_pkg_apis_push_v1alpha1_UnifiedPushServerSpec(ref),
-		"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerStatus": schema_pkg_apis_push_v1alpha1_UnifiedPushServerStatus(ref),
+		"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.AndroidVariant":                       schema_pkg_apis_push_v1alpha1_AndroidVariant(ref),
+		"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.AndroidVariantSpec":                   schema_pkg_apis_push_v1alpha1_AndroidVariantSpec(ref),
+		"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.AndroidVariantStatus":                 schema_pkg_apis_push_v1alpha1_AndroidVariantStatus(ref),
+		"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.DeviceImport":                         schema_pkg_apis_push_v1alpha1_DeviceImport(ref),
+		"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.DeviceImportSpec":                     schema_pkg_apis_push_v1alpha1_DeviceImportSpec(ref),
+		"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.DeviceImportStatus":                   schema_pkg_apis_push_v1alpha1_DeviceImportStatus(ref),
+		"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.IOSTokenVariant":                      schema_pkg_apis_push_v1alpha1_IOSTokenVariant(ref),
+		"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.IOSTokenVariantSpec":                  schema_pkg_apis_push_v1alpha1_IOSTokenVariantSpec(ref),
+		"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.IOSTokenVariantStatus":                schema_pkg_apis_push_v1alpha1_IOSTokenVariantStatus(ref),
+		"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.PushApplication":                      schema_pkg_apis_push_v1alpha1_PushApplication(ref),
+		"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.PushApplicationSpec":                  schema_pkg_apis_push_v1alpha1_PushApplicationSpec(ref),
+		"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.PushApplicationStatus":                schema_pkg_apis_push_v1alpha1_PushApplicationStatus(ref),
+		"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.PushMessage":                          schema_pkg_apis_push_v1alpha1_PushMessage(ref),
+		"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.PushMessageSpec":                      schema_pkg_apis_push_v1alpha1_PushMessageSpec(ref),
+		"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.PushMessageStatus":                    schema_pkg_apis_push_v1alpha1_PushMessageStatus(ref),
+		"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServer":                    schema_pkg_apis_push_v1alpha1_UnifiedPushServer(ref),
+		"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerAuth":                schema_pkg_apis_push_v1alpha1_UnifiedPushServerAuth(ref),
+		"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerAutoscaling":         schema_pkg_apis_push_v1alpha1_UnifiedPushServerAutoscaling(ref),
+		"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerCondition":           schema_pkg_apis_push_v1alpha1_UnifiedPushServerCondition(ref),
+		"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerCustomMetric":        schema_pkg_apis_push_v1alpha1_UnifiedPushServerCustomMetric(ref),
+		"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerImages":              schema_pkg_apis_push_v1alpha1_UnifiedPushServerImages(ref),
+		"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerIngress":             schema_pkg_apis_push_v1alpha1_UnifiedPushServerIngress(ref),
+		"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerOIDC":                schema_pkg_apis_push_v1alpha1_UnifiedPushServerOIDC(ref),
+		"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerOpenShiftAuth":       schema_pkg_apis_push_v1alpha1_UnifiedPushServerOpenShiftAuth(ref),
+		"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerSpec":                schema_pkg_apis_push_v1alpha1_UnifiedPushServerSpec(ref),
+		"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerStatus":              schema_pkg_apis_push_v1alpha1_UnifiedPushServerStatus(ref),
+		"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerSubjectAccessReview": schema_pkg_apis_push_v1alpha1_UnifiedPushServerSubjectAccessReview(ref),
+		"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.WebPushVariant":                       schema_pkg_apis_push_v1alpha1_WebPushVariant(ref),
+		"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.WebPushVariantSpec":                   schema_pkg_apis_push_v1alpha1_WebPushVariantSpec(ref),
+		"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.WebPushVariantStatus":                 schema_pkg_apis_push_v1alpha1_WebPushVariantStatus(ref),
+	}
+}
+
+func schema_pkg_apis_push_v1alpha1_AndroidVariant(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "AndroidVariant is the Schema for the androidvariants API",
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.AndroidVariantSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.AndroidVariantStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.AndroidVariantSpec", "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.AndroidVariantStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_push_v1alpha1_AndroidVariantSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "AndroidVariantSpec defines the desired state of AndroidVariant",
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name is the name of the variant in UPS. Defaults to the name of the CR.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"description": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Description is a human friendly description for the variant.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"pushApplication": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PushApplication is the name of the PushApplication CR, in the same namespace, that this variant will be added to",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"credentialsSecret": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CredentialsSecret is the name of a Secret, in the same namespace, holding the FCM credentials of the variant. It must contain the following keys:\n\nserverKey: the FCM server key senderId: the FCM sender id",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"pushApplication", "credentialsSecret"},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
+func schema_pkg_apis_push_v1alpha1_AndroidVariantStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "AndroidVariantStatus defines the observed state of AndroidVariant",
+				Properties: map[string]spec.Schema{
+					"phase": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Phase indicates whether the CR is reconciling(good), failing(bad), or initializing.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Message is a more human-readable message indicating details about current phase or error.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"pushApplicationId": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PushApplicationId is the id of the application in UPS that the variant was created in",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"variantId": {
+						SchemaProps: spec.SchemaProps{
+							Description: "VariantId is the id of the variant in UPS",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"secret": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Secret is the variant secret, used by devices to register with the variant",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"phase"},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
+func schema_pkg_apis_push_v1alpha1_DeviceImport(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DeviceImport is the Schema for the deviceimports API",
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.DeviceImportSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.DeviceImportStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.DeviceImportSpec", "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.DeviceImportStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_push_v1alpha1_DeviceImportSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DeviceImportSpec defines the desired state of DeviceImport",
+				Properties: map[string]spec.Schema{
+					"variant": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Variant is the variant CR, in the same namespace, that the devices will be registered with",
+							Ref:         ref("github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.VariantReference"),
+						},
+					},
+					"configMapKeyRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ConfigMapKeyRef selects a key of a ConfigMap holding a JSON array of installations, e.g.\n\n[{\"deviceToken\": \"abc...\", \"alias\": \"alice\", \"categories\": [\"news\"]}]\n\nOnly one of ConfigMapKeyRef or SecretKeyRef should be set.",
+							Ref:         ref("k8s.io/api/core/v1.ConfigMapKeySelector"),
+						},
+					},
+					"secretKeyRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SecretKeyRef selects a key of a Secret holding a JSON array of installations, in the same format as ConfigMapKeyRef.",
+							Ref:         ref("k8s.io/api/core/v1.SecretKeySelector"),
+						},
+					},
+					"batchSize": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BatchSize is the number of installations sent to UPS in each request. Defaults to 1000.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+				Required: []string{"variant"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.VariantReference", "k8s.io/api/core/v1.ConfigMapKeySelector", "k8s.io/api/core/v1.SecretKeySelector"},
+	}
+}
+
+func schema_pkg_apis_push_v1alpha1_DeviceImportStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DeviceImportStatus defines the observed state of DeviceImport",
+				Properties: map[string]spec.Schema{
+					"phase": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Phase indicates whether the import is waiting to start(initializing), in progress(reconciling), failing(bad), or done(complete).",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Message is a more human-readable message indicating details about current phase or error.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"imported": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Imported is the number of installations accepted by UPS",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"failed": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Failed is the number of installations in batches that UPS returned an error for",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"rejected": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Rejected is the number of installations that were not sent to UPS because they aren't valid, e.g. they have no deviceToken",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"completedAt": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CompletedAt is the time at which all of the installations had been processed. A completed import is never run again.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+				Required: []string{"phase", "imported", "failed", "rejected"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_push_v1alpha1_IOSTokenVariant(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "IOSTokenVariant is the Schema for the iostokenvariants API",
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.IOSTokenVariantSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.IOSTokenVariantStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.IOSTokenVariantSpec", "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.IOSTokenVariantStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_push_v1alpha1_IOSTokenVariantSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "IOSTokenVariantSpec defines the desired state of IOSTokenVariant",
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name is the name of the variant in UPS. Defaults to the name of the CR.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"description": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Description is a human friendly description for the variant.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"pushApplication": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PushApplication is the name of the PushApplication CR, in the same namespace, that this variant will be added to",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"credentialsSecret": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CredentialsSecret is the name of a Secret, in the same namespace, holding the APNs token credentials of the variant. It must contain the following keys:\n\nprivateKey: the contents of the .p8 private key file keyId: the id of the private key teamId: the Apple developer team id bundleId: the bundle id of the app\n\nIt can optionally contain \"production: true\" to use the production APNs servers instead of the development ones.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"pushApplication", "credentialsSecret"},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
+func schema_pkg_apis_push_v1alpha1_IOSTokenVariantStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "IOSTokenVariantStatus defines the observed state of IOSTokenVariant",
+				Properties: map[string]spec.Schema{
+					"phase": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Phase indicates whether the CR is reconciling(good), failing(bad), or initializing.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Message is a more human-readable message indicating details about current phase or error.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"pushApplicationId": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PushApplicationId is the id of the application in UPS that the variant was created in",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"variantId": {
+						SchemaProps: spec.SchemaProps{
+							Description: "VariantId is the id of the variant in UPS",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"secret": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Secret is the variant secret, used by devices to register with the variant",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"credentialsHash": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CredentialsHash is a hash of the private key that was last sent to UPS, which is used to tell whether it has changed since",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"phase"},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
+func schema_pkg_apis_push_v1alpha1_PushApplication(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PushApplication is the Schema for the pushapplications API",
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.PushApplicationSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.PushApplicationStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.PushApplicationSpec", "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.PushApplicationStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_push_v1alpha1_PushApplicationSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PushApplicationSpec defines the desired state of PushApplication",
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name is the name of the application in UPS. Defaults to the name of the CR.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"unifiedPushServer": {
+						SchemaProps: spec.SchemaProps{
+							Description: "UnifiedPushServer is the name of the UnifiedPushServer CR, in the same namespace, that this PushApplication will be registered with",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"description": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Description is a description of the app to be displayed in the UnifiedPush Server admin UI",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"unifiedPushServer"},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
+func schema_pkg_apis_push_v1alpha1_PushApplicationStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PushApplicationStatus defines the observed state of PushApplication",
+				Properties: map[string]spec.Schema{
+					"phase": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Phase indicates whether the CR is reconciling(good), failing(bad), or initializing.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Message is a more human-readable message indicating details about current phase or error.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"pushApplicationId": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PushApplicationId is an identifer used to register Variants with this PushApplication",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"secretName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SecretName is the name of the Secret holding the pushApplicationID and masterSecret of this PushApplication",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"mobileServicesSecret": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MobileServicesSecret is the name of the Secret holding the mobile-services.json client configuration for the variants of this PushApplication",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"phase"},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
+func schema_pkg_apis_push_v1alpha1_PushMessage(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PushMessage is the Schema for the pushmessages API",
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.PushMessageSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.PushMessageStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.PushMessageSpec", "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.PushMessageStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_push_v1alpha1_PushMessageSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PushMessageSpec defines the desired state of PushMessage",
+				Properties: map[string]spec.Schema{
+					"pushApplication": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PushApplication is the name of the PushApplication CR, in the same namespace, whose devices the message will be sent to",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"alert": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Alert is the text of the notification",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"sound": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Sound is the name of a sound file to play on the device",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"badge": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Badge is the number to show on the app icon, on platforms that support it",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"data": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Data is arbitrary data delivered to the app along with the notification",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"aliases": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Aliases restricts the message to the devices registered with one of these aliases",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"categories": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Categories restricts the message to the devices registered with one of these categories",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"variants": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Variants restricts the message to the devices of the variants with these ids",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"ttl": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TTL is the number of seconds that the push network should keep trying to deliver the message for",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+				Required: []string{"pushApplication"},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
+func schema_pkg_apis_push_v1alpha1_PushMessageStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PushMessageStatus defines the observed state of PushMessage",
+				Properties: map[string]spec.Schema{
+					"phase": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Phase indicates whether the message is waiting to be sent(initializing), being sent(sending), failing(bad), or has been sent(complete). A message left sending may have been accepted by UPS, so it isn't sent again either.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Message is a more human-readable message indicating details about current phase or error.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"delivered": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Delivered is true once the message has been accepted by UPS. A delivered message is never sent again.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"sentAt": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SentAt is the time at which the message was accepted by UPS",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"response": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Response is the body of the response from UPS",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"phase"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
 	}
 }
 
@@ -39,24 +804,327 @@ func schema_pkg_apis_push_v1alpha1_UnifiedPushServer(ref common.ReferenceCallbac
 					},
 					"metadata": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerSpec", "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_push_v1alpha1_UnifiedPushServerAuth(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "UnifiedPushServerAuth configures the authentication of the admin console",
+				Properties: map[string]spec.Schema{
+					"openshift": {
+						SchemaProps: spec.SchemaProps{
+							Description: "OpenShift restricts which of the users that log in with the OpenShift OAuth server can open the admin console. All of them can when it's not set.",
+							Ref:         ref("github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerOpenShiftAuth"),
+						},
+					},
+					"oidc": {
+						SchemaProps: spec.SchemaProps{
+							Description: "OIDC has users log in with an OpenID Connect provider, e.g. Keycloak, instead of the OpenShift OAuth server",
+							Ref:         ref("github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerOIDC"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerOIDC", "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerOpenShiftAuth"},
+	}
+}
+
+func schema_pkg_apis_push_v1alpha1_UnifiedPushServerAutoscaling(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "UnifiedPushServerAutoscaling configures the HorizontalPodAutoscaler of the UnifiedPush Server",
+				Properties: map[string]spec.Schema{
+					"minReplicas": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MinReplicas is the number of pods that the Deployment is never scaled below. Defaults to Replicas, or 1.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"maxReplicas": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MaxReplicas is the number of pods that the Deployment is never scaled above",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"targetCPUUtilizationPercentage": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TargetCPUUtilizationPercentage is the average CPU usage of the pods, as a percentage of the requested CPU, that the autoscaler aims for. Defaults to 80.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"customMetric": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CustomMetric is an optional per-pod metric that the autoscaler scales on as well, e.g. the rate of aerogear_ups_push_requests_total exposed through the custom metrics API.",
+							Ref:         ref("github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerCustomMetric"),
+						},
+					},
+				},
+				Required: []string{"maxReplicas"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerCustomMetric"},
+	}
+}
+
+func schema_pkg_apis_push_v1alpha1_UnifiedPushServerCondition(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "UnifiedPushServerCondition describes one aspect of the state of a UnifiedPushServer",
+				Properties: map[string]spec.Schema{
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Type of the condition",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Status of the condition, one of True, False or Unknown",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"reason": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Reason is a CamelCase reason for the last transition of the condition",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Message is a human-readable message with details about the last transition",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"lastTransitionTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastTransitionTime is the last time that the status of the condition changed",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+				Required: []string{"type", "status"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_push_v1alpha1_UnifiedPushServerCustomMetric(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "UnifiedPushServerCustomMetric is a metric from the custom metrics API that the UnifiedPush Server is scaled on",
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name is the name of the metric in the custom metrics API",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"targetAverageValue": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TargetAverageValue is the average value of the metric across the pods that the autoscaler aims for",
+							Ref:         ref("k8s.io/apimachinery/pkg/api/resource.Quantity"),
+						},
+					},
+				},
+				Required: []string{"name", "targetAverageValue"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/api/resource.Quantity"},
+	}
+}
+
+func schema_pkg_apis_push_v1alpha1_UnifiedPushServerImages(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "UnifiedPushServerImages are the container images used by a UnifiedPushServer",
+				Properties: map[string]spec.Schema{
+					"unifiedPush": {
+						SchemaProps: spec.SchemaProps{
+							Description: "UnifiedPush is the image of the UnifiedPush Server container",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"oauthProxy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "OAuthProxy is the image of the OAuth proxy container",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"postgres": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Postgres is the image of the PostgreSQL container. It's empty in the status when an external database is used.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"backup": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Backup is the image of the backup CronJobs. It's empty in the status when there are no backups.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
+func schema_pkg_apis_push_v1alpha1_UnifiedPushServerIngress(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "UnifiedPushServerIngress configures the Ingress of a UnifiedPushServer",
+				Properties: map[string]spec.Schema{
+					"className": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ClassName is the class of the ingress controller that serves the Ingress, set in the kubernetes.io/ingress.class annotation. The default one is used when it's not set.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"host": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Host is the host name that the admin console is served on. The Ingress matches all hosts when it's not set.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"tlsSecretName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TLSSecretName is the name of a Secret with the TLS certificate and key of the host. The admin console is served over plain HTTP when it's not set.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
+func schema_pkg_apis_push_v1alpha1_UnifiedPushServerOIDC(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "UnifiedPushServerOIDC configures an OpenID Connect provider for the admin console",
+				Properties: map[string]spec.Schema{
+					"issuerURL": {
+						SchemaProps: spec.SchemaProps{
+							Description: "IssuerURL is the URL of the OpenID Connect issuer, e.g. https://keycloak.example.com/auth/realms/push",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"clientSecret": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ClientSecret is the name of a Secret with the client-id and client-secret of the OpenID Connect client",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"allowedGroups": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AllowedGroups restricts the admin console to the users in any of these groups, taken from the groups claim of their ID token. All authenticated users are allowed when it's empty.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"issuerURL", "clientSecret"},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
+func schema_pkg_apis_push_v1alpha1_UnifiedPushServerOpenShiftAuth(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "UnifiedPushServerOpenShiftAuth configures the access checks of the OpenShift OAuth proxy",
+				Properties: map[string]spec.Schema{
+					"subjectAccessReview": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SubjectAccessReview is the access that users need to open the admin console, or to call its API with a bearer token. It defaults to creating RoleBindings in the namespace, which only its admins can do, unless AllowedGroups is set.",
+							Ref:         ref("github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerSubjectAccessReview"),
 						},
 					},
-					"spec": {
+					"allowedGroups": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerSpec"),
+							Description: "AllowedGroups restricts the admin console to the users in any of these OpenShift groups. They don't need to be admins of the namespace, unless SubjectAccessReview is also set.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
 						},
 					},
-					"status": {
+					"bearerTokens": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerStatus"),
+							Description: "BearerTokens lets the API of the admin console be called with the OpenShift bearer token of a user that passes SubjectAccessReview. The ServiceAccount of the UnifiedPushServer needs the system:auth-delegator cluster role to check the tokens.",
+							Type:        []string{"boolean"},
+							Format:      "",
 						},
 					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerSpec", "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerSubjectAccessReview"},
 	}
 }
 
@@ -145,11 +1213,76 @@ func schema_pkg_apis_push_v1alpha1_UnifiedPushServerSpec(ref common.ReferenceCal
 							},
 						},
 					},
+					"replicas": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Replicas is the number of UnifiedPush Server pods. With more than one, the pods are updated one at a time, spread across nodes unless Affinity is set, and protected by a PodDisruptionBudget. Defaults to 1.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"minAvailable": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MinAvailable is the number of UnifiedPush Server pods that have to be available for the UnifiedPushServer to be ready, and that voluntary disruptions such as node drains have to leave running. Defaults to one less than Replicas, and to 1 for a single replica.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"autoscaling": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Autoscaling enables a HorizontalPodAutoscaler for the UnifiedPush Server Deployment, which then manages the number of replicas instead of Replicas.",
+							Ref:         ref("github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerAutoscaling"),
+						},
+					},
+					"images": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Images overrides the container images of this UnifiedPushServer. Both tags and digests are accepted, e.g. quay.io/aerogear/unifiedpush-configurable-container@sha256:... Images that aren't set default to the ones the operator was configured with.",
+							Ref:         ref("github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerImages"),
+						},
+					},
+					"imagePullPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ImagePullPolicy is the pull policy of all of the containers. Defaults to Always.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"imagePullSecrets": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ImagePullSecrets are the Secrets used to pull the images of all of the pods, e.g. for a private mirror registry",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/api/core/v1.LocalObjectReference"),
+									},
+								},
+							},
+						},
+					},
+					"credentialRotationDays": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CredentialRotationDays is the number of days after which the passwords of the PostgreSQL and AMQ users that are managed by the operator are rotated. Rotation is disabled when it's not set, but can still be requested with the push.aerogear.org/rotate-credentials annotation.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"ingress": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Ingress configures the Ingress that exposes the OAuth proxy on clusters without the route.openshift.io API, where it's created instead of a Route",
+							Ref:         ref("github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerIngress"),
+						},
+					},
+					"auth": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Auth configures how the proxy in front of the admin console authenticates users. They log in with the OpenShift OAuth server when it's not set.",
+							Ref:         ref("github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerAuth"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerBackup", "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerDatabase", "k8s.io/api/core/v1.Affinity", "k8s.io/api/core/v1.ResourceRequirements", "k8s.io/api/core/v1.Toleration"},
+			"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerAuth", "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerAutoscaling", "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerBackup", "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerDatabase", "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerImages", "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerIngress", "k8s.io/api/core/v1.Affinity", "k8s.io/api/core/v1.LocalObjectReference", "k8s.io/api/core/v1.ResourceRequirements", "k8s.io/api/core/v1.Toleration"},
 	}
 }
 
@@ -201,6 +1334,264 @@ func schema_pkg_apis_push_v1alpha1_UnifiedPushServerStatus(ref common.ReferenceC
 							},
 						},
 					},
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Conditions describe the state of the UnifiedPushServer as a whole (Available, Progressing and Degraded) and of each of its components (DatabaseReady, MessageBrokerReady, RouteAdmitted or IngressReady and BackupsConfigured), so that it's possible to tell which part is at fault when something is wrong.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerCondition"),
+									},
+								},
+							},
+						},
+					},
+					"adminConsoleURL": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AdminConsoleURL is the public URL of the UnifiedPush Server admin console, behind the OAuth proxy. It's empty until the Route has been admitted, or the Ingress has been given an address.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"internalServiceURL": {
+						SchemaProps: spec.SchemaProps{
+							Description: "InternalServiceURL is the in-cluster URL of the UnifiedPush Server REST API, which doesn't go through the OAuth proxy.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"images": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Images are the container images that are running for this CR",
+							Ref:         ref("github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerImages"),
+						},
+					},
+					"observedGeneration": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ObservedGeneration is the generation of the spec that was last reconciled successfully, with all of the components ready. Once it's equal to metadata.generation, all of the changes to the spec have been applied.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"lastCredentialRotation": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastCredentialRotation is the time at which the password of the PostgreSQL or AMQ user was last rotated",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+				Required: []string{"phase"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerCondition", "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.UnifiedPushServerImages", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_push_v1alpha1_UnifiedPushServerSubjectAccessReview(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "UnifiedPushServerSubjectAccessReview is an access check, passed to the OpenShift OAuth proxy in --openshift-sar and --openshift-delegate-urls",
+				Properties: map[string]spec.Schema{
+					"namespace": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Namespace of the resource. It defaults to the namespace of the UnifiedPushServer.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"group": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Group is the API group of the resource, empty for the core group",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"resource": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Resource is the plural name of the resource, e.g. pushapplications",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"resourceName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ResourceName is the name of a single resource, all of them when it's not set",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"verb": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Verb is the verb that users need to be allowed, e.g. update",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"resource", "verb"},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
+func schema_pkg_apis_push_v1alpha1_WebPushVariant(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "WebPushVariant is the Schema for the webpushvariants API",
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.WebPushVariantSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.WebPushVariantStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.WebPushVariantSpec", "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1.WebPushVariantStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_push_v1alpha1_WebPushVariantSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "WebPushVariantSpec defines the desired state of WebPushVariant",
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name is the name of the variant in UPS. Defaults to the name of the CR.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"description": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Description is a human friendly description for the variant.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"pushApplication": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PushApplication is the name of the PushApplication CR, in the same namespace, that this variant will be added to",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"alias": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Alias is a \"mailto:\" address or URL that push services can use to contact the sender of the notifications",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"credentialsSecret": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CredentialsSecret is the name of a Secret, in the same namespace, holding an existing VAPID key pair for the variant. It must contain the following keys:\n\npublicKey: the URL-safe base64 encoded public key privateKey: the URL-safe base64 encoded private key\n\nIf it is not set, a new key pair will be generated and stored in a Secret owned by the WebPushVariant.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"pushApplication", "alias"},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
+func schema_pkg_apis_push_v1alpha1_WebPushVariantStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "WebPushVariantStatus defines the observed state of WebPushVariant",
+				Properties: map[string]spec.Schema{
+					"phase": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Phase indicates whether the CR is reconciling(good), failing(bad), or initializing.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Message is a more human-readable message indicating details about current phase or error.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"pushApplicationId": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PushApplicationId is the id of the application in UPS that the variant was created in",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"variantId": {
+						SchemaProps: spec.SchemaProps{
+							Description: "VariantId is the id of the variant in UPS",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"secret": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Secret is the variant secret, used by devices to register with the variant",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"publicKey": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PublicKey is the VAPID public key of the variant, which web frontends need in order to subscribe to push notifications",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"credentialsSecret": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CredentialsSecret is the name of the Secret holding the VAPID key pair in use, whether it was generated or not",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"credentialsHash": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CredentialsHash is a hash of the private key that was last sent to UPS, which is used to tell whether it has changed since",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 				Required: []string{"phase"},
 			},
@@ -0,0 +1,197 @@
+// Package cache lets the operator watch several namespaces at once,
+// which the cache of this version of controller-runtime can't do: it
+// watches either a single namespace or all of them.
+package cache
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+var log = logf.Log.WithName("cache")
+
+// WatchNamespaces splits the value of WATCH_NAMESPACE into the
+// namespaces to watch. An empty value means all namespaces, which is
+// returned as nil.
+func WatchNamespaces(watchNamespace string) []string {
+	var namespaces []string
+	for _, namespace := range strings.Split(watchNamespace, ",") {
+		namespace = strings.TrimSpace(namespace)
+		if namespace != "" {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	return namespaces
+}
+
+// MultiNamespacedCacheBuilder returns a manager.NewCacheFunc that
+// creates a cache for each of the given namespaces
+func MultiNamespacedCacheBuilder(namespaces []string) manager.NewCacheFunc {
+	return func(config *rest.Config, opts cache.Options) (cache.Cache, error) {
+		caches := map[string]cache.Cache{}
+		for _, namespace := range namespaces {
+			opts.Namespace = namespace
+			c, err := cache.New(config, opts)
+			if err != nil {
+				return nil, err
+			}
+			caches[namespace] = c
+		}
+		return &multiNamespaceCache{namespaceToCache: caches}, nil
+	}
+}
+
+// multiNamespaceCache reads from the cache of the namespace of the
+// object, or from all of them when listing without a namespace
+type multiNamespaceCache struct {
+	namespaceToCache map[string]cache.Cache
+}
+
+var _ cache.Cache = &multiNamespaceCache{}
+
+func (c *multiNamespaceCache) GetInformer(obj runtime.Object) (toolscache.SharedIndexInformer, error) {
+	informer := &multiNamespaceInformer{}
+	for _, namespaceCache := range c.namespaceToCache {
+		i, err := namespaceCache.GetInformer(obj)
+		if err != nil {
+			return nil, err
+		}
+		informer.add(i)
+	}
+	return informer, nil
+}
+
+func (c *multiNamespaceCache) GetInformerForKind(gvk schema.GroupVersionKind) (toolscache.SharedIndexInformer, error) {
+	informer := &multiNamespaceInformer{}
+	for _, namespaceCache := range c.namespaceToCache {
+		i, err := namespaceCache.GetInformerForKind(gvk)
+		if err != nil {
+			return nil, err
+		}
+		informer.add(i)
+	}
+	return informer, nil
+}
+
+func (c *multiNamespaceCache) Start(stopCh <-chan struct{}) error {
+	for namespace, namespaceCache := range c.namespaceToCache {
+		go func(namespace string, namespaceCache cache.Cache) {
+			if err := namespaceCache.Start(stopCh); err != nil {
+				log.Error(err, "Failed to start the cache", "Namespace", namespace)
+			}
+		}(namespace, namespaceCache)
+	}
+	<-stopCh
+	return nil
+}
+
+func (c *multiNamespaceCache) WaitForCacheSync(stop <-chan struct{}) bool {
+	synced := true
+	for _, namespaceCache := range c.namespaceToCache {
+		if !namespaceCache.WaitForCacheSync(stop) {
+			synced = false
+		}
+	}
+	return synced
+}
+
+func (c *multiNamespaceCache) IndexField(obj runtime.Object, field string, extractValue client.IndexerFunc) error {
+	for _, namespaceCache := range c.namespaceToCache {
+		if err := namespaceCache.IndexField(obj, field, extractValue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *multiNamespaceCache) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	namespaceCache, ok := c.namespaceToCache[key.Namespace]
+	if !ok {
+		// Objects outside of the watched namespaces are never found, the
+		// same as with the cache of a single namespace
+		return errors.NewNotFound(schema.GroupResource{}, key.Name)
+	}
+	return namespaceCache.Get(ctx, key, obj)
+}
+
+func (c *multiNamespaceCache) List(ctx context.Context, opts *client.ListOptions, list runtime.Object) error {
+	if opts != nil && opts.Namespace != "" {
+		namespaceCache, ok := c.namespaceToCache[opts.Namespace]
+		if !ok {
+			return meta.SetList(list, []runtime.Object{})
+		}
+		return namespaceCache.List(ctx, opts, list)
+	}
+
+	allItems := []runtime.Object{}
+	for _, namespaceCache := range c.namespaceToCache {
+		namespaceList := list.DeepCopyObject()
+		if err := namespaceCache.List(ctx, opts, namespaceList); err != nil {
+			return err
+		}
+		items, err := meta.ExtractList(namespaceList)
+		if err != nil {
+			return err
+		}
+		allItems = append(allItems, items...)
+	}
+	return meta.SetList(list, allItems)
+}
+
+// multiNamespaceInformer adds the event handlers and indexers to the
+// informers of all the namespaces. The store, indexer and controller
+// that it returns are the ones of the first namespace, as the
+// controllers only ever add event handlers.
+type multiNamespaceInformer struct {
+	toolscache.SharedIndexInformer
+	informers []toolscache.SharedIndexInformer
+}
+
+func (i *multiNamespaceInformer) add(informer toolscache.SharedIndexInformer) {
+	if i.SharedIndexInformer == nil {
+		i.SharedIndexInformer = informer
+	}
+	i.informers = append(i.informers, informer)
+}
+
+func (i *multiNamespaceInformer) AddEventHandler(handler toolscache.ResourceEventHandler) {
+	for _, informer := range i.informers {
+		informer.AddEventHandler(handler)
+	}
+}
+
+func (i *multiNamespaceInformer) AddEventHandlerWithResyncPeriod(handler toolscache.ResourceEventHandler, resyncPeriod time.Duration) {
+	for _, informer := range i.informers {
+		informer.AddEventHandlerWithResyncPeriod(handler, resyncPeriod)
+	}
+}
+
+func (i *multiNamespaceInformer) AddIndexers(indexers toolscache.Indexers) error {
+	for _, informer := range i.informers {
+		if err := informer.AddIndexers(indexers); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (i *multiNamespaceInformer) HasSynced() bool {
+	for _, informer := range i.informers {
+		if !informer.HasSynced() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestWatchNamespaces(t *testing.T) {
+	cases := map[string][]string{
+		"":                     nil,
+		"unifiedpush":          {"unifiedpush"},
+		"staging,qa":           {"staging", "qa"},
+		" staging , qa ,":      {"staging", "qa"},
+		"staging,,unifiedpush": {"staging", "unifiedpush"},
+	}
+	for watchNamespace, expected := range cases {
+		if namespaces := WatchNamespaces(watchNamespace); !reflect.DeepEqual(namespaces, expected) {
+			t.Errorf("expected %v for %q, got %v", expected, watchNamespace, namespaces)
+		}
+	}
+}
+
+// fakeCache reads from a fake client instead of informers
+type fakeCache struct {
+	client.Reader
+	cache.Informers
+}
+
+func newFakeCache(objs ...runtime.Object) cache.Cache {
+	return &fakeCache{Reader: fakeclient.NewFakeClient(objs...)}
+}
+
+func TestMultiNamespaceCache(t *testing.T) {
+	staging := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "staging"}}
+	qa := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "qa"}}
+	c := &multiNamespaceCache{namespaceToCache: map[string]cache.Cache{
+		"staging": newFakeCache(staging),
+		"qa":      newFakeCache(qa),
+	}}
+
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: "config", Namespace: "qa"}, &corev1.ConfigMap{}); err != nil {
+		t.Errorf("expected to get the ConfigMap in a watched namespace: (%v)", err)
+	}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: "config", Namespace: "other"}, &corev1.ConfigMap{}); !errors.IsNotFound(err) {
+		t.Errorf("expected a not found error outside of the watched namespaces, got (%v)", err)
+	}
+
+	list := &corev1.ConfigMapList{}
+	if err := c.List(context.TODO(), &client.ListOptions{}, list); err != nil {
+		t.Fatalf("list: (%v)", err)
+	}
+	if len(list.Items) != 2 {
+		t.Errorf("expected the ConfigMaps of all the watched namespaces, got %v", list.Items)
+	}
+
+	list = &corev1.ConfigMapList{}
+	if err := c.List(context.TODO(), client.InNamespace("staging"), list); err != nil {
+		t.Fatalf("list: (%v)", err)
+	}
+	if len(list.Items) != 1 || list.Items[0].Namespace != "staging" {
+		t.Errorf("expected only the ConfigMap in staging, got %v", list.Items)
+	}
+
+	list = &corev1.ConfigMapList{}
+	if err := c.List(context.TODO(), client.InNamespace("other"), list); err != nil || len(list.Items) != 0 {
+		t.Errorf("expected no ConfigMaps outside of the watched namespaces, got %v (%v)", list.Items, err)
+	}
+}
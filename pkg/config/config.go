@@ -1,6 +1,10 @@
 package config
 
-import "os"
+import (
+	"os"
+
+	"github.com/aerogear/unifiedpush-operator/pkg/constants"
+)
 
 type Config struct {
 	UPSContainerName        string
@@ -22,6 +26,14 @@ type Config struct {
 	PostgresCpuLimit      string
 	PostgresCpuRequest    string
 	PostgresPVCSize       string
+
+	// The images can be overridden with the RELATED_IMAGE_* variables,
+	// e.g. to use a mirror registry in a disconnected cluster
+	UPSImage        string
+	PostgresImage   string
+	OauthProxyImage string
+	OIDCProxyImage  string
+	BackupImage     string
 }
 
 func New() Config {
@@ -45,6 +57,12 @@ func New() Config {
 		PostgresCpuLimit:      getEnv("POSTGRES_CPU_LIMIT", "1"),
 		PostgresCpuRequest:    getEnv("POSTGRES_CPU_REQUEST", "250m"),
 		PostgresPVCSize:       getEnv("POSTGRES_PVC_SIZE", "5Gi"),
+
+		UPSImage:        getEnv("RELATED_IMAGE_UNIFIEDPUSH", constants.UPSImage),
+		PostgresImage:   getEnv("RELATED_IMAGE_POSTGRESQL", constants.PostgresImage),
+		OauthProxyImage: getEnv("RELATED_IMAGE_OAUTH_PROXY", constants.OauthProxyImage),
+		OIDCProxyImage:  getEnv("RELATED_IMAGE_OIDC_PROXY", constants.OIDCProxyImage),
+		BackupImage:     getEnv("RELATED_IMAGE_BACKUP", constants.BackupImage),
 	}
 }
 
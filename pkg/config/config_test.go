@@ -0,0 +1,24 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/aerogear/unifiedpush-operator/pkg/constants"
+)
+
+func TestNew_RelatedImages(t *testing.T) {
+	cfg := New()
+	if cfg.UPSImage != constants.UPSImage || cfg.BackupImage != constants.BackupImage {
+		t.Errorf("expected the images from pkg/constants by default, got %s and %s", cfg.UPSImage, cfg.BackupImage)
+	}
+
+	image := "mirror.example.com/aerogear/unifiedpush-configurable-container@sha256:9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+	os.Setenv("RELATED_IMAGE_UNIFIEDPUSH", image)
+	defer os.Unsetenv("RELATED_IMAGE_UNIFIEDPUSH")
+
+	cfg = New()
+	if cfg.UPSImage != image {
+		t.Errorf("expected the image from RELATED_IMAGE_UNIFIEDPUSH, got %s", cfg.UPSImage)
+	}
+}
@@ -4,5 +4,6 @@ const (
 	UPSImage        = "quay.io/aerogear/unifiedpush-configurable-container:2.3.2-1"
 	PostgresImage   = "centos/postgresql-10-centos7:1"
 	OauthProxyImage = "quay.io/openshift/origin-oauth-proxy:4.2.0"
+	OIDCProxyImage  = "quay.io/oauth2-proxy/oauth2-proxy:v7.1.3"
 	BackupImage     = "quay.io/integreatly/backup-container:1.0.16"
 )
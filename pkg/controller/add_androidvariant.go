@@ -0,0 +1,10 @@
+package controller
+
+import (
+	"github.com/aerogear/unifiedpush-operator/pkg/controller/androidvariant"
+)
+
+func init() {
+	// AddToManagerFuncs is a list of functions to create controllers and add them to a manager.
+	AddToManagerFuncs = append(AddToManagerFuncs, androidvariant.Add)
+}
@@ -0,0 +1,456 @@
+package adoption
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	pushv1alpha1 "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1"
+	"github.com/aerogear/unifiedpush-operator/pkg/controller/util"
+	"github.com/aerogear/unifiedpush-operator/pkg/unifiedpush"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	controllerName    = "adoption-controller"
+	requeueDelay      = 30 * time.Second
+	requeueErrorDelay = 5 * time.Second
+
+	// maxNameLength leaves room for the suffixes that the other
+	// controllers add to the names of the CRs created here
+	maxNameLength = 40
+)
+
+var log = logf.Log.WithName(controllerName)
+
+// Add creates a new adoption Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileAdoption{
+		client:         mgr.GetClient(),
+		scheme:         mgr.GetScheme(),
+		recorder:       mgr.GetRecorder(controllerName),
+		unifiedpushURL: util.UnifiedPushServerURL,
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource UnifiedPushServer
+	err = c.Watch(&source.Kind{Type: &pushv1alpha1.UnifiedPushServer{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var _ reconcile.Reconciler = &ReconcileAdoption{}
+
+// ReconcileAdoption imports the applications and variants of a
+// UnifiedPushServer into CRs
+type ReconcileAdoption struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	client   client.Client
+	scheme   *runtime.Scheme
+	recorder record.EventRecorder
+
+	unifiedpushURL util.UnifiedPushURLFunc
+}
+
+// Reconcile creates a PushApplication, AndroidVariant,
+// IOSTokenVariant or WebPushVariant CR for each application and
+// variant in a UnifiedPushServer that has the import annotation, and
+// that doesn't have a CR yet. The CRs are marked as adopted, so that
+// they take over the existing applications and variants instead of
+// creating new ones. The annotation is removed once the import is done.
+func (r *ReconcileAdoption) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+
+	// Fetch the UnifiedPushServer instance
+	instance := &pushv1alpha1.UnifiedPushServer{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	if instance.DeletionTimestamp != nil || instance.Annotations[util.ImportApplicationsAnnotation] != "true" {
+		return reconcile.Result{}, nil
+	}
+	reqLogger.Info("Importing applications from UnifiedPushServer")
+
+	if !util.IsUnifiedPushServerReady(instance) {
+		reqLogger.Info("Requeuing, UnifiedPushServer not ready.")
+		return reconcile.Result{RequeueAfter: requeueDelay}, nil
+	}
+
+	apps, err := unifiedpush.NewClient(r.unifiedpushURL(instance)).ListApplications()
+	if err != nil {
+		return r.manageError(instance, err)
+	}
+
+	known, err := r.knownIDs(instance.Namespace)
+	if err != nil {
+		return r.manageError(instance, err)
+	}
+
+	importedApps, importedVariants := 0, 0
+	for _, app := range apps {
+		appName, ok := known[app.PushApplicationID]
+		if !ok {
+			appName, err = r.importApplication(instance, app.PushApplication)
+			if err != nil {
+				return r.manageError(instance, err)
+			}
+			importedApps++
+		}
+
+		for _, raw := range app.Variants {
+			imported, err := r.importVariant(instance.Namespace, appName, raw, known)
+			if err != nil {
+				return r.manageError(instance, err)
+			}
+			if imported {
+				importedVariants++
+			}
+		}
+	}
+
+	delete(instance.Annotations, util.ImportApplicationsAnnotation)
+	if err := r.client.Update(context.TODO(), instance); err != nil {
+		return r.manageError(instance, err)
+	}
+
+	message := fmt.Sprintf("Imported %d applications and %d variants", importedApps, importedVariants)
+	reqLogger.Info(message)
+	r.recorder.Event(instance, "Normal", "Imported", message)
+	return reconcile.Result{}, nil
+}
+
+func (r *ReconcileAdoption) manageError(instance *pushv1alpha1.UnifiedPushServer, issue error) (reconcile.Result, error) {
+	log.Error(issue, "Failed to import applications", "UnifiedPushServer.Namespace", instance.Namespace, "UnifiedPushServer.Name", instance.Name)
+	r.recorder.Event(instance, "Warning", "ImportFailed", issue.Error())
+
+	return reconcile.Result{
+		RequeueAfter: requeueErrorDelay,
+		Requeue:      true,
+	}, nil
+}
+
+// knownIDs maps the UPS ids of the applications and variants that
+// already have a CR in the namespace to the name of that CR
+func (r *ReconcileAdoption) knownIDs(namespace string) (map[string]string, error) {
+	known := map[string]string{}
+	add := func(o metav1.Object, id string) {
+		if id != "" {
+			known[id] = o.GetName()
+		}
+		if adopted := util.AdoptedID(o); adopted != "" {
+			known[adopted] = o.GetName()
+		}
+	}
+
+	apps := &pushv1alpha1.PushApplicationList{}
+	if err := r.client.List(context.TODO(), client.InNamespace(namespace), apps); err != nil {
+		return nil, err
+	}
+	for i := range apps.Items {
+		add(&apps.Items[i], apps.Items[i].Status.PushApplicationId)
+	}
+
+	androidVariants := &pushv1alpha1.AndroidVariantList{}
+	if err := r.client.List(context.TODO(), client.InNamespace(namespace), androidVariants); err != nil {
+		return nil, err
+	}
+	for i := range androidVariants.Items {
+		add(&androidVariants.Items[i], androidVariants.Items[i].Status.VariantId)
+	}
+
+	iosTokenVariants := &pushv1alpha1.IOSTokenVariantList{}
+	if err := r.client.List(context.TODO(), client.InNamespace(namespace), iosTokenVariants); err != nil {
+		return nil, err
+	}
+	for i := range iosTokenVariants.Items {
+		add(&iosTokenVariants.Items[i], iosTokenVariants.Items[i].Status.VariantId)
+	}
+
+	webPushVariants := &pushv1alpha1.WebPushVariantList{}
+	if err := r.client.List(context.TODO(), client.InNamespace(namespace), webPushVariants); err != nil {
+		return nil, err
+	}
+	for i := range webPushVariants.Items {
+		add(&webPushVariants.Items[i], webPushVariants.Items[i].Status.VariantId)
+	}
+
+	return known, nil
+}
+
+// importApplication creates an adopted PushApplication for an
+// application in UPS, and returns its name
+func (r *ReconcileAdoption) importApplication(ups *pushv1alpha1.UnifiedPushServer, app unifiedpush.PushApplication) (string, error) {
+	name, err := r.uniqueName(&pushv1alpha1.PushApplication{}, ups.Namespace, app.Name, app.PushApplicationID)
+	if err != nil {
+		return "", err
+	}
+
+	cr := &pushv1alpha1.PushApplication{
+		ObjectMeta: adoptedObjectMeta(name, ups.Namespace, app.PushApplicationID),
+		Spec: pushv1alpha1.PushApplicationSpec{
+			Name:              app.Name,
+			UnifiedPushServer: ups.Name,
+			Description:       app.Description,
+		},
+	}
+	log.Info("Creating an adopted PushApplication", "PushApplication.Namespace", cr.Namespace, "PushApplication.Name", cr.Name, "PushApplicationId", app.PushApplicationID)
+	return name, r.client.Create(context.TODO(), cr)
+}
+
+// importVariant creates an adopted variant CR, and a Secret with the
+// credentials that UPS returned for it, for a variant in UPS of one of
+// the types that have a CR. It returns false if the variant was
+// skipped.
+func (r *ReconcileAdoption) importVariant(namespace string, appName string, raw json.RawMessage, known map[string]string) (bool, error) {
+	base := unifiedpush.Variant{}
+	if err := json.Unmarshal(raw, &base); err != nil {
+		return false, err
+	}
+	knownName, isKnown := known[base.VariantID]
+
+	var cr runtime.Object
+	credentials := map[string]string{}
+	meta := func(kind runtime.Object) (metav1.ObjectMeta, error) {
+		if isKnown {
+			return adoptedObjectMeta(knownName, namespace, base.VariantID), nil
+		}
+		name, err := r.uniqueName(kind, namespace, fmt.Sprintf("%s-%s", appName, base.Name), base.VariantID)
+		return adoptedObjectMeta(name, namespace, base.VariantID), err
+	}
+
+	switch variantType := unifiedpush.VariantType(raw); variantType {
+	case "android":
+		v := unifiedpush.AndroidVariant{}
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return false, err
+		}
+		objectMeta, err := meta(&pushv1alpha1.AndroidVariant{})
+		if err != nil {
+			return false, err
+		}
+		cr = &pushv1alpha1.AndroidVariant{
+			ObjectMeta: objectMeta,
+			Spec: pushv1alpha1.AndroidVariantSpec{
+				Name:              v.Name,
+				Description:       v.Description,
+				PushApplication:   appName,
+				CredentialsSecret: credentialsSecretName(objectMeta.Name),
+			},
+		}
+		credentials["serverKey"] = v.GoogleKey
+		credentials["senderId"] = v.ProjectNumber
+	case "ios_token":
+		v := unifiedpush.IOSTokenVariant{}
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return false, err
+		}
+		objectMeta, err := meta(&pushv1alpha1.IOSTokenVariant{})
+		if err != nil {
+			return false, err
+		}
+		cr = &pushv1alpha1.IOSTokenVariant{
+			ObjectMeta: objectMeta,
+			Spec: pushv1alpha1.IOSTokenVariantSpec{
+				Name:              v.Name,
+				Description:       v.Description,
+				PushApplication:   appName,
+				CredentialsSecret: credentialsSecretName(objectMeta.Name),
+			},
+		}
+		credentials["privateKey"] = v.PrivateKey
+		credentials["keyId"] = v.KeyID
+		credentials["teamId"] = v.TeamID
+		credentials["bundleId"] = v.BundleID
+		credentials["production"] = strconv.FormatBool(v.Production)
+	case "web_push":
+		v := unifiedpush.WebPushVariant{}
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return false, err
+		}
+		objectMeta, err := meta(&pushv1alpha1.WebPushVariant{})
+		if err != nil {
+			return false, err
+		}
+		cr = &pushv1alpha1.WebPushVariant{
+			ObjectMeta: objectMeta,
+			Spec: pushv1alpha1.WebPushVariantSpec{
+				Name:              v.Name,
+				Description:       v.Description,
+				PushApplication:   appName,
+				Alias:             v.Alias,
+				CredentialsSecret: credentialsSecretName(objectMeta.Name),
+			},
+		}
+		credentials["publicKey"] = v.PublicKey
+		credentials["privateKey"] = v.PrivateKey
+	default:
+		log.Info("Skipping variant of a type that has no CR", "VariantId", base.VariantID, "Type", variantType)
+		return false, nil
+	}
+
+	crMeta := cr.(metav1.Object)
+	if isKnown {
+		return false, r.restoreCredentialsSecret(cr, base.VariantID, credentials)
+	}
+
+	log.Info("Creating an adopted variant", "Variant.Namespace", crMeta.GetNamespace(), "Variant.Name", crMeta.GetName(), "VariantId", base.VariantID)
+	if err := r.client.Create(context.TODO(), cr); err != nil {
+		return false, err
+	}
+	return true, r.createCredentialsSecret(crMeta, credentials)
+}
+
+// restoreCredentialsSecret creates the credentials Secret of a variant
+// that was adopted by an earlier import, if that import failed before
+// creating it. The CR is fetched into the given object, and variants
+// that weren't adopted from the given id are left alone.
+func (r *ReconcileAdoption) restoreCredentialsSecret(cr runtime.Object, variantID string, credentials map[string]string) error {
+	crMeta := cr.(metav1.Object)
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: crMeta.GetName(), Namespace: crMeta.GetNamespace()}, cr)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if util.AdoptedID(crMeta) != variantID {
+		return nil
+	}
+
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: credentialsSecretName(crMeta.GetName()), Namespace: crMeta.GetNamespace()}, &corev1.Secret{})
+	if err == nil || !errors.IsNotFound(err) {
+		return err
+	}
+	log.Info("Restoring the credentials Secret of an adopted variant", "Variant.Namespace", crMeta.GetNamespace(), "Variant.Name", crMeta.GetName(), "VariantId", variantID)
+	return r.createCredentialsSecret(crMeta, credentials)
+}
+
+// createCredentialsSecret creates the Secret, owned by the given
+// variant CR, with the credentials that UPS returned for it
+func (r *ReconcileAdoption) createCredentialsSecret(crMeta metav1.Object, credentials map[string]string) error {
+	// Only the credentials that UPS returned are stored, so that the
+	// variant fails to reconcile, instead of blanking them out in
+	// UPS, if some are missing
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      credentialsSecretName(crMeta.GetName()),
+			Namespace: crMeta.GetNamespace(),
+		},
+		Data: map[string][]byte{},
+	}
+	for key, value := range credentials {
+		if value != "" {
+			secret.Data[key] = []byte(value)
+		}
+	}
+	if err := controllerutil.SetControllerReference(crMeta, secret, r.scheme); err != nil {
+		return err
+	}
+	return r.client.Create(context.TODO(), secret)
+}
+
+// uniqueName turns the name of an application or variant in UPS into
+// a valid CR name that isn't in use yet, adding part of its id, and
+// then a counter, if needed
+func (r *ReconcileAdoption) uniqueName(kind runtime.Object, namespace string, upsName string, id string) (string, error) {
+	base := sanitizeName(upsName)
+	idSuffix := sanitizeName(id)
+	if len(idSuffix) > 8 {
+		idSuffix = idSuffix[:8]
+	}
+
+	for i := 0; ; i++ {
+		name := base
+		if i == 1 {
+			name = withSuffix(base, idSuffix)
+		} else if i > 1 {
+			name = withSuffix(base, fmt.Sprintf("%s-%d", idSuffix, i))
+		}
+		err := r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, kind)
+		if errors.IsNotFound(err) {
+			return name, nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+}
+
+// withSuffix adds a suffix to a name, shortening the name so that the
+// result stays within maxNameLength
+func withSuffix(name string, suffix string) string {
+	if len(name) > maxNameLength-len(suffix)-1 {
+		name = strings.TrimRight(name[:maxNameLength-len(suffix)-1], "-")
+	}
+	return fmt.Sprintf("%s-%s", name, suffix)
+}
+
+var invalidNameChars = regexp.MustCompile("[^a-z0-9-]+")
+
+// sanitizeName makes a DNS-1123 label out of any string
+func sanitizeName(name string) string {
+	name = invalidNameChars.ReplaceAllString(strings.ToLower(name), "-")
+	if len(name) > maxNameLength {
+		name = name[:maxNameLength]
+	}
+	name = strings.Trim(name, "-")
+	if name == "" {
+		return "imported"
+	}
+	return name
+}
+
+func credentialsSecretName(variantName string) string {
+	return fmt.Sprintf("%s-credentials", variantName)
+}
+
+func adoptedObjectMeta(name string, namespace string, id string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      name,
+		Namespace: namespace,
+		Annotations: map[string]string{
+			util.AdoptedAnnotation: id,
+		},
+	}
+}
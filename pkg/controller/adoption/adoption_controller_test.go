@@ -0,0 +1,285 @@
+package adoption
+
+import (
+	"context"
+	"testing"
+
+	pushv1alpha1 "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1"
+	"github.com/aerogear/unifiedpush-operator/pkg/controller/testutil"
+	"github.com/aerogear/unifiedpush-operator/pkg/controller/util"
+	"github.com/aerogear/unifiedpush-operator/pkg/unifiedpush"
+	"github.com/aerogear/unifiedpush-operator/pkg/unifiedpush/fake"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+//buildReconcileWithFakeClientWithMocks return reconcile with fake client, schemes and mock objects
+func buildReconcileWithFakeClientWithMocks(objs []runtime.Object, server *fake.Server, t *testing.T) *ReconcileAdoption {
+	return &ReconcileAdoption{
+		client:         testutil.NewFakeClient(t, objs...),
+		scheme:         scheme.Scheme,
+		recorder:       record.NewFakeRecorder(10),
+		unifiedpushURL: testutil.UnifiedPushURL(server),
+	}
+}
+
+func TestReconcileAdoption_Reconcile(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+
+	upsClient := unifiedpush.NewClient(server.URL)
+	app, err := upsClient.CreateApplication(unifiedpush.PushApplication{Name: "My App", Description: "An existing app"})
+	if err != nil {
+		t.Fatalf("create application: (%v)", err)
+	}
+	android, err := upsClient.CreateAndroidVariant(app.PushApplicationID, unifiedpush.AndroidVariant{
+		Variant:       unifiedpush.Variant{Name: "Android"},
+		GoogleKey:     "server-key",
+		ProjectNumber: "sender-id",
+	})
+	if err != nil {
+		t.Fatalf("create Android variant: (%v)", err)
+	}
+	webPush, err := upsClient.CreateWebPushVariant(app.PushApplicationID, unifiedpush.WebPushVariant{
+		Variant:    unifiedpush.Variant{Name: "Web"},
+		PublicKey:  "public-key",
+		PrivateKey: "private-key",
+		Alias:      "mailto:admin@example.com",
+	})
+	if err != nil {
+		t.Fatalf("create web push variant: (%v)", err)
+	}
+	existing, err := upsClient.CreateApplication(unifiedpush.PushApplication{Name: "example-pushapplication"})
+	if err != nil {
+		t.Fatalf("create application: (%v)", err)
+	}
+
+	ups := testutil.ReadyUnifiedPushServer()
+	ups.Annotations = map[string]string{util.ImportApplicationsAnnotation: "true"}
+	r := buildReconcileWithFakeClientWithMocks([]runtime.Object{
+		ups,
+		testutil.PushApplication(existing.PushApplicationID),
+	}, server, t)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: ups.Name, Namespace: ups.Namespace}}
+
+	res, err := r.Reconcile(req)
+	if err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	if res.Requeue {
+		t.Fatalf("reconcile requeued unexpectedly")
+	}
+
+	// The application that already has a CR isn't imported again
+	apps := &pushv1alpha1.PushApplicationList{}
+	if err := r.client.List(context.TODO(), client.InNamespace(ups.Namespace), apps); err != nil {
+		t.Fatalf("list PushApplications: (%v)", err)
+	}
+	if len(apps.Items) != 2 {
+		t.Fatalf("expected 2 PushApplications, got %d", len(apps.Items))
+	}
+
+	imported := &pushv1alpha1.PushApplication{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: "my-app", Namespace: ups.Namespace}, imported); err != nil {
+		t.Fatalf("get PushApplication: (%v)", err)
+	}
+	if util.AdoptedID(imported) != app.PushApplicationID {
+		t.Errorf("expected PushApplication to adopt %s, got %q", app.PushApplicationID, util.AdoptedID(imported))
+	}
+	if imported.Spec.Name != "My App" || imported.Spec.Description != "An existing app" || imported.Spec.UnifiedPushServer != ups.Name {
+		t.Errorf("unexpected PushApplication spec: %+v", imported.Spec)
+	}
+
+	androidVariant := &pushv1alpha1.AndroidVariant{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: "my-app-android", Namespace: ups.Namespace}, androidVariant); err != nil {
+		t.Fatalf("get AndroidVariant: (%v)", err)
+	}
+	if util.AdoptedID(androidVariant) != android.VariantID || androidVariant.Spec.PushApplication != "my-app" {
+		t.Errorf("unexpected AndroidVariant: %+v", androidVariant.ObjectMeta)
+	}
+	credentials := &corev1.Secret{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: androidVariant.Spec.CredentialsSecret, Namespace: ups.Namespace}, credentials); err != nil {
+		t.Fatalf("get credentials Secret: (%v)", err)
+	}
+	if string(credentials.Data["serverKey"]) != "server-key" || string(credentials.Data["senderId"]) != "sender-id" {
+		t.Errorf("unexpected Android credentials: %v", credentials.Data)
+	}
+
+	webPushVariant := &pushv1alpha1.WebPushVariant{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: "my-app-web", Namespace: ups.Namespace}, webPushVariant); err != nil {
+		t.Fatalf("get WebPushVariant: (%v)", err)
+	}
+	if util.AdoptedID(webPushVariant) != webPush.VariantID || webPushVariant.Spec.Alias != "mailto:admin@example.com" {
+		t.Errorf("unexpected WebPushVariant: %+v", webPushVariant)
+	}
+	credentials = &corev1.Secret{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: webPushVariant.Spec.CredentialsSecret, Namespace: ups.Namespace}, credentials); err != nil {
+		t.Fatalf("get credentials Secret: (%v)", err)
+	}
+	if string(credentials.Data["publicKey"]) != "public-key" || string(credentials.Data["privateKey"]) != "private-key" {
+		t.Errorf("unexpected web push credentials: %v", credentials.Data)
+	}
+
+	found := &pushv1alpha1.UnifiedPushServer{}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, found); err != nil {
+		t.Fatalf("get UnifiedPushServer: (%v)", err)
+	}
+	if _, ok := found.Annotations[util.ImportApplicationsAnnotation]; ok {
+		t.Errorf("expected import annotation to be removed, got %v", found.Annotations)
+	}
+
+	// Importing again doesn't create any duplicates
+	found.Annotations = map[string]string{util.ImportApplicationsAnnotation: "true"}
+	if err := r.client.Update(context.TODO(), found); err != nil {
+		t.Fatalf("update UnifiedPushServer: (%v)", err)
+	}
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	if err := r.client.List(context.TODO(), client.InNamespace(ups.Namespace), apps); err != nil {
+		t.Fatalf("list PushApplications: (%v)", err)
+	}
+	androidVariants := &pushv1alpha1.AndroidVariantList{}
+	if err := r.client.List(context.TODO(), client.InNamespace(ups.Namespace), androidVariants); err != nil {
+		t.Fatalf("list AndroidVariants: (%v)", err)
+	}
+	if len(apps.Items) != 2 || len(androidVariants.Items) != 1 {
+		t.Errorf("expected no new CRs, got %d PushApplications and %d AndroidVariants", len(apps.Items), len(androidVariants.Items))
+	}
+}
+
+func TestReconcileAdoption_NotRequested(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+
+	if _, err := unifiedpush.NewClient(server.URL).CreateApplication(unifiedpush.PushApplication{Name: "My App"}); err != nil {
+		t.Fatalf("create application: (%v)", err)
+	}
+
+	ups := testutil.ReadyUnifiedPushServer()
+	r := buildReconcileWithFakeClientWithMocks([]runtime.Object{ups}, server, t)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: ups.Name, Namespace: ups.Namespace}}
+
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	apps := &pushv1alpha1.PushApplicationList{}
+	if err := r.client.List(context.TODO(), client.InNamespace(ups.Namespace), apps); err != nil {
+		t.Fatalf("list PushApplications: (%v)", err)
+	}
+	if len(apps.Items) != 0 {
+		t.Errorf("expected no PushApplications without the import annotation, got %d", len(apps.Items))
+	}
+}
+
+func TestReconcileAdoption_MissingCredentialsSecret(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+
+	upsClient := unifiedpush.NewClient(server.URL)
+	app, err := upsClient.CreateApplication(unifiedpush.PushApplication{Name: "My App"})
+	if err != nil {
+		t.Fatalf("create application: (%v)", err)
+	}
+	android, err := upsClient.CreateAndroidVariant(app.PushApplicationID, unifiedpush.AndroidVariant{
+		Variant:       unifiedpush.Variant{Name: "Android"},
+		GoogleKey:     "server-key",
+		ProjectNumber: "sender-id",
+	})
+	if err != nil {
+		t.Fatalf("create Android variant: (%v)", err)
+	}
+
+	// An earlier import failed after creating the variant CR, but
+	// before creating its credentials Secret
+	ups := testutil.ReadyUnifiedPushServer()
+	ups.Annotations = map[string]string{util.ImportApplicationsAnnotation: "true"}
+	adoptedApp := testutil.PushApplication("")
+	adoptedApp.ObjectMeta = adoptedObjectMeta("my-app", ups.Namespace, app.PushApplicationID)
+	adoptedVariant := &pushv1alpha1.AndroidVariant{
+		ObjectMeta: adoptedObjectMeta("my-app-android", ups.Namespace, android.VariantID),
+		Spec: pushv1alpha1.AndroidVariantSpec{
+			Name:              "Android",
+			PushApplication:   "my-app",
+			CredentialsSecret: credentialsSecretName("my-app-android"),
+		},
+	}
+	r := buildReconcileWithFakeClientWithMocks([]runtime.Object{ups, adoptedApp, adoptedVariant}, server, t)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: ups.Name, Namespace: ups.Namespace}}
+
+	res, err := r.Reconcile(req)
+	if err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	if res.Requeue {
+		t.Fatalf("reconcile requeued unexpectedly")
+	}
+
+	credentials := &corev1.Secret{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: adoptedVariant.Spec.CredentialsSecret, Namespace: ups.Namespace}, credentials); err != nil {
+		t.Fatalf("get credentials Secret: (%v)", err)
+	}
+	if string(credentials.Data["serverKey"]) != "server-key" || string(credentials.Data["senderId"]) != "sender-id" {
+		t.Errorf("unexpected Android credentials: %v", credentials.Data)
+	}
+	androidVariants := &pushv1alpha1.AndroidVariantList{}
+	if err := r.client.List(context.TODO(), client.InNamespace(ups.Namespace), androidVariants); err != nil {
+		t.Fatalf("list AndroidVariants: (%v)", err)
+	}
+	if len(androidVariants.Items) != 1 {
+		t.Errorf("expected no new AndroidVariants, got %d", len(androidVariants.Items))
+	}
+}
+
+func TestUniqueName(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+
+	taken := func(name string) *pushv1alpha1.PushApplication {
+		app := testutil.PushApplication("")
+		app.Name = name
+		return app
+	}
+	r := buildReconcileWithFakeClientWithMocks([]runtime.Object{
+		taken("my-app"),
+		taken("my-app-01234567"),
+		taken("my-app-01234567-2"),
+	}, server, t)
+
+	name, err := r.uniqueName(&pushv1alpha1.PushApplication{}, "unifiedpush", "My App", "0123456789abcdef")
+	if err != nil {
+		t.Fatalf("uniqueName: (%v)", err)
+	}
+	if name != "my-app-01234567-3" {
+		t.Errorf("expected my-app-01234567-3, got %s", name)
+	}
+
+	name, err = r.uniqueName(&pushv1alpha1.PushApplication{}, "unifiedpush", "Other App", "0123456789abcdef")
+	if err != nil {
+		t.Fatalf("uniqueName: (%v)", err)
+	}
+	if name != "other-app" {
+		t.Errorf("expected other-app, got %s", name)
+	}
+}
+
+func TestSanitizeName(t *testing.T) {
+	cases := map[string]string{
+		"My App":          "my-app",
+		"my-app":          "my-app",
+		"  Ünïcode_app! ": "n-code-app",
+		"---":             "imported",
+		"a-very-long-application-name-that-goes-on": "a-very-long-application-name-that-goes-o",
+	}
+	for in, expected := range cases {
+		if got := sanitizeName(in); got != expected {
+			t.Errorf("sanitizeName(%q): expected %q, got %q", in, expected, got)
+		}
+	}
+}
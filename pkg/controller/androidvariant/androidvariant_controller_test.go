@@ -0,0 +1,246 @@
+package androidvariant
+
+import (
+	"context"
+	"testing"
+
+	pushv1alpha1 "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1"
+	"github.com/aerogear/unifiedpush-operator/pkg/controller/testutil"
+	"github.com/aerogear/unifiedpush-operator/pkg/controller/util"
+	"github.com/aerogear/unifiedpush-operator/pkg/unifiedpush"
+	"github.com/aerogear/unifiedpush-operator/pkg/unifiedpush/fake"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+//buildReconcileWithFakeClientWithMocks return reconcile with fake client, schemes and mock objects
+func buildReconcileWithFakeClientWithMocks(objs []runtime.Object, server *fake.Server, t *testing.T) *ReconcileAndroidVariant {
+	return &ReconcileAndroidVariant{
+		client:         testutil.NewFakeClient(t, objs...),
+		scheme:         scheme.Scheme,
+		recorder:       record.NewFakeRecorder(10),
+		unifiedpushURL: testutil.UnifiedPushURL(server),
+	}
+}
+
+func TestReconcileAndroidVariant_Reconcile(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+
+	upsApp, err := unifiedpush.NewClient(server.URL).CreateApplication(unifiedpush.PushApplication{Name: "example-pushapplication"})
+	if err != nil {
+		t.Fatalf("create application: (%v)", err)
+	}
+
+	variant := androidVariant()
+	r := buildReconcileWithFakeClientWithMocks([]runtime.Object{
+		testutil.ReadyUnifiedPushServer(),
+		testutil.PushApplication(upsApp.PushApplicationID),
+		credentialsSecret(),
+		variant,
+	}, server, t)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: variant.Name, Namespace: variant.Namespace}}
+
+	// Create
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+
+	found := &pushv1alpha1.AndroidVariant{}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, found); err != nil {
+		t.Fatalf("get AndroidVariant: (%v)", err)
+	}
+	if found.Status.Phase != pushv1alpha1.PhaseReconciling {
+		t.Fatalf("expected phase %s, got %s: %s", pushv1alpha1.PhaseReconciling, found.Status.Phase, found.Status.Message)
+	}
+	if found.Status.PushApplicationId != upsApp.PushApplicationID {
+		t.Errorf("expected pushApplicationId %s, got %s", upsApp.PushApplicationID, found.Status.PushApplicationId)
+	}
+
+	upsVariant := &unifiedpush.AndroidVariant{}
+	if !server.Variant(found.Status.VariantId, upsVariant) {
+		t.Fatalf("variant %s was not created in UPS", found.Status.VariantId)
+	}
+	if upsVariant.Secret != found.Status.Secret {
+		t.Errorf("expected variant secret %s in status, got %s", upsVariant.Secret, found.Status.Secret)
+	}
+	if upsVariant.GoogleKey != "server-key" || upsVariant.ProjectNumber != "sender-id" {
+		t.Errorf("unexpected variant in UPS: %+v", upsVariant)
+	}
+
+	// Update when the credentials change
+	secret := credentialsSecret()
+	secret.Data["serverKey"] = []byte("new-server-key")
+	if err := r.client.Update(context.TODO(), secret); err != nil {
+		t.Fatalf("update Secret: (%v)", err)
+	}
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	server.Variant(found.Status.VariantId, upsVariant)
+	if upsVariant.GoogleKey != "new-server-key" {
+		t.Errorf("expected serverKey to be updated in UPS, got %q", upsVariant.GoogleKey)
+	}
+	if requests := variantsForSecret(r.client, secret.Namespace, secret.Name); len(requests) != 1 || requests[0] != req {
+		t.Errorf("expected Secret to map to %v, got %v", req, requests)
+	}
+
+	// Delete
+	if err := r.client.Get(context.TODO(), req.NamespacedName, found); err != nil {
+		t.Fatalf("get AndroidVariant: (%v)", err)
+	}
+	now := metav1.Now()
+	found.DeletionTimestamp = &now
+	if err := r.client.Update(context.TODO(), found); err != nil {
+		t.Fatalf("update AndroidVariant: (%v)", err)
+	}
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	if ids := server.VariantIDs(upsApp.PushApplicationID, variantType); len(ids) != 0 {
+		t.Errorf("expected variant to be deleted from UPS, got %v", ids)
+	}
+	deleted := &pushv1alpha1.AndroidVariant{}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, deleted); err != nil {
+		t.Fatalf("get AndroidVariant: (%v)", err)
+	}
+	if len(deleted.Finalizers) != 0 {
+		t.Errorf("expected finalizer to be removed, got %v", deleted.Finalizers)
+	}
+}
+
+func TestReconcileAndroidVariant_MissingCredentials(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+
+	secret := credentialsSecret()
+	delete(secret.Data, "senderId")
+	variant := androidVariant()
+	r := buildReconcileWithFakeClientWithMocks([]runtime.Object{
+		testutil.ReadyUnifiedPushServer(),
+		testutil.PushApplication("some-id"),
+		secret,
+		variant,
+	}, server, t)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: variant.Name, Namespace: variant.Namespace}}
+
+	res, err := r.Reconcile(req)
+	if err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	if !res.Requeue {
+		t.Error("expected reconcile to be requeued")
+	}
+
+	found := &pushv1alpha1.AndroidVariant{}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, found); err != nil {
+		t.Fatalf("get AndroidVariant: (%v)", err)
+	}
+	if found.Status.Phase != pushv1alpha1.PhaseFailing {
+		t.Errorf("expected phase %s, got %s", pushv1alpha1.PhaseFailing, found.Status.Phase)
+	}
+}
+
+func TestReconcileAndroidVariant_PushApplicationChanged(t *testing.T) {
+	testutil.TestPushApplicationChanged(t, testutil.VariantController{
+		VariantType: variantType,
+		Variant:     androidVariant(),
+		Objects:     []runtime.Object{credentialsSecret()},
+		NewReconciler: func(c client.Client, server *fake.Server) reconcile.Reconciler {
+			return &ReconcileAndroidVariant{
+				client:         c,
+				scheme:         scheme.Scheme,
+				recorder:       record.NewFakeRecorder(10),
+				unifiedpushURL: testutil.UnifiedPushURL(server),
+			}
+		},
+		Status: func(o runtime.Object) (string, string) {
+			variant := o.(*pushv1alpha1.AndroidVariant)
+			return variant.Status.PushApplicationId, variant.Status.VariantId
+		},
+	})
+}
+
+func TestReconcileAndroidVariant_Adopted(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+
+	upsClient := unifiedpush.NewClient(server.URL)
+	upsApp, err := upsClient.CreateApplication(unifiedpush.PushApplication{Name: "example-pushapplication"})
+	if err != nil {
+		t.Fatalf("create application: (%v)", err)
+	}
+	existing, err := upsClient.CreateAndroidVariant(upsApp.PushApplicationID, unifiedpush.AndroidVariant{
+		Variant:       unifiedpush.Variant{Name: "Android"},
+		GoogleKey:     "server-key",
+		ProjectNumber: "sender-id",
+	})
+	if err != nil {
+		t.Fatalf("create variant: (%v)", err)
+	}
+
+	variant := androidVariant()
+	variant.Annotations = map[string]string{util.AdoptedAnnotation: existing.VariantID}
+	variant.Spec.Name = "Android"
+	r := buildReconcileWithFakeClientWithMocks([]runtime.Object{
+		testutil.ReadyUnifiedPushServer(),
+		testutil.PushApplication(upsApp.PushApplicationID),
+		credentialsSecret(),
+		variant,
+	}, server, t)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: variant.Name, Namespace: variant.Namespace}}
+
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+
+	found := &pushv1alpha1.AndroidVariant{}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, found); err != nil {
+		t.Fatalf("get AndroidVariant: (%v)", err)
+	}
+	if found.Status.VariantId != existing.VariantID || found.Status.Secret != existing.Secret {
+		t.Errorf("expected the existing variant %s to be adopted, got %+v", existing.VariantID, found.Status)
+	}
+	if ids := server.VariantIDs(upsApp.PushApplicationID, variantType); len(ids) != 1 {
+		t.Errorf("expected no new variant to be created in UPS, got %v", ids)
+	}
+	upsVariant := &unifiedpush.AndroidVariant{}
+	server.Variant(existing.VariantID, upsVariant)
+	if upsVariant.Name != "Android" || upsVariant.Description != variant.Spec.Description {
+		t.Errorf("unexpected variant in UPS: %+v", upsVariant)
+	}
+}
+
+func credentialsSecret() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "example-fcm-credentials",
+			Namespace: "unifiedpush",
+		},
+		Data: map[string][]byte{
+			"serverKey": []byte("server-key"),
+			"senderId":  []byte("sender-id"),
+		},
+	}
+}
+
+func androidVariant() *pushv1alpha1.AndroidVariant {
+	return &pushv1alpha1.AndroidVariant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "example-androidvariant",
+			Namespace: "unifiedpush",
+		},
+		Spec: pushv1alpha1.AndroidVariantSpec{
+			Description:       "An example Android variant",
+			PushApplication:   "example-pushapplication",
+			CredentialsSecret: "example-fcm-credentials",
+		},
+	}
+}
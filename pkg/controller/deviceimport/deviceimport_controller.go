@@ -0,0 +1,354 @@
+package deviceimport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	pushv1alpha1 "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1"
+	"github.com/aerogear/unifiedpush-operator/pkg/controller/util"
+	"github.com/aerogear/unifiedpush-operator/pkg/unifiedpush"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	controllerName    = "deviceimport-controller"
+	defaultBatchSize  = 1000
+	requeueDelay      = 30 * time.Second
+	requeueErrorDelay = 5 * time.Second
+)
+
+var log = logf.Log.WithName(controllerName)
+
+// Add creates a new DeviceImport Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	apiReader, err := client.New(mgr.GetConfig(), client.Options{Scheme: mgr.GetScheme(), Mapper: mgr.GetRESTMapper()})
+	if err != nil {
+		log.Error(err, "Failed to create client")
+		os.Exit(1)
+	}
+	return &ReconcileDeviceImport{
+		client:         mgr.GetClient(),
+		apiReader:      apiReader,
+		scheme:         mgr.GetScheme(),
+		recorder:       mgr.GetRecorder(controllerName),
+		unifiedpushURL: util.UnifiedPushServerURL,
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource DeviceImport
+	err = c.Watch(&source.Kind{Type: &pushv1alpha1.DeviceImport{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var _ reconcile.Reconciler = &ReconcileDeviceImport{}
+
+// ReconcileDeviceImport reconciles a DeviceImport object
+type ReconcileDeviceImport struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	client   client.Client
+	scheme   *runtime.Scheme
+	recorder record.EventRecorder
+
+	// apiReader reads the DeviceImport straight from the apiserver, so
+	// that the import resumes from the latest counts, not from an out
+	// of date copy in the cache
+	apiReader client.Reader
+
+	unifiedpushURL util.UnifiedPushURLFunc
+}
+
+// Reconcile imports the installations of a DeviceImport into its
+// variant, in batches. The counts in the status are updated after each
+// batch, and an import that is interrupted, or that can't reach UPS,
+// carries on from where it left off. A completed import is never run
+// again.
+func (r *ReconcileDeviceImport) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling DeviceImport")
+
+	// Fetch the DeviceImport instance
+	instance := &pushv1alpha1.DeviceImport{}
+	err := r.apiReader.Get(context.TODO(), request.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Return and don't requeue
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return r.manageError(instance, err)
+	}
+
+	if instance.Status.CompletedAt != nil {
+		reqLogger.Info("DeviceImport already completed, not importing again", "CompletedAt", instance.Status.CompletedAt)
+		return reconcile.Result{}, nil
+	}
+
+	variant, err := getVariant(r.client, instance.Namespace, instance.Spec.Variant)
+	if err != nil {
+		return r.manageError(instance, err)
+	}
+
+	_, ups, err := util.GetPushApplication(r.client, instance.Namespace, variant.pushApplication)
+	if err != nil {
+		return r.manageError(instance, err)
+	}
+
+	if variant.id == "" || !util.IsUnifiedPushServerReady(ups) {
+		reqLogger.Info("Requeuing, variant not ready.", "Variant.Kind", instance.Spec.Variant.Kind, "Variant.Name", instance.Spec.Variant.Name)
+		return r.manageWaiting(instance, fmt.Sprintf("Waiting for %s %s to be ready", instance.Spec.Variant.Kind, instance.Spec.Variant.Name))
+	}
+
+	data, err := r.readSource(instance)
+	if err != nil {
+		return r.manageError(instance, err)
+	}
+
+	//#region Import
+	upsClient := unifiedpush.NewClient(r.unifiedpushURL(ups))
+	batchSize := instance.Spec.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+
+	// Skip whatever was processed by a previous, interrupted, run
+	skip := instance.Status.Imported + instance.Status.Failed + instance.Status.Rejected
+	rejected := 0
+	batch := []json.RawMessage{}
+	sendBatch := func() error {
+		if len(batch) == 0 && rejected == 0 {
+			return nil
+		}
+		if len(batch) > 0 {
+			err := upsClient.ImportDevices(variant.id, variant.secret, batch)
+			if _, isRejected := err.(*unifiedpush.ImportRejectedError); err != nil && !isRejected {
+				// UPS is unreachable or failing, the batch is imported
+				// again once it's back
+				return err
+			}
+			if err != nil {
+				reqLogger.Error(err, "Failed to import a batch of installations", "Count", len(batch))
+				instance.Status.Failed += len(batch)
+			} else {
+				instance.Status.Imported += len(batch)
+			}
+		}
+		instance.Status.Rejected += rejected
+		batch = batch[:0]
+		rejected = 0
+
+		instance.Status.Phase = pushv1alpha1.PhaseReconciling
+		instance.Status.Message = fmt.Sprintf("Imported %d, failed %d, rejected %d", instance.Status.Imported, instance.Status.Failed, instance.Status.Rejected)
+		return r.client.Status().Update(context.TODO(), instance)
+	}
+
+	err = forEachInstallation(bytes.NewReader(data), func(installation json.RawMessage) error {
+		if skip > 0 {
+			skip--
+			return nil
+		}
+
+		if isValidInstallation(installation) {
+			batch = append(batch, installation)
+		} else {
+			rejected++
+		}
+		if len(batch)+rejected >= batchSize {
+			return sendBatch()
+		}
+		return nil
+	})
+	if err == nil {
+		err = sendBatch()
+	}
+	if err != nil {
+		return r.manageError(instance, err)
+	}
+	//#endregion
+
+	now := metav1.Now()
+	instance.Status.CompletedAt = &now
+	instance.Status.Message = fmt.Sprintf("Imported %d, failed %d, rejected %d", instance.Status.Imported, instance.Status.Failed, instance.Status.Rejected)
+	return r.manageSuccess(instance)
+}
+
+// variant holds what's needed to import devices into a variant CR
+type variant struct {
+	pushApplication string
+	id              string
+	secret          string
+}
+
+// getVariant fetches the variant CR of any kind that ref points at
+func getVariant(c client.Client, namespace string, ref pushv1alpha1.VariantReference) (*variant, error) {
+	key := types.NamespacedName{Name: ref.Name, Namespace: namespace}
+	switch ref.Kind {
+	case "AndroidVariant":
+		v := &pushv1alpha1.AndroidVariant{}
+		if err := c.Get(context.TODO(), key, v); err != nil {
+			return nil, err
+		}
+		return &variant{pushApplication: v.Spec.PushApplication, id: v.Status.VariantId, secret: v.Status.Secret}, nil
+	case "IOSTokenVariant":
+		v := &pushv1alpha1.IOSTokenVariant{}
+		if err := c.Get(context.TODO(), key, v); err != nil {
+			return nil, err
+		}
+		return &variant{pushApplication: v.Spec.PushApplication, id: v.Status.VariantId, secret: v.Status.Secret}, nil
+	case "WebPushVariant":
+		v := &pushv1alpha1.WebPushVariant{}
+		if err := c.Get(context.TODO(), key, v); err != nil {
+			return nil, err
+		}
+		return &variant{pushApplication: v.Spec.PushApplication, id: v.Status.VariantId, secret: v.Status.Secret}, nil
+	default:
+		return nil, fmt.Errorf("unknown variant kind %q", ref.Kind)
+	}
+}
+
+// readSource returns the installation JSON from the ConfigMap or
+// Secret key referenced by the DeviceImport
+func (r *ReconcileDeviceImport) readSource(cr *pushv1alpha1.DeviceImport) ([]byte, error) {
+	switch {
+	case cr.Spec.ConfigMapKeyRef != nil && cr.Spec.SecretKeyRef != nil:
+		return nil, fmt.Errorf("only one of configMapKeyRef or secretKeyRef should be set")
+	case cr.Spec.ConfigMapKeyRef != nil:
+		ref := cr.Spec.ConfigMapKeyRef
+		configMap := &corev1.ConfigMap{}
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Name: ref.Name, Namespace: cr.Namespace}, configMap); err != nil {
+			return nil, err
+		}
+		if data, ok := configMap.Data[ref.Key]; ok {
+			return []byte(data), nil
+		}
+		if data, ok := configMap.BinaryData[ref.Key]; ok {
+			return data, nil
+		}
+		return nil, fmt.Errorf("ConfigMap %s has no %s", ref.Name, ref.Key)
+	case cr.Spec.SecretKeyRef != nil:
+		ref := cr.Spec.SecretKeyRef
+		secret := &corev1.Secret{}
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Name: ref.Name, Namespace: cr.Namespace}, secret); err != nil {
+			return nil, err
+		}
+		if data, ok := secret.Data[ref.Key]; ok {
+			return data, nil
+		}
+		return nil, fmt.Errorf("Secret %s has no %s", ref.Name, ref.Key)
+	default:
+		return nil, fmt.Errorf("one of configMapKeyRef or secretKeyRef must be set")
+	}
+}
+
+// forEachInstallation decodes a JSON array of installations one
+// element at a time, so that only a batch of them is decoded at once
+func forEachInstallation(r io.Reader, f func(json.RawMessage) error) error {
+	dec := json.NewDecoder(r)
+	if t, err := dec.Token(); err != nil || t != json.Delim('[') {
+		return fmt.Errorf("installations must be a JSON array")
+	}
+	for dec.More() {
+		installation := json.RawMessage{}
+		if err := dec.Decode(&installation); err != nil {
+			return fmt.Errorf("error decoding installations: %v", err)
+		}
+		if err := f(installation); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isValidInstallation returns true if an installation is an object
+// with a deviceToken, which is the only field UPS requires
+func isValidInstallation(installation json.RawMessage) bool {
+	i := struct {
+		DeviceToken string `json:"deviceToken"`
+	}{}
+	return json.Unmarshal(installation, &i) == nil && i.DeviceToken != ""
+}
+
+func (r *ReconcileDeviceImport) manageError(instance *pushv1alpha1.DeviceImport, issue error) (reconcile.Result, error) {
+	r.recorder.Event(instance, "Warning", "ReconcileFailed", issue.Error())
+
+	instance.Status.Message = issue.Error()
+	instance.Status.Phase = pushv1alpha1.PhaseFailing
+
+	err := r.client.Status().Update(context.TODO(), instance)
+	if err != nil {
+		log.Error(err, "Unable to update status")
+	}
+
+	return reconcile.Result{
+		RequeueAfter: requeueErrorDelay,
+		Requeue:      true,
+	}, nil
+}
+
+func (r *ReconcileDeviceImport) manageWaiting(instance *pushv1alpha1.DeviceImport, message string) (reconcile.Result, error) {
+	instance.Status.Message = message
+	instance.Status.Phase = pushv1alpha1.PhaseInitializing
+
+	err := r.client.Status().Update(context.TODO(), instance)
+	if err != nil {
+		log.Error(err, "Unable to update status")
+	}
+
+	return reconcile.Result{RequeueAfter: requeueDelay}, nil
+}
+
+func (r *ReconcileDeviceImport) manageSuccess(instance *pushv1alpha1.DeviceImport) (reconcile.Result, error) {
+	r.recorder.Event(instance, "Normal", "Completed", instance.Status.Message)
+
+	instance.Status.Phase = pushv1alpha1.PhaseComplete
+
+	err := r.client.Status().Update(context.TODO(), instance)
+	if err != nil {
+		log.Error(err, "Unable to update status")
+		return reconcile.Result{
+			RequeueAfter: requeueErrorDelay,
+			Requeue:      true,
+		}, nil
+	}
+
+	log.Info("Reconcile successful", "DeviceImport.Namespace", instance.Namespace, "DeviceImport.Name", instance.Name)
+	return reconcile.Result{}, nil
+}
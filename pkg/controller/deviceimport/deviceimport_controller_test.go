@@ -0,0 +1,306 @@
+package deviceimport
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	pushv1alpha1 "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1"
+	"github.com/aerogear/unifiedpush-operator/pkg/controller/testutil"
+	"github.com/aerogear/unifiedpush-operator/pkg/unifiedpush"
+	"github.com/aerogear/unifiedpush-operator/pkg/unifiedpush/fake"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+//buildReconcileWithFakeClientWithMocks return reconcile with fake client, schemes and mock objects
+func buildReconcileWithFakeClientWithMocks(objs []runtime.Object, server *fake.Server, t *testing.T) *ReconcileDeviceImport {
+	cl := testutil.NewFakeClient(t, objs...)
+	return &ReconcileDeviceImport{
+		client:         cl,
+		apiReader:      cl,
+		scheme:         scheme.Scheme,
+		recorder:       record.NewFakeRecorder(10),
+		unifiedpushURL: testutil.UnifiedPushURL(server),
+	}
+}
+
+func TestReconcileDeviceImport_Reconcile(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+
+	upsClient := unifiedpush.NewClient(server.URL)
+	upsApp, err := upsClient.CreateApplication(unifiedpush.PushApplication{Name: "example-pushapplication"})
+	if err != nil {
+		t.Fatalf("create application: (%v)", err)
+	}
+	upsVariant, err := upsClient.CreateAndroidVariant(upsApp.PushApplicationID, unifiedpush.AndroidVariant{})
+	if err != nil {
+		t.Fatalf("create variant: (%v)", err)
+	}
+
+	// 5 valid installations and 2 without a deviceToken
+	installations := []string{}
+	for i := 0; i < 5; i++ {
+		installations = append(installations, fmt.Sprintf(`{"deviceToken": "token-%d", "alias": "user-%d"}`, i, i))
+	}
+	installations = append(installations, `{"alias": "no-token"}`, `"not an object"`)
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "devices", Namespace: "unifiedpush"},
+		Data:       map[string]string{"devices.json": "[" + strings.Join(installations, ",") + "]"},
+	}
+
+	deviceImport := &pushv1alpha1.DeviceImport{
+		ObjectMeta: metav1.ObjectMeta{Name: "example-deviceimport", Namespace: "unifiedpush"},
+		Spec: pushv1alpha1.DeviceImportSpec{
+			Variant: pushv1alpha1.VariantReference{Kind: "AndroidVariant", Name: "example-androidvariant"},
+			ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: configMap.Name},
+				Key:                  "devices.json",
+			},
+			BatchSize: 2,
+		},
+	}
+
+	r := buildReconcileWithFakeClientWithMocks([]runtime.Object{
+		testutil.ReadyUnifiedPushServer(),
+		testutil.PushApplication(""),
+		&pushv1alpha1.AndroidVariant{
+			ObjectMeta: metav1.ObjectMeta{Name: "example-androidvariant", Namespace: "unifiedpush"},
+			Spec:       pushv1alpha1.AndroidVariantSpec{PushApplication: "example-pushapplication"},
+			Status: pushv1alpha1.AndroidVariantStatus{
+				PushApplicationId: upsApp.PushApplicationID,
+				VariantId:         upsVariant.VariantID,
+				Secret:            upsVariant.Secret,
+			},
+		},
+		configMap,
+		deviceImport,
+	}, server, t)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: deviceImport.Name, Namespace: deviceImport.Namespace}}
+
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+
+	found := &pushv1alpha1.DeviceImport{}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, found); err != nil {
+		t.Fatalf("get DeviceImport: (%v)", err)
+	}
+	if found.Status.Phase != pushv1alpha1.PhaseComplete || found.Status.CompletedAt == nil {
+		t.Fatalf("expected import to be complete, got %+v", found.Status)
+	}
+	if found.Status.Imported != 5 || found.Status.Failed != 0 || found.Status.Rejected != 2 {
+		t.Errorf("unexpected counts in status: %+v", found.Status)
+	}
+	if devices := server.Devices(upsVariant.VariantID); len(devices) != 5 {
+		t.Errorf("expected 5 devices to be imported, got %d", len(devices))
+	}
+
+	// Never import a completed import again
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	if devices := server.Devices(upsVariant.VariantID); len(devices) != 5 {
+		t.Errorf("expected devices not to be imported again, got %d", len(devices))
+	}
+}
+
+func TestReconcileDeviceImport_Resume(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+
+	upsClient := unifiedpush.NewClient(server.URL)
+	upsApp, err := upsClient.CreateApplication(unifiedpush.PushApplication{Name: "example-pushapplication"})
+	if err != nil {
+		t.Fatalf("create application: (%v)", err)
+	}
+	upsVariant, err := upsClient.CreateWebPushVariant(upsApp.PushApplicationID, unifiedpush.WebPushVariant{})
+	if err != nil {
+		t.Fatalf("create variant: (%v)", err)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "devices", Namespace: "unifiedpush"},
+		Data:       map[string][]byte{"devices.json": []byte(`[{"deviceToken": "a"}, {"deviceToken": "b"}, {"deviceToken": "c"}]`)},
+	}
+
+	// The first installation was imported before the operator restarted
+	deviceImport := &pushv1alpha1.DeviceImport{
+		ObjectMeta: metav1.ObjectMeta{Name: "example-deviceimport", Namespace: "unifiedpush"},
+		Spec: pushv1alpha1.DeviceImportSpec{
+			Variant: pushv1alpha1.VariantReference{Kind: "WebPushVariant", Name: "example-webpushvariant"},
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secret.Name},
+				Key:                  "devices.json",
+			},
+		},
+		Status: pushv1alpha1.DeviceImportStatus{
+			Phase:    pushv1alpha1.PhaseReconciling,
+			Imported: 1,
+		},
+	}
+
+	r := buildReconcileWithFakeClientWithMocks([]runtime.Object{
+		testutil.ReadyUnifiedPushServer(),
+		testutil.PushApplication(""),
+		&pushv1alpha1.WebPushVariant{
+			ObjectMeta: metav1.ObjectMeta{Name: "example-webpushvariant", Namespace: "unifiedpush"},
+			Spec:       pushv1alpha1.WebPushVariantSpec{PushApplication: "example-pushapplication"},
+			Status: pushv1alpha1.WebPushVariantStatus{
+				PushApplicationId: upsApp.PushApplicationID,
+				VariantId:         upsVariant.VariantID,
+				Secret:            upsVariant.Secret,
+			},
+		},
+		secret,
+		deviceImport,
+	}, server, t)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: deviceImport.Name, Namespace: deviceImport.Namespace}}
+
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+
+	found := &pushv1alpha1.DeviceImport{}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, found); err != nil {
+		t.Fatalf("get DeviceImport: (%v)", err)
+	}
+	if found.Status.Imported != 3 {
+		t.Errorf("expected 3 imported installations, got %+v", found.Status)
+	}
+	devices := server.Devices(upsVariant.VariantID)
+	if len(devices) != 2 || string(devices[0]) != `{"deviceToken":"b"}` {
+		t.Errorf("expected only the remaining devices to be imported, got %s", devices)
+	}
+}
+
+func TestReconcileDeviceImport_Failed(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+
+	upsApp, err := unifiedpush.NewClient(server.URL).CreateApplication(unifiedpush.PushApplication{Name: "example-pushapplication"})
+	if err != nil {
+		t.Fatalf("create application: (%v)", err)
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "devices", Namespace: "unifiedpush"},
+		Data:       map[string]string{"devices.json": `[{"deviceToken": "a"}, {"deviceToken": "b"}]`},
+	}
+	deviceImport := &pushv1alpha1.DeviceImport{
+		ObjectMeta: metav1.ObjectMeta{Name: "example-deviceimport", Namespace: "unifiedpush"},
+		Spec: pushv1alpha1.DeviceImportSpec{
+			Variant: pushv1alpha1.VariantReference{Kind: "IOSTokenVariant", Name: "example-iostokenvariant"},
+			ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: configMap.Name},
+				Key:                  "devices.json",
+			},
+		},
+	}
+
+	// The variant secret doesn't match, so UPS rejects the batch
+	r := buildReconcileWithFakeClientWithMocks([]runtime.Object{
+		testutil.ReadyUnifiedPushServer(),
+		testutil.PushApplication(""),
+		&pushv1alpha1.IOSTokenVariant{
+			ObjectMeta: metav1.ObjectMeta{Name: "example-iostokenvariant", Namespace: "unifiedpush"},
+			Spec:       pushv1alpha1.IOSTokenVariantSpec{PushApplication: "example-pushapplication"},
+			Status: pushv1alpha1.IOSTokenVariantStatus{
+				PushApplicationId: upsApp.PushApplicationID,
+				VariantId:         "unknown",
+				Secret:            "unknown",
+			},
+		},
+		configMap,
+		deviceImport,
+	}, server, t)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: deviceImport.Name, Namespace: deviceImport.Namespace}}
+
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+
+	found := &pushv1alpha1.DeviceImport{}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, found); err != nil {
+		t.Fatalf("get DeviceImport: (%v)", err)
+	}
+	if found.Status.Imported != 0 || found.Status.Failed != 2 || found.Status.Phase != pushv1alpha1.PhaseComplete {
+		t.Errorf("expected 2 failed installations, got %+v", found.Status)
+	}
+}
+
+func TestReconcileDeviceImport_Unreachable(t *testing.T) {
+	server := fake.NewServer()
+
+	upsClient := unifiedpush.NewClient(server.URL)
+	upsApp, err := upsClient.CreateApplication(unifiedpush.PushApplication{Name: "example-pushapplication"})
+	if err != nil {
+		t.Fatalf("create application: (%v)", err)
+	}
+	upsVariant, err := upsClient.CreateAndroidVariant(upsApp.PushApplicationID, unifiedpush.AndroidVariant{})
+	if err != nil {
+		t.Fatalf("create variant: (%v)", err)
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "devices", Namespace: "unifiedpush"},
+		Data:       map[string]string{"devices.json": `[{"deviceToken": "a"}, {"deviceToken": "b"}]`},
+	}
+	deviceImport := &pushv1alpha1.DeviceImport{
+		ObjectMeta: metav1.ObjectMeta{Name: "example-deviceimport", Namespace: "unifiedpush"},
+		Spec: pushv1alpha1.DeviceImportSpec{
+			Variant: pushv1alpha1.VariantReference{Kind: "AndroidVariant", Name: "example-androidvariant"},
+			ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: configMap.Name},
+				Key:                  "devices.json",
+			},
+		},
+	}
+
+	r := buildReconcileWithFakeClientWithMocks([]runtime.Object{
+		testutil.ReadyUnifiedPushServer(),
+		testutil.PushApplication(""),
+		&pushv1alpha1.AndroidVariant{
+			ObjectMeta: metav1.ObjectMeta{Name: "example-androidvariant", Namespace: "unifiedpush"},
+			Spec:       pushv1alpha1.AndroidVariantSpec{PushApplication: "example-pushapplication"},
+			Status: pushv1alpha1.AndroidVariantStatus{
+				PushApplicationId: upsApp.PushApplicationID,
+				VariantId:         upsVariant.VariantID,
+				Secret:            upsVariant.Secret,
+			},
+		},
+		configMap,
+		deviceImport,
+	}, server, t)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: deviceImport.Name, Namespace: deviceImport.Namespace}}
+
+	// UPS goes away
+	server.Close()
+
+	res, err := r.Reconcile(req)
+	if err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	if !res.Requeue {
+		t.Error("expected reconcile to be requeued")
+	}
+
+	found := &pushv1alpha1.DeviceImport{}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, found); err != nil {
+		t.Fatalf("get DeviceImport: (%v)", err)
+	}
+	if found.Status.CompletedAt != nil || found.Status.Phase != pushv1alpha1.PhaseFailing {
+		t.Errorf("expected import not to be completed, got %+v", found.Status)
+	}
+	if found.Status.Imported != 0 || found.Status.Failed != 0 {
+		t.Errorf("expected no installations to be counted, got %+v", found.Status)
+	}
+}
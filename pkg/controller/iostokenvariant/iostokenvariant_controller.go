@@ -0,0 +1,321 @@
+package iostokenvariant
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	pushv1alpha1 "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1"
+	"github.com/aerogear/unifiedpush-operator/pkg/controller/util"
+	"github.com/aerogear/unifiedpush-operator/pkg/unifiedpush"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	controllerName    = "iostokenvariant-controller"
+	variantType       = "ios_token"
+	requeueDelay      = 30 * time.Second
+	requeueErrorDelay = 5 * time.Second
+)
+
+var log = logf.Log.WithName(controllerName)
+
+// Add creates a new IOSTokenVariant Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileIOSTokenVariant{
+		client:         mgr.GetClient(),
+		scheme:         mgr.GetScheme(),
+		recorder:       mgr.GetRecorder(controllerName),
+		unifiedpushURL: util.UnifiedPushServerURL,
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource IOSTokenVariant
+	err = c.Watch(&source.Kind{Type: &pushv1alpha1.IOSTokenVariant{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the credentials Secrets, which aren't owned
+	// by the IOSTokenVariants, and requeue the ones that reference them
+	err = c.Watch(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+			return variantsForSecret(mgr.GetClient(), o.Meta.GetNamespace(), o.Meta.GetName())
+		}),
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// variantsForSecret returns a request for each IOSTokenVariant that
+// takes its credentials from the given Secret
+func variantsForSecret(c client.Client, namespace string, name string) []reconcile.Request {
+	variants := &pushv1alpha1.IOSTokenVariantList{}
+	if err := c.List(context.TODO(), client.InNamespace(namespace), variants); err != nil {
+		log.Error(err, "Failed to list IOSTokenVariants", "Namespace", namespace)
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, v := range variants.Items {
+		if v.Spec.CredentialsSecret == name {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: v.Name, Namespace: v.Namespace}})
+		}
+	}
+	return requests
+}
+
+var _ reconcile.Reconciler = &ReconcileIOSTokenVariant{}
+
+// ReconcileIOSTokenVariant reconciles a IOSTokenVariant object
+type ReconcileIOSTokenVariant struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	client   client.Client
+	scheme   *runtime.Scheme
+	recorder record.EventRecorder
+
+	unifiedpushURL util.UnifiedPushURLFunc
+}
+
+// Reconcile makes sure that an iOS token variant exists in the
+// application of the referenced PushApplication, with the APNs
+// token credentials from the referenced Secret.
+func (r *ReconcileIOSTokenVariant) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling IOSTokenVariant")
+
+	// Fetch the IOSTokenVariant instance
+	instance := &pushv1alpha1.IOSTokenVariant{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Return and don't requeue
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return r.manageError(instance, err)
+	}
+
+	app, ups, err := util.GetPushApplication(r.client, instance.Namespace, instance.Spec.PushApplication)
+	if err != nil {
+		if errors.IsNotFound(err) && instance.DeletionTimestamp != nil {
+			// The application is gone from UPS, and so are its variants
+			reqLogger.Info("PushApplication or UnifiedPushServer not found, nothing to clean up", "PushApplication.Name", instance.Spec.PushApplication)
+			return reconcile.Result{}, util.RemoveFinalizer(r.client, reqLogger, instance)
+		}
+		return r.manageError(instance, err)
+	}
+	upsClient := unifiedpush.NewClient(r.unifiedpushURL(ups))
+
+	if instance.DeletionTimestamp != nil {
+		if instance.Status.VariantId != "" {
+			reqLogger.Info("Deleting variant from UPS", "VariantId", instance.Status.VariantId)
+			if err := upsClient.DeleteVariant(instance.Status.PushApplicationId, variantType, instance.Status.VariantId); err != nil {
+				return r.manageError(instance, err)
+			}
+		}
+		return reconcile.Result{}, util.RemoveFinalizer(r.client, reqLogger, instance)
+	}
+
+	if err := util.AddFinalizer(r.client, reqLogger, instance); err != nil {
+		return r.manageError(instance, err)
+	}
+
+	if app.Status.PushApplicationId == "" || !util.IsUnifiedPushServerReady(ups) {
+		reqLogger.Info("Requeuing, PushApplication not ready.", "PushApplication.Name", app.Name)
+		return r.manageWaiting(instance, fmt.Sprintf("Waiting for PushApplication %s to be ready", app.Name))
+	}
+
+	credentials := &corev1.Secret{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: instance.Spec.CredentialsSecret, Namespace: instance.Namespace}, credentials)
+	if err != nil {
+		return r.manageError(instance, err)
+	}
+
+	desiredVariant, err := newIOSTokenVariant(instance, credentials)
+	if err != nil {
+		return r.manageError(instance, err)
+	}
+
+	// Variants imported from UPS take over the existing variant
+	if instance.Status.VariantId == "" && util.AdoptedID(instance) != "" {
+		instance.Status.VariantId = util.AdoptedID(instance)
+		instance.Status.PushApplicationId = app.Status.PushApplicationId
+	}
+
+	credentialsHash := util.CredentialsHash(desiredVariant.PrivateKey)
+
+	//#region UPS variant
+	// The variant of a previous application, e.g. when the
+	// PushApplication has been recreated, is deleted rather than left
+	// behind in UPS
+	if instance.Status.VariantId != "" && instance.Status.PushApplicationId != app.Status.PushApplicationId {
+		reqLogger.Info("Deleting variant of the previous application from UPS", "PushApplicationId", instance.Status.PushApplicationId, "VariantId", instance.Status.VariantId)
+		if err := upsClient.DeleteVariant(instance.Status.PushApplicationId, variantType, instance.Status.VariantId); err != nil {
+			return r.manageError(instance, err)
+		}
+		instance.Status.VariantId = ""
+		instance.Status.Secret = ""
+		instance.Status.CredentialsHash = ""
+	}
+
+	var variant *unifiedpush.IOSTokenVariant
+	if instance.Status.VariantId != "" {
+		variant, err = upsClient.GetIOSTokenVariant(app.Status.PushApplicationId, instance.Status.VariantId)
+		if err != nil {
+			return r.manageError(instance, err)
+		}
+	}
+
+	if variant == nil {
+		reqLogger.Info("Creating a new iOS token variant in UPS", "PushApplicationId", app.Status.PushApplicationId)
+		variant, err = upsClient.CreateIOSTokenVariant(app.Status.PushApplicationId, desiredVariant)
+		if err != nil {
+			return r.manageError(instance, err)
+		}
+
+		// Save the id straight away, so we don't create the variant
+		// twice if something below fails
+		instance.Status.PushApplicationId = app.Status.PushApplicationId
+		instance.Status.VariantId = variant.VariantID
+		instance.Status.Secret = variant.Secret
+		instance.Status.CredentialsHash = credentialsHash
+		err = r.client.Status().Update(context.TODO(), instance)
+		if err != nil {
+			reqLogger.Error(err, "Failed to update IOSTokenVariant status", "VariantId", variant.VariantID)
+			return r.manageError(instance, err)
+		}
+	} else if variant.Name != desiredVariant.Name ||
+		variant.Description != desiredVariant.Description ||
+		variant.Production != desiredVariant.Production ||
+		instance.Status.CredentialsHash != credentialsHash ||
+		variant.KeyID != desiredVariant.KeyID ||
+		variant.TeamID != desiredVariant.TeamID ||
+		variant.BundleID != desiredVariant.BundleID {
+		reqLogger.Info("Variant in UPS is different than the IOSTokenVariant spec. Going to update it now.", "VariantId", variant.VariantID)
+		desiredVariant.VariantID = variant.VariantID
+		if err := upsClient.UpdateIOSTokenVariant(app.Status.PushApplicationId, desiredVariant); err != nil {
+			return r.manageError(instance, err)
+		}
+	}
+	//#endregion
+
+	instance.Status.PushApplicationId = app.Status.PushApplicationId
+	instance.Status.VariantId = variant.VariantID
+	instance.Status.Secret = variant.Secret
+	instance.Status.CredentialsHash = credentialsHash
+	return r.manageSuccess(instance)
+}
+
+func (r *ReconcileIOSTokenVariant) manageError(instance *pushv1alpha1.IOSTokenVariant, issue error) (reconcile.Result, error) {
+	r.recorder.Event(instance, "Warning", "ReconcileFailed", issue.Error())
+
+	instance.Status.Message = issue.Error()
+	instance.Status.Phase = pushv1alpha1.PhaseFailing
+
+	err := r.client.Status().Update(context.TODO(), instance)
+	if err != nil {
+		log.Error(err, "Unable to update status")
+	}
+
+	return reconcile.Result{
+		RequeueAfter: requeueErrorDelay,
+		Requeue:      true,
+	}, nil
+}
+
+func (r *ReconcileIOSTokenVariant) manageWaiting(instance *pushv1alpha1.IOSTokenVariant, message string) (reconcile.Result, error) {
+	instance.Status.Message = message
+	instance.Status.Phase = pushv1alpha1.PhaseInitializing
+
+	err := r.client.Status().Update(context.TODO(), instance)
+	if err != nil {
+		log.Error(err, "Unable to update status")
+	}
+
+	return reconcile.Result{RequeueAfter: requeueDelay}, nil
+}
+
+func (r *ReconcileIOSTokenVariant) manageSuccess(instance *pushv1alpha1.IOSTokenVariant) (reconcile.Result, error) {
+	instance.Status.Message = ""
+	instance.Status.Phase = pushv1alpha1.PhaseReconciling
+
+	err := r.client.Status().Update(context.TODO(), instance)
+	if err != nil {
+		log.Error(err, "Unable to update status")
+		return reconcile.Result{
+			RequeueAfter: requeueErrorDelay,
+			Requeue:      true,
+		}, nil
+	}
+
+	log.Info("Reconcile successful", "IOSTokenVariant.Namespace", instance.Namespace, "IOSTokenVariant.Name", instance.Name)
+	return reconcile.Result{}, nil
+}
+
+// newIOSTokenVariant builds the UPS representation of the variant from
+// the CR and its credentials Secret
+func newIOSTokenVariant(cr *pushv1alpha1.IOSTokenVariant, credentials *corev1.Secret) (unifiedpush.IOSTokenVariant, error) {
+	values := map[string]string{}
+	for _, key := range []string{"privateKey", "keyId", "teamId", "bundleId"} {
+		value, ok := credentials.Data[key]
+		if !ok {
+			return unifiedpush.IOSTokenVariant{}, fmt.Errorf("Secret %s has no %s", credentials.Name, key)
+		}
+		values[key] = string(value)
+	}
+
+	production := false
+	if value, ok := credentials.Data["production"]; ok {
+		var err error
+		production, err = strconv.ParseBool(string(value))
+		if err != nil {
+			return unifiedpush.IOSTokenVariant{}, fmt.Errorf("Secret %s has an invalid production value: %v", credentials.Name, err)
+		}
+	}
+
+	return unifiedpush.IOSTokenVariant{
+		Variant: unifiedpush.Variant{
+			Name:        util.NameInUPS(cr, cr.Spec.Name),
+			Description: cr.Spec.Description,
+		},
+		Production: production,
+		PrivateKey: values["privateKey"],
+		KeyID:      values["keyId"],
+		TeamID:     values["teamId"],
+		BundleID:   values["bundleId"],
+	}, nil
+}
@@ -0,0 +1,246 @@
+package iostokenvariant
+
+import (
+	"context"
+	"testing"
+
+	pushv1alpha1 "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1"
+	"github.com/aerogear/unifiedpush-operator/pkg/controller/testutil"
+	"github.com/aerogear/unifiedpush-operator/pkg/unifiedpush"
+	"github.com/aerogear/unifiedpush-operator/pkg/unifiedpush/fake"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+//buildReconcileWithFakeClientWithMocks return reconcile with fake client, schemes and mock objects
+func buildReconcileWithFakeClientWithMocks(objs []runtime.Object, server *fake.Server, t *testing.T) *ReconcileIOSTokenVariant {
+	return &ReconcileIOSTokenVariant{
+		client:         testutil.NewFakeClient(t, objs...),
+		scheme:         scheme.Scheme,
+		recorder:       record.NewFakeRecorder(10),
+		unifiedpushURL: testutil.UnifiedPushURL(server),
+	}
+}
+
+func TestReconcileIOSTokenVariant_Reconcile(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+
+	upsApp, err := unifiedpush.NewClient(server.URL).CreateApplication(unifiedpush.PushApplication{Name: "example-pushapplication"})
+	if err != nil {
+		t.Fatalf("create application: (%v)", err)
+	}
+
+	variant := iosTokenVariant()
+	r := buildReconcileWithFakeClientWithMocks([]runtime.Object{
+		testutil.ReadyUnifiedPushServer(),
+		testutil.PushApplication(upsApp.PushApplicationID),
+		credentialsSecret(),
+		variant,
+	}, server, t)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: variant.Name, Namespace: variant.Namespace}}
+
+	// Create
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+
+	found := &pushv1alpha1.IOSTokenVariant{}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, found); err != nil {
+		t.Fatalf("get IOSTokenVariant: (%v)", err)
+	}
+	if found.Status.Phase != pushv1alpha1.PhaseReconciling {
+		t.Fatalf("expected phase %s, got %s: %s", pushv1alpha1.PhaseReconciling, found.Status.Phase, found.Status.Message)
+	}
+	if found.Status.PushApplicationId != upsApp.PushApplicationID {
+		t.Errorf("expected pushApplicationId %s, got %s", upsApp.PushApplicationID, found.Status.PushApplicationId)
+	}
+
+	upsVariant := &unifiedpush.IOSTokenVariant{}
+	if !server.Variant(found.Status.VariantId, upsVariant) {
+		t.Fatalf("variant %s was not created in UPS", found.Status.VariantId)
+	}
+	if upsVariant.Secret != found.Status.Secret {
+		t.Errorf("expected variant secret %s in status, got %s", upsVariant.Secret, found.Status.Secret)
+	}
+	if upsVariant.PrivateKey != "private-key" || upsVariant.KeyID != "key-id" || upsVariant.TeamID != "team-id" || upsVariant.BundleID != "org.aerogear.example" || upsVariant.Production {
+		t.Errorf("unexpected variant in UPS: %+v", upsVariant)
+	}
+
+	// Update when the credentials change
+	secret := credentialsSecret()
+	secret.Data["production"] = []byte("true")
+	if err := r.client.Update(context.TODO(), secret); err != nil {
+		t.Fatalf("update Secret: (%v)", err)
+	}
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	upsVariant = &unifiedpush.IOSTokenVariant{}
+	server.Variant(found.Status.VariantId, upsVariant)
+	if !upsVariant.Production {
+		t.Errorf("expected production to be updated in UPS, got %+v", upsVariant)
+	}
+	if requests := variantsForSecret(r.client, secret.Namespace, secret.Name); len(requests) != 1 || requests[0] != req {
+		t.Errorf("expected Secret to map to %v, got %v", req, requests)
+	}
+
+	// Delete
+	if err := r.client.Get(context.TODO(), req.NamespacedName, found); err != nil {
+		t.Fatalf("get IOSTokenVariant: (%v)", err)
+	}
+	now := metav1.Now()
+	found.DeletionTimestamp = &now
+	if err := r.client.Update(context.TODO(), found); err != nil {
+		t.Fatalf("update IOSTokenVariant: (%v)", err)
+	}
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	if ids := server.VariantIDs(upsApp.PushApplicationID, variantType); len(ids) != 0 {
+		t.Errorf("expected variant to be deleted from UPS, got %v", ids)
+	}
+	deleted := &pushv1alpha1.IOSTokenVariant{}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, deleted); err != nil {
+		t.Fatalf("get IOSTokenVariant: (%v)", err)
+	}
+	if len(deleted.Finalizers) != 0 {
+		t.Errorf("expected finalizer to be removed, got %v", deleted.Finalizers)
+	}
+}
+
+func TestReconcileIOSTokenVariant_MissingCredentials(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+
+	secret := credentialsSecret()
+	delete(secret.Data, "keyId")
+	variant := iosTokenVariant()
+	r := buildReconcileWithFakeClientWithMocks([]runtime.Object{
+		testutil.ReadyUnifiedPushServer(),
+		testutil.PushApplication("some-id"),
+		secret,
+		variant,
+	}, server, t)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: variant.Name, Namespace: variant.Namespace}}
+
+	res, err := r.Reconcile(req)
+	if err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	if !res.Requeue {
+		t.Error("expected reconcile to be requeued")
+	}
+
+	found := &pushv1alpha1.IOSTokenVariant{}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, found); err != nil {
+		t.Fatalf("get IOSTokenVariant: (%v)", err)
+	}
+	if found.Status.Phase != pushv1alpha1.PhaseFailing {
+		t.Errorf("expected phase %s, got %s", pushv1alpha1.PhaseFailing, found.Status.Phase)
+	}
+}
+
+func TestReconcileIOSTokenVariant_PushApplicationChanged(t *testing.T) {
+	testutil.TestPushApplicationChanged(t, testutil.VariantController{
+		VariantType: variantType,
+		Variant:     iosTokenVariant(),
+		Objects:     []runtime.Object{credentialsSecret()},
+		NewReconciler: func(c client.Client, server *fake.Server) reconcile.Reconciler {
+			return &ReconcileIOSTokenVariant{
+				client:         c,
+				scheme:         scheme.Scheme,
+				recorder:       record.NewFakeRecorder(10),
+				unifiedpushURL: testutil.UnifiedPushURL(server),
+			}
+		},
+		Status: func(o runtime.Object) (string, string) {
+			variant := o.(*pushv1alpha1.IOSTokenVariant)
+			return variant.Status.PushApplicationId, variant.Status.VariantId
+		},
+	})
+}
+
+func TestReconcileIOSTokenVariant_PrivateKeyNotReturned(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+	server.HideVariantFields("privateKey")
+
+	upsApp, err := unifiedpush.NewClient(server.URL).CreateApplication(unifiedpush.PushApplication{Name: "example-pushapplication"})
+	if err != nil {
+		t.Fatalf("create application: (%v)", err)
+	}
+
+	variant := iosTokenVariant()
+	r := buildReconcileWithFakeClientWithMocks([]runtime.Object{
+		testutil.ReadyUnifiedPushServer(),
+		testutil.PushApplication(upsApp.PushApplicationID),
+		credentialsSecret(),
+		variant,
+	}, server, t)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: variant.Name, Namespace: variant.Namespace}}
+
+	for i := 0; i < 2; i++ {
+		if _, err := r.Reconcile(req); err != nil {
+			t.Fatalf("reconcile: (%v)", err)
+		}
+	}
+	found := &pushv1alpha1.IOSTokenVariant{}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, found); err != nil {
+		t.Fatalf("get IOSTokenVariant: (%v)", err)
+	}
+	if updates := server.VariantUpdates(found.Status.VariantId); updates != 0 {
+		t.Errorf("expected the variant not to be updated while the private key is the same, got %d updates", updates)
+	}
+
+	// A new private key is still sent
+	secret := credentialsSecret()
+	secret.Data["privateKey"] = []byte("new-private-key")
+	if err := r.client.Update(context.TODO(), secret); err != nil {
+		t.Fatalf("update Secret: (%v)", err)
+	}
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	upsVariant := &unifiedpush.IOSTokenVariant{}
+	server.Variant(found.Status.VariantId, upsVariant)
+	if upsVariant.PrivateKey != "new-private-key" || server.VariantUpdates(found.Status.VariantId) != 1 {
+		t.Errorf("expected the new private key to be sent to UPS once, got %+v", upsVariant)
+	}
+}
+
+func credentialsSecret() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "example-apns-credentials",
+			Namespace: "unifiedpush",
+		},
+		Data: map[string][]byte{
+			"privateKey": []byte("private-key"),
+			"keyId":      []byte("key-id"),
+			"teamId":     []byte("team-id"),
+			"bundleId":   []byte("org.aerogear.example"),
+		},
+	}
+}
+
+func iosTokenVariant() *pushv1alpha1.IOSTokenVariant {
+	return &pushv1alpha1.IOSTokenVariant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "example-iostokenvariant",
+			Namespace: "unifiedpush",
+		},
+		Spec: pushv1alpha1.IOSTokenVariantSpec{
+			Description:       "An example iOS token variant",
+			PushApplication:   "example-pushapplication",
+			CredentialsSecret: "example-apns-credentials",
+		},
+	}
+}
@@ -0,0 +1,150 @@
+package pushapplication
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	pushv1alpha1 "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const mobileServicesKey = "mobile-services.json"
+
+// mobileServices is the mobile-services.json format read by the
+// AeroGear mobile SDKs
+type mobileServices struct {
+	Version   int                    `json:"version"`
+	Namespace string                 `json:"namespace"`
+	ClientID  string                 `json:"clientId"`
+	Services  []mobileServicesConfig `json:"services"`
+}
+
+type mobileServicesConfig struct {
+	ID     string                       `json:"id"`
+	Name   string                       `json:"name"`
+	Type   string                       `json:"type"`
+	URL    string                       `json:"url"`
+	Config map[string]map[string]string `json:"config"`
+}
+
+func mobileServicesSecretName(cr *pushv1alpha1.PushApplication) string {
+	return fmt.Sprintf("%s-mobile-services", cr.Name)
+}
+
+// pushConfig collects the client configuration of all the variants
+// that have been created in the application of the given
+// PushApplication, keyed by platform. Clients only read one variant
+// per platform, so when there are several, the one whose CR name comes
+// first is used. An Android variant whose credentials can't be read is
+// left out, rather than failing the whole application.
+func pushConfig(c client.Client, cr *pushv1alpha1.PushApplication) (map[string]map[string]string, error) {
+	reqLogger := log.WithValues("Request.Namespace", cr.Namespace, "Request.Name", cr.Name)
+	config := map[string]map[string]string{}
+	names := map[string]string{}
+	add := func(platform string, name string, variantConfig map[string]string) {
+		if existing, ok := names[platform]; ok && existing < name {
+			return
+		}
+		names[platform] = name
+		config[platform] = variantConfig
+	}
+	belongs := func(pushApplication string, pushApplicationID string, variantID string) bool {
+		return pushApplication == cr.Name && pushApplicationID == cr.Status.PushApplicationId && variantID != ""
+	}
+
+	androidVariants := &pushv1alpha1.AndroidVariantList{}
+	if err := c.List(context.TODO(), client.InNamespace(cr.Namespace), androidVariants); err != nil {
+		return nil, err
+	}
+	for _, v := range androidVariants.Items {
+		if !belongs(v.Spec.PushApplication, v.Status.PushApplicationId, v.Status.VariantId) {
+			continue
+		}
+		credentials := &corev1.Secret{}
+		err := c.Get(context.TODO(), types.NamespacedName{Name: v.Spec.CredentialsSecret, Namespace: v.Namespace}, credentials)
+		if err != nil {
+			reqLogger.Error(err, "Leaving AndroidVariant out of the mobile services config", "AndroidVariant.Name", v.Name)
+			continue
+		}
+		add("android", v.Name, map[string]string{
+			"variantId":     v.Status.VariantId,
+			"variantSecret": v.Status.Secret,
+			"senderId":      string(credentials.Data["senderId"]),
+		})
+	}
+
+	iosTokenVariants := &pushv1alpha1.IOSTokenVariantList{}
+	if err := c.List(context.TODO(), client.InNamespace(cr.Namespace), iosTokenVariants); err != nil {
+		return nil, err
+	}
+	for _, v := range iosTokenVariants.Items {
+		if !belongs(v.Spec.PushApplication, v.Status.PushApplicationId, v.Status.VariantId) {
+			continue
+		}
+		add("ios", v.Name, map[string]string{
+			"variantId":     v.Status.VariantId,
+			"variantSecret": v.Status.Secret,
+		})
+	}
+
+	webPushVariants := &pushv1alpha1.WebPushVariantList{}
+	if err := c.List(context.TODO(), client.InNamespace(cr.Namespace), webPushVariants); err != nil {
+		return nil, err
+	}
+	for _, v := range webPushVariants.Items {
+		if !belongs(v.Spec.PushApplication, v.Status.PushApplicationId, v.Status.VariantId) {
+			continue
+		}
+		add("web_push", v.Name, map[string]string{
+			"variantId":     v.Status.VariantId,
+			"variantSecret": v.Status.Secret,
+			"appServerKey":  v.Status.PublicKey,
+		})
+	}
+
+	return config, nil
+}
+
+// newServices returns the services of the mobile-services.json of a
+// PushApplication, which is a single push service for all of its
+// variants, or none if it has no variants yet
+func newServices(cr *pushv1alpha1.PushApplication, url string, config map[string]map[string]string) []mobileServicesConfig {
+	if len(config) == 0 {
+		return []mobileServicesConfig{}
+	}
+	return []mobileServicesConfig{
+		{
+			ID:     cr.Name,
+			Name:   "push",
+			Type:   "push",
+			URL:    url,
+			Config: config,
+		},
+	}
+}
+
+func reconcileMobileServicesSecret(secret *corev1.Secret, cr *pushv1alpha1.PushApplication, services []mobileServicesConfig) error {
+	b, err := json.MarshalIndent(mobileServices{
+		Version:   1,
+		Namespace: cr.Namespace,
+		ClientID:  cr.Name,
+		Services:  services,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	secret.ObjectMeta.Labels = map[string]string{
+		"app":             cr.Spec.UnifiedPushServer,
+		"pushapplication": cr.Name,
+	}
+	// The variant secrets are in there, so it's not a ConfigMap
+	secret.Data = map[string][]byte{
+		mobileServicesKey: b,
+	}
+	return nil
+}
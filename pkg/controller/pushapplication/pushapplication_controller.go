@@ -0,0 +1,363 @@
+package pushapplication
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	pushv1alpha1 "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1"
+	"github.com/aerogear/unifiedpush-operator/pkg/controller/util"
+	"github.com/aerogear/unifiedpush-operator/pkg/unifiedpush"
+
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	controllerName    = "pushapplication-controller"
+	requeueDelay      = 30 * time.Second
+	requeueErrorDelay = 5 * time.Second
+)
+
+var log = logf.Log.WithName(controllerName)
+
+// Add creates a new PushApplication Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcilePushApplication{
+		client:         mgr.GetClient(),
+		scheme:         mgr.GetScheme(),
+		recorder:       mgr.GetRecorder(controllerName),
+		unifiedpushURL: util.UnifiedPushServerURL,
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource PushApplication
+	err = c.Watch(&source.Kind{Type: &pushv1alpha1.PushApplication{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to secondary resource Secret and requeue the owner PushApplication
+	err = c.Watch(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &pushv1alpha1.PushApplication{},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the variants, which aren't owned by the
+	// PushApplication, and requeue the one they belong to, so that
+	// the mobile services config stays up to date
+	variantHandler := &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(applicationForVariant),
+	}
+	for _, variant := range []runtime.Object{&pushv1alpha1.AndroidVariant{}, &pushv1alpha1.IOSTokenVariant{}, &pushv1alpha1.WebPushVariant{}} {
+		err = c.Watch(&source.Kind{Type: variant}, variantHandler)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Watch for changes to the UnifiedPushServer Routes, or Ingresses
+	// on clusters without Routes, and requeue the PushApplications
+	// registered with that UnifiedPushServer
+	serverHandler := &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+			return applicationsForServer(mgr.GetClient(), o.Meta.GetNamespace(), o.Meta.GetLabels()["app"])
+		}),
+	}
+	err = c.Watch(&source.Kind{Type: &routev1.Route{}}, serverHandler)
+	if _, isNoKindMatchError := err.(*meta.NoKindMatchError); err != nil && !isNoKindMatchError {
+		return err
+	}
+	err = c.Watch(&source.Kind{Type: &extensionsv1beta1.Ingress{}}, serverHandler)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// applicationForVariant returns a request for the PushApplication that
+// a variant belongs to, if it names one
+func applicationForVariant(o handler.MapObject) []reconcile.Request {
+	var name string
+	switch v := o.Object.(type) {
+	case *pushv1alpha1.AndroidVariant:
+		name = v.Spec.PushApplication
+	case *pushv1alpha1.IOSTokenVariant:
+		name = v.Spec.PushApplication
+	case *pushv1alpha1.WebPushVariant:
+		name = v.Spec.PushApplication
+	}
+	if name == "" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: name, Namespace: o.Meta.GetNamespace()}}}
+}
+
+// applicationsForServer returns a request for each PushApplication
+// registered with the given UnifiedPushServer
+func applicationsForServer(c client.Client, namespace string, name string) []reconcile.Request {
+	apps := &pushv1alpha1.PushApplicationList{}
+	if err := c.List(context.TODO(), client.InNamespace(namespace), apps); err != nil {
+		log.Error(err, "Failed to list PushApplications", "Namespace", namespace)
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, app := range apps.Items {
+		if app.Spec.UnifiedPushServer == name {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: app.Name, Namespace: app.Namespace}})
+		}
+	}
+	return requests
+}
+
+var _ reconcile.Reconciler = &ReconcilePushApplication{}
+
+// ReconcilePushApplication reconciles a PushApplication object
+type ReconcilePushApplication struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	client   client.Client
+	scheme   *runtime.Scheme
+	recorder record.EventRecorder
+
+	unifiedpushURL util.UnifiedPushURLFunc
+}
+
+// Reconcile makes sure that an application exists in the referenced
+// UnifiedPush Server for each PushApplication, that its credentials
+// are available in a Secret, and that the client configuration of its
+// variants is available in a mobile-services.json Secret.
+func (r *ReconcilePushApplication) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling PushApplication")
+
+	// Fetch the PushApplication instance
+	instance := &pushv1alpha1.PushApplication{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Return and don't requeue
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return r.manageError(instance, err)
+	}
+
+	ups := &pushv1alpha1.UnifiedPushServer{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: instance.Spec.UnifiedPushServer, Namespace: instance.Namespace}, ups)
+	if err != nil {
+		if errors.IsNotFound(err) && instance.DeletionTimestamp != nil {
+			// The server is gone, and so is the app
+			reqLogger.Info("UnifiedPushServer not found, nothing to clean up", "UnifiedPushServer.Name", instance.Spec.UnifiedPushServer)
+			return reconcile.Result{}, util.RemoveFinalizer(r.client, reqLogger, instance)
+		}
+		return r.manageError(instance, err)
+	}
+	upsClient := unifiedpush.NewClient(r.unifiedpushURL(ups))
+
+	if instance.DeletionTimestamp != nil {
+		if instance.Status.PushApplicationId != "" {
+			reqLogger.Info("Deleting application from UPS", "PushApplicationId", instance.Status.PushApplicationId)
+			if err := upsClient.DeleteApplication(instance.Status.PushApplicationId); err != nil {
+				return r.manageError(instance, err)
+			}
+		}
+		return reconcile.Result{}, util.RemoveFinalizer(r.client, reqLogger, instance)
+	}
+
+	if err := util.AddFinalizer(r.client, reqLogger, instance); err != nil {
+		return r.manageError(instance, err)
+	}
+
+	if !util.IsUnifiedPushServerReady(ups) {
+		reqLogger.Info("Requeuing, UnifiedPushServer not ready.", "UnifiedPushServer.Name", ups.Name)
+		return r.manageWaiting(instance, fmt.Sprintf("Waiting for UnifiedPushServer %s to be ready", ups.Name))
+	}
+
+	// PushApplications imported from UPS take over the existing app
+	if instance.Status.PushApplicationId == "" {
+		instance.Status.PushApplicationId = util.AdoptedID(instance)
+	}
+
+	//#region UPS application
+	var app *unifiedpush.PushApplication
+	if instance.Status.PushApplicationId != "" {
+		app, err = upsClient.GetApplication(instance.Status.PushApplicationId)
+		if err != nil {
+			return r.manageError(instance, err)
+		}
+	}
+
+	desiredApp := unifiedpush.PushApplication{
+		Name:        util.NameInUPS(instance, instance.Spec.Name),
+		Description: instance.Spec.Description,
+	}
+
+	if app == nil {
+		reqLogger.Info("Creating a new application in UPS")
+		app, err = upsClient.CreateApplication(desiredApp)
+		if err != nil {
+			return r.manageError(instance, err)
+		}
+
+		// Save the id straight away, so we don't create the app
+		// twice if something below fails
+		instance.Status.PushApplicationId = app.PushApplicationID
+		err = r.client.Status().Update(context.TODO(), instance)
+		if err != nil {
+			reqLogger.Error(err, "Failed to update PushApplication status", "PushApplicationId", app.PushApplicationID)
+			return r.manageError(instance, err)
+		}
+	} else if app.Name != desiredApp.Name || app.Description != desiredApp.Description {
+		reqLogger.Info("Application in UPS is different than the PushApplication spec. Going to update it now.", "PushApplicationId", app.PushApplicationID)
+		desiredApp.PushApplicationID = app.PushApplicationID
+		if err := upsClient.UpdateApplication(desiredApp); err != nil {
+			return r.manageError(instance, err)
+		}
+	}
+	//#endregion
+
+	//#region Secret
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: secretName(instance), Namespace: instance.Namespace}}
+	op, err := controllerutil.CreateOrUpdate(context.TODO(), r.client, secret, func(ignore runtime.Object) error {
+		reconcileSecret(secret, instance, app)
+		// Set PushApplication instance as the owner and controller
+		return controllerutil.SetControllerReference(instance, secret, r.scheme)
+	})
+	if err != nil {
+		return r.manageError(instance, err)
+	}
+	if op != controllerutil.OperationResultNone {
+		reqLogger.Info("Secret reconciled:", "Secret.Name", secret.Name, "Secret.Namespace", secret.Namespace, "Operation", op)
+	}
+	//#endregion
+
+	//#region Mobile services Secret
+	url, err := util.UnifiedPushServerPublicURL(r.client, ups)
+	if err != nil {
+		return r.manageError(instance, err)
+	}
+
+	config, err := pushConfig(r.client, instance)
+	if err != nil {
+		return r.manageError(instance, err)
+	}
+	services := newServices(instance, url, config)
+
+	mobileServicesSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: mobileServicesSecretName(instance), Namespace: instance.Namespace}}
+	op, err = controllerutil.CreateOrUpdate(context.TODO(), r.client, mobileServicesSecret, func(ignore runtime.Object) error {
+		if err := reconcileMobileServicesSecret(mobileServicesSecret, instance, services); err != nil {
+			return err
+		}
+		// Set PushApplication instance as the owner and controller
+		return controllerutil.SetControllerReference(instance, mobileServicesSecret, r.scheme)
+	})
+	if err != nil {
+		return r.manageError(instance, err)
+	}
+	if op != controllerutil.OperationResultNone {
+		reqLogger.Info("Secret reconciled:", "Secret.Name", mobileServicesSecret.Name, "Secret.Namespace", mobileServicesSecret.Namespace, "Operation", op)
+	}
+	//#endregion
+
+	instance.Status.SecretName = secret.Name
+	instance.Status.MobileServicesSecret = mobileServicesSecret.Name
+	return r.manageSuccess(instance)
+}
+
+func (r *ReconcilePushApplication) manageError(instance *pushv1alpha1.PushApplication, issue error) (reconcile.Result, error) {
+	r.recorder.Event(instance, "Warning", "ReconcileFailed", issue.Error())
+
+	instance.Status.Message = issue.Error()
+	instance.Status.Phase = pushv1alpha1.PhaseFailing
+
+	err := r.client.Status().Update(context.TODO(), instance)
+	if err != nil {
+		log.Error(err, "Unable to update status")
+	}
+
+	return reconcile.Result{
+		RequeueAfter: requeueErrorDelay,
+		Requeue:      true,
+	}, nil
+}
+
+func (r *ReconcilePushApplication) manageWaiting(instance *pushv1alpha1.PushApplication, message string) (reconcile.Result, error) {
+	instance.Status.Message = message
+	instance.Status.Phase = pushv1alpha1.PhaseInitializing
+
+	err := r.client.Status().Update(context.TODO(), instance)
+	if err != nil {
+		log.Error(err, "Unable to update status")
+	}
+
+	return reconcile.Result{RequeueAfter: requeueDelay}, nil
+}
+
+func (r *ReconcilePushApplication) manageSuccess(instance *pushv1alpha1.PushApplication) (reconcile.Result, error) {
+	instance.Status.Message = ""
+	instance.Status.Phase = pushv1alpha1.PhaseReconciling
+
+	err := r.client.Status().Update(context.TODO(), instance)
+	if err != nil {
+		log.Error(err, "Unable to update status")
+		return reconcile.Result{
+			RequeueAfter: requeueErrorDelay,
+			Requeue:      true,
+		}, nil
+	}
+
+	log.Info("Reconcile successful", "PushApplication.Namespace", instance.Namespace, "PushApplication.Name", instance.Name)
+	return reconcile.Result{}, nil
+}
+
+func secretName(cr *pushv1alpha1.PushApplication) string {
+	return fmt.Sprintf("%s-pushapplication", cr.Name)
+}
+
+func reconcileSecret(secret *corev1.Secret, cr *pushv1alpha1.PushApplication, app *unifiedpush.PushApplication) {
+	secret.ObjectMeta.Labels = map[string]string{
+		"app":             cr.Spec.UnifiedPushServer,
+		"pushapplication": cr.Name,
+	}
+	secret.Data = map[string][]byte{
+		"pushApplicationID": []byte(app.PushApplicationID),
+		"masterSecret":      []byte(app.MasterSecret),
+	}
+}
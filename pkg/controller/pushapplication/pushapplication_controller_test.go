@@ -0,0 +1,324 @@
+package pushapplication
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	pushv1alpha1 "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1"
+	"github.com/aerogear/unifiedpush-operator/pkg/controller/testutil"
+	"github.com/aerogear/unifiedpush-operator/pkg/unifiedpush"
+	"github.com/aerogear/unifiedpush-operator/pkg/unifiedpush/fake"
+
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+//buildReconcileWithFakeClientWithMocks return reconcile with fake client, schemes and mock objects
+func buildReconcileWithFakeClientWithMocks(objs []runtime.Object, server *fake.Server, t *testing.T) *ReconcilePushApplication {
+	// Add Openshift route scheme
+	if err := routev1.AddToScheme(scheme.Scheme); err != nil {
+		t.Fatalf("Unable to add route scheme: (%v)", err)
+	}
+
+	return &ReconcilePushApplication{
+		client:         testutil.NewFakeClient(t, objs...),
+		scheme:         scheme.Scheme,
+		recorder:       record.NewFakeRecorder(10),
+		unifiedpushURL: testutil.UnifiedPushURL(server),
+	}
+}
+
+func TestAdd_WithoutRoutes(t *testing.T) {
+	s := scheme.Scheme
+	if err := routev1.AddToScheme(s); err != nil {
+		t.Fatalf("Unable to add route scheme: (%v)", err)
+	}
+	if err := pushv1alpha1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatalf("Unable to add push scheme: (%v)", err)
+	}
+
+	// The route.openshift.io API isn't served, as on plain Kubernetes
+	mapper := meta.NewDefaultRESTMapper(nil)
+	for _, obj := range []runtime.Object{
+		&pushv1alpha1.PushApplication{},
+		&pushv1alpha1.AndroidVariant{},
+		&pushv1alpha1.IOSTokenVariant{},
+		&pushv1alpha1.WebPushVariant{},
+		&corev1.Secret{},
+		&extensionsv1beta1.Ingress{},
+	} {
+		gvks, _, err := s.ObjectKinds(obj)
+		if err != nil {
+			t.Fatalf("get kind: (%v)", err)
+		}
+		mapper.Add(gvks[0], meta.RESTScopeNamespace)
+	}
+
+	mgr, err := manager.New(&rest.Config{Host: "http://127.0.0.1:1"}, manager.Options{
+		Scheme:             s,
+		MapperProvider:     func(c *rest.Config) (meta.RESTMapper, error) { return mapper, nil },
+		MetricsBindAddress: "0",
+	})
+	if err != nil {
+		t.Fatalf("create manager: (%v)", err)
+	}
+
+	if err := add(mgr, &ReconcilePushApplication{}); err != nil {
+		t.Fatalf("expected the controller to be added without Routes, got (%v)", err)
+	}
+}
+
+func TestApplicationForVariant(t *testing.T) {
+	variant := &pushv1alpha1.AndroidVariant{
+		ObjectMeta: metav1.ObjectMeta{Name: "example-androidvariant", Namespace: "unifiedpush"},
+		Spec:       pushv1alpha1.AndroidVariantSpec{PushApplication: "example-pushapplication"},
+	}
+	requests := applicationForVariant(handler.MapObject{Meta: variant, Object: variant})
+	expected := []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "example-pushapplication", Namespace: "unifiedpush"}}}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Errorf("expected (%v), got (%v)", expected, requests)
+	}
+
+	variant.Spec.PushApplication = ""
+	if requests := applicationForVariant(handler.MapObject{Meta: variant, Object: variant}); len(requests) != 0 {
+		t.Errorf("expected no requests for a variant without a PushApplication, got (%v)", requests)
+	}
+}
+
+func TestReconcilePushApplication_Reconcile(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+
+	app := pushApplication()
+	r := buildReconcileWithFakeClientWithMocks([]runtime.Object{testutil.ReadyUnifiedPushServer(), app}, server, t)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: app.Name, Namespace: app.Namespace}}
+
+	// Create
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+
+	found := &pushv1alpha1.PushApplication{}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, found); err != nil {
+		t.Fatalf("get PushApplication: (%v)", err)
+	}
+	if found.Status.Phase != pushv1alpha1.PhaseReconciling {
+		t.Fatalf("expected phase %s, got %s: %s", pushv1alpha1.PhaseReconciling, found.Status.Phase, found.Status.Message)
+	}
+	if len(found.Finalizers) != 1 {
+		t.Errorf("expected a finalizer, got %v", found.Finalizers)
+	}
+
+	upsApp := server.Application(found.Status.PushApplicationId)
+	if upsApp == nil {
+		t.Fatalf("application %s was not created in UPS", found.Status.PushApplicationId)
+	}
+	if upsApp.Name != app.Name || upsApp.Description != app.Spec.Description {
+		t.Errorf("unexpected application in UPS: %+v", upsApp)
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: found.Status.SecretName, Namespace: app.Namespace}, secret); err != nil {
+		t.Fatalf("get Secret: (%v)", err)
+	}
+	if string(secret.Data["pushApplicationID"]) != upsApp.PushApplicationID || string(secret.Data["masterSecret"]) != upsApp.MasterSecret {
+		t.Errorf("unexpected Secret data: %v", secret.Data)
+	}
+
+	// Update
+	found.Spec.Description = "changed"
+	if err := r.client.Update(context.TODO(), found); err != nil {
+		t.Fatalf("update PushApplication: (%v)", err)
+	}
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	if d := server.Application(found.Status.PushApplicationId).Description; d != "changed" {
+		t.Errorf("expected description to be updated in UPS, got %q", d)
+	}
+
+	// Recreate if removed from UPS behind our back
+	if err := unifiedpush.NewClient(server.URL).DeleteApplication(found.Status.PushApplicationId); err != nil {
+		t.Fatalf("delete application: (%v)", err)
+	}
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, found); err != nil {
+		t.Fatalf("get PushApplication: (%v)", err)
+	}
+	if server.Application(found.Status.PushApplicationId) == nil {
+		t.Fatalf("application was not recreated in UPS")
+	}
+
+	if found.Status.MobileServicesSecret != mobileServicesSecretName(app) {
+		t.Errorf("expected Secret %s in status, got %s", mobileServicesSecretName(app), found.Status.MobileServicesSecret)
+	}
+
+	// Delete
+	now := metav1.Now()
+	found.DeletionTimestamp = &now
+	if err := r.client.Update(context.TODO(), found); err != nil {
+		t.Fatalf("update PushApplication: (%v)", err)
+	}
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	if apps := server.Applications(); len(apps) != 0 {
+		t.Errorf("expected application to be deleted from UPS, got %v", apps)
+	}
+	deleted := &pushv1alpha1.PushApplication{}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, deleted); err != nil {
+		t.Fatalf("get PushApplication: (%v)", err)
+	}
+	if len(deleted.Finalizers) != 0 {
+		t.Errorf("expected finalizer to be removed, got %v", deleted.Finalizers)
+	}
+}
+
+func TestReconcilePushApplication_WaitsForUnifiedPushServer(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+
+	ups := testutil.ReadyUnifiedPushServer()
+	ups.Status.Ready = nil
+	app := pushApplication()
+	r := buildReconcileWithFakeClientWithMocks([]runtime.Object{ups, app}, server, t)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: app.Name, Namespace: app.Namespace}}
+
+	res, err := r.Reconcile(req)
+	if err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	if res.RequeueAfter == 0 {
+		t.Error("expected reconcile to be requeued")
+	}
+	if apps := server.Applications(); len(apps) != 0 {
+		t.Errorf("expected no application to be created in UPS, got %v", apps)
+	}
+}
+
+func TestReconcilePushApplication_MobileServices(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+
+	app := pushApplication()
+	route := &routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "example-unifiedpushserver-unifiedpush-proxy",
+			Namespace: "unifiedpush",
+			Labels:    map[string]string{"app": "example-unifiedpushserver"},
+		},
+		Spec: routev1.RouteSpec{Host: "ups.example.com"},
+	}
+	r := buildReconcileWithFakeClientWithMocks([]runtime.Object{testutil.ReadyUnifiedPushServer(), app, route}, server, t)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: app.Name, Namespace: app.Namespace}}
+
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	found := &pushv1alpha1.PushApplication{}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, found); err != nil {
+		t.Fatalf("get PushApplication: (%v)", err)
+	}
+
+	// Add one variant of each type, as their controllers would
+	appID := found.Status.PushApplicationId
+	objs := []runtime.Object{
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "fcm", Namespace: app.Namespace},
+			Data:       map[string][]byte{"serverKey": []byte("server-key"), "senderId": []byte("sender-id")},
+		},
+	}
+	android := &pushv1alpha1.AndroidVariant{
+		ObjectMeta: metav1.ObjectMeta{Name: "android", Namespace: app.Namespace},
+		Spec:       pushv1alpha1.AndroidVariantSpec{PushApplication: app.Name, CredentialsSecret: "fcm"},
+		Status:     pushv1alpha1.AndroidVariantStatus{PushApplicationId: appID, VariantId: "android-id", Secret: "android-secret"},
+	}
+	ios := &pushv1alpha1.IOSTokenVariant{
+		ObjectMeta: metav1.ObjectMeta{Name: "ios", Namespace: app.Namespace},
+		Spec:       pushv1alpha1.IOSTokenVariantSpec{PushApplication: app.Name},
+		Status:     pushv1alpha1.IOSTokenVariantStatus{PushApplicationId: appID, VariantId: "ios-id", Secret: "ios-secret"},
+	}
+	webPush := &pushv1alpha1.WebPushVariant{
+		ObjectMeta: metav1.ObjectMeta{Name: "webpush", Namespace: app.Namespace},
+		Spec:       pushv1alpha1.WebPushVariantSpec{PushApplication: app.Name},
+		Status:     pushv1alpha1.WebPushVariantStatus{PushApplicationId: appID, VariantId: "webpush-id", Secret: "webpush-secret", PublicKey: "public-key"},
+	}
+	// Clients only read the first variant of each platform
+	secondAndroid := &pushv1alpha1.AndroidVariant{
+		ObjectMeta: metav1.ObjectMeta{Name: "second-android", Namespace: app.Namespace},
+		Spec:       pushv1alpha1.AndroidVariantSpec{PushApplication: app.Name, CredentialsSecret: "fcm"},
+		Status:     pushv1alpha1.AndroidVariantStatus{PushApplicationId: appID, VariantId: "second-android-id", Secret: "second-android-secret"},
+	}
+	// A variant whose credentials are gone is left out
+	noCredentials := &pushv1alpha1.AndroidVariant{
+		ObjectMeta: metav1.ObjectMeta{Name: "a-no-credentials", Namespace: app.Namespace},
+		Spec:       pushv1alpha1.AndroidVariantSpec{PushApplication: app.Name, CredentialsSecret: "missing"},
+		Status:     pushv1alpha1.AndroidVariantStatus{PushApplicationId: appID, VariantId: "no-credentials-id", Secret: "no-credentials-secret"},
+	}
+	other := &pushv1alpha1.AndroidVariant{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: app.Namespace},
+		Spec:       pushv1alpha1.AndroidVariantSpec{PushApplication: "other-app", CredentialsSecret: "fcm"},
+		Status:     pushv1alpha1.AndroidVariantStatus{PushApplicationId: "other-app-id", VariantId: "other-id"},
+	}
+	objs = append(objs, secondAndroid, android, noCredentials, ios, webPush, other)
+	for _, o := range objs {
+		if err := r.client.Create(context.TODO(), o); err != nil {
+			t.Fatalf("create %T: (%v)", o, err)
+		}
+	}
+
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+
+	mobileServicesSecret := &corev1.Secret{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: mobileServicesSecretName(app), Namespace: app.Namespace}, mobileServicesSecret); err != nil {
+		t.Fatalf("get Secret: (%v)", err)
+	}
+	config := mobileServices{}
+	if err := json.Unmarshal(mobileServicesSecret.Data[mobileServicesKey], &config); err != nil {
+		t.Fatalf("unmarshal %s: (%v)", mobileServicesKey, err)
+	}
+
+	expected := []mobileServicesConfig{
+		{
+			ID:   app.Name,
+			Name: "push",
+			Type: "push",
+			URL:  "https://ups.example.com",
+			Config: map[string]map[string]string{
+				"android":  {"variantId": "android-id", "variantSecret": "android-secret", "senderId": "sender-id"},
+				"ios":      {"variantId": "ios-id", "variantSecret": "ios-secret"},
+				"web_push": {"variantId": "webpush-id", "variantSecret": "webpush-secret", "appServerKey": "public-key"},
+			},
+		},
+	}
+	if config.ClientID != app.Name || !reflect.DeepEqual(config.Services, expected) {
+		t.Errorf("unexpected %s: %s", mobileServicesKey, mobileServicesSecret.Data[mobileServicesKey])
+	}
+
+	if requests := applicationsForServer(r.client, app.Namespace, route.Labels["app"]); len(requests) != 1 || requests[0] != req {
+		t.Errorf("expected Route to map to %v, got %v", req, requests)
+	}
+}
+
+func pushApplication() *pushv1alpha1.PushApplication {
+	app := testutil.PushApplication("")
+	app.Spec.Description = "An example app"
+	return app
+}
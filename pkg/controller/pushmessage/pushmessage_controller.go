@@ -0,0 +1,229 @@
+package pushmessage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	pushv1alpha1 "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1"
+	"github.com/aerogear/unifiedpush-operator/pkg/controller/util"
+	"github.com/aerogear/unifiedpush-operator/pkg/unifiedpush"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	controllerName    = "pushmessage-controller"
+	requeueDelay      = 30 * time.Second
+	requeueErrorDelay = 5 * time.Second
+)
+
+var log = logf.Log.WithName(controllerName)
+
+// Add creates a new PushMessage Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcilePushMessage{
+		client:         mgr.GetClient(),
+		scheme:         mgr.GetScheme(),
+		recorder:       mgr.GetRecorder(controllerName),
+		unifiedpushURL: util.UnifiedPushServerURL,
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource PushMessage
+	err = c.Watch(&source.Kind{Type: &pushv1alpha1.PushMessage{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var _ reconcile.Reconciler = &ReconcilePushMessage{}
+
+// ReconcilePushMessage reconciles a PushMessage object
+type ReconcilePushMessage struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	client   client.Client
+	scheme   *runtime.Scheme
+	recorder record.EventRecorder
+
+	unifiedpushURL util.UnifiedPushURLFunc
+}
+
+// Reconcile sends each PushMessage to the devices of the referenced
+// PushApplication, once. After UPS has accepted the message it is
+// marked as delivered and left alone, even if its spec changes.
+func (r *ReconcilePushMessage) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling PushMessage")
+
+	// Fetch the PushMessage instance
+	instance := &pushv1alpha1.PushMessage{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Return and don't requeue
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return r.manageError(instance, err)
+	}
+
+	if instance.Status.Delivered {
+		reqLogger.Info("PushMessage already delivered, not sending it again", "SentAt", instance.Status.SentAt)
+		return reconcile.Result{}, nil
+	}
+	if instance.Status.Phase == pushv1alpha1.PhaseSending {
+		reqLogger.Info("PushMessage may already have been sent, not sending it again")
+		return reconcile.Result{}, nil
+	}
+
+	app, ups, err := util.GetPushApplication(r.client, instance.Namespace, instance.Spec.PushApplication)
+	if err != nil {
+		return r.manageError(instance, err)
+	}
+
+	if app.Status.PushApplicationId == "" || app.Status.SecretName == "" || !util.IsUnifiedPushServerReady(ups) {
+		reqLogger.Info("Requeuing, PushApplication not ready.", "PushApplication.Name", app.Name)
+		return r.manageWaiting(instance, fmt.Sprintf("Waiting for PushApplication %s to be ready", app.Name))
+	}
+
+	secret := &corev1.Secret{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: app.Status.SecretName, Namespace: app.Namespace}, secret)
+	if err != nil {
+		return r.manageError(instance, err)
+	}
+
+	// Claim the message before sending it. The update is checked
+	// against the resourceVersion, so it fails instead of sending the
+	// message twice when the cached copy is out of date.
+	instance.Status.Message = ""
+	instance.Status.Phase = pushv1alpha1.PhaseSending
+	err = r.client.Status().Update(context.TODO(), instance)
+	if err != nil {
+		reqLogger.Info("Requeuing, unable to claim PushMessage", "Error", err.Error())
+		return reconcile.Result{Requeue: true}, nil
+	}
+
+	upsClient := unifiedpush.NewClient(r.unifiedpushURL(ups))
+	reqLogger.Info("Sending push message", "PushApplicationId", app.Status.PushApplicationId)
+	response, err := upsClient.SendMessage(app.Status.PushApplicationId, string(secret.Data["masterSecret"]), newPushMessage(instance))
+	if err != nil {
+		return r.manageError(instance, err)
+	}
+
+	now := metav1.Now()
+	instance.Status.Delivered = true
+	instance.Status.SentAt = &now
+	instance.Status.Response = response
+	return r.manageSuccess(instance)
+}
+
+func (r *ReconcilePushMessage) manageError(instance *pushv1alpha1.PushMessage, issue error) (reconcile.Result, error) {
+	r.recorder.Event(instance, "Warning", "ReconcileFailed", issue.Error())
+
+	instance.Status.Message = issue.Error()
+	instance.Status.Phase = pushv1alpha1.PhaseFailing
+
+	err := r.client.Status().Update(context.TODO(), instance)
+	if err != nil {
+		log.Error(err, "Unable to update status")
+	}
+
+	return reconcile.Result{
+		RequeueAfter: requeueErrorDelay,
+		Requeue:      true,
+	}, nil
+}
+
+func (r *ReconcilePushMessage) manageWaiting(instance *pushv1alpha1.PushMessage, message string) (reconcile.Result, error) {
+	instance.Status.Message = message
+	instance.Status.Phase = pushv1alpha1.PhaseInitializing
+
+	err := r.client.Status().Update(context.TODO(), instance)
+	if err != nil {
+		log.Error(err, "Unable to update status")
+	}
+
+	return reconcile.Result{RequeueAfter: requeueDelay}, nil
+}
+
+func (r *ReconcilePushMessage) manageSuccess(instance *pushv1alpha1.PushMessage) (reconcile.Result, error) {
+	r.recorder.Event(instance, "Normal", "Delivered", "Push message accepted by UnifiedPush Server")
+
+	instance.Status.Message = ""
+	instance.Status.Phase = pushv1alpha1.PhaseComplete
+
+	// The message has been sent, and it would be sent again on the
+	// next reconcile if the status isn't stored, so keep trying for a
+	// while, on top of the latest version of the CR
+	status := instance.Status
+	err := wait.ExponentialBackoff(retry.DefaultBackoff, func() (bool, error) {
+		err := r.client.Status().Update(context.TODO(), instance)
+		if err == nil {
+			return true, nil
+		}
+		log.Error(err, "Unable to update status of delivered PushMessage")
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace}, instance); err != nil {
+			return false, nil
+		}
+		instance.Status = status
+		return false, nil
+	})
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	log.Info("Reconcile successful", "PushMessage.Namespace", instance.Namespace, "PushMessage.Name", instance.Name)
+	return reconcile.Result{}, nil
+}
+
+// newPushMessage builds the sender request body from the CR
+func newPushMessage(cr *pushv1alpha1.PushMessage) unifiedpush.PushMessage {
+	return unifiedpush.PushMessage{
+		Message: unifiedpush.Message{
+			Alert:    cr.Spec.Alert,
+			Sound:    cr.Spec.Sound,
+			Badge:    cr.Spec.Badge,
+			UserData: cr.Spec.Data,
+		},
+		Criteria: unifiedpush.Criteria{
+			Aliases:    cr.Spec.Aliases,
+			Categories: cr.Spec.Categories,
+			Variants:   cr.Spec.Variants,
+		},
+		TTL: cr.Spec.TTL,
+	}
+}
@@ -0,0 +1,292 @@
+package pushmessage
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	pushv1alpha1 "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1"
+	"github.com/aerogear/unifiedpush-operator/pkg/controller/testutil"
+	"github.com/aerogear/unifiedpush-operator/pkg/unifiedpush"
+	"github.com/aerogear/unifiedpush-operator/pkg/unifiedpush/fake"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+//buildReconcileWithFakeClientWithMocks return reconcile with fake client, schemes and mock objects
+func buildReconcileWithFakeClientWithMocks(objs []runtime.Object, server *fake.Server, t *testing.T) *ReconcilePushMessage {
+	return &ReconcilePushMessage{
+		client:         testutil.NewFakeClient(t, objs...),
+		scheme:         scheme.Scheme,
+		recorder:       record.NewFakeRecorder(10),
+		unifiedpushURL: testutil.UnifiedPushURL(server),
+	}
+}
+
+func TestReconcilePushMessage_Reconcile(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+
+	upsApp, err := unifiedpush.NewClient(server.URL).CreateApplication(unifiedpush.PushApplication{Name: "example-pushapplication"})
+	if err != nil {
+		t.Fatalf("create application: (%v)", err)
+	}
+
+	msg := pushMessage()
+	r := buildReconcileWithFakeClientWithMocks([]runtime.Object{
+		testutil.ReadyUnifiedPushServer(),
+		pushApplication(upsApp),
+		pushApplicationSecret(upsApp),
+		msg,
+	}, server, t)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: msg.Name, Namespace: msg.Namespace}}
+
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+
+	found := &pushv1alpha1.PushMessage{}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, found); err != nil {
+		t.Fatalf("get PushMessage: (%v)", err)
+	}
+	if found.Status.Phase != pushv1alpha1.PhaseComplete || !found.Status.Delivered {
+		t.Fatalf("expected message to be delivered, got %+v", found.Status)
+	}
+	if found.Status.SentAt == nil || found.Status.Response == "" {
+		t.Errorf("expected send time and response in status, got %+v", found.Status)
+	}
+
+	sent := server.Messages()
+	if len(sent) != 1 {
+		t.Fatalf("expected 1 message to be sent, got %d", len(sent))
+	}
+	expected := unifiedpush.PushMessage{
+		Message: unifiedpush.Message{
+			Alert:    "Version 2.0 is out!",
+			Badge:    1,
+			UserData: map[string]string{"version": "2.0"},
+		},
+		Criteria: unifiedpush.Criteria{
+			Aliases:    []string{"alice"},
+			Categories: []string{"releases"},
+		},
+		TTL: 3600,
+	}
+	if sent[0].PushApplicationID != upsApp.PushApplicationID || !reflect.DeepEqual(sent[0].Message, expected) {
+		t.Errorf("unexpected message sent: %+v", sent[0])
+	}
+
+	// Never re-send a delivered message
+	found.Spec.Alert = "changed"
+	if err := r.client.Update(context.TODO(), found); err != nil {
+		t.Fatalf("update PushMessage: (%v)", err)
+	}
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	if sent := server.Messages(); len(sent) != 1 {
+		t.Errorf("expected message not to be sent again, got %d messages", len(sent))
+	}
+}
+
+func TestReconcilePushMessage_NonJSONResponse(t *testing.T) {
+	for _, body := range []string{"", "Job submitted", `{"pushMessageId":`} {
+		t.Run(fmt.Sprintf("%q", body), func(t *testing.T) {
+			server := fake.NewServer()
+			defer server.Close()
+			server.SetSendResponse(body)
+
+			upsApp, err := unifiedpush.NewClient(server.URL).CreateApplication(unifiedpush.PushApplication{Name: "example-pushapplication"})
+			if err != nil {
+				t.Fatalf("create application: (%v)", err)
+			}
+
+			msg := pushMessage()
+			r := buildReconcileWithFakeClientWithMocks([]runtime.Object{
+				testutil.ReadyUnifiedPushServer(),
+				pushApplication(upsApp),
+				pushApplicationSecret(upsApp),
+				msg,
+			}, server, t)
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Name: msg.Name, Namespace: msg.Namespace}}
+
+			for i := 0; i < 2; i++ {
+				if _, err := r.Reconcile(req); err != nil {
+					t.Fatalf("reconcile: (%v)", err)
+				}
+			}
+
+			found := &pushv1alpha1.PushMessage{}
+			if err := r.client.Get(context.TODO(), req.NamespacedName, found); err != nil {
+				t.Fatalf("get PushMessage: (%v)", err)
+			}
+			if found.Status.Phase != pushv1alpha1.PhaseComplete || !found.Status.Delivered {
+				t.Fatalf("expected message to be delivered, got %+v", found.Status)
+			}
+			if found.Status.Response != body {
+				t.Errorf("expected response %q in status, got %q", body, found.Status.Response)
+			}
+			if sent := server.Messages(); len(sent) != 1 {
+				t.Errorf("expected message to be sent once, got %d messages", len(sent))
+			}
+		})
+	}
+}
+
+func TestReconcilePushMessage_WrongMasterSecret(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+
+	upsApp, err := unifiedpush.NewClient(server.URL).CreateApplication(unifiedpush.PushApplication{Name: "example-pushapplication"})
+	if err != nil {
+		t.Fatalf("create application: (%v)", err)
+	}
+
+	secret := pushApplicationSecret(upsApp)
+	secret.Data["masterSecret"] = []byte("wrong")
+	msg := pushMessage()
+	r := buildReconcileWithFakeClientWithMocks([]runtime.Object{
+		testutil.ReadyUnifiedPushServer(),
+		pushApplication(upsApp),
+		secret,
+		msg,
+	}, server, t)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: msg.Name, Namespace: msg.Namespace}}
+
+	res, err := r.Reconcile(req)
+	if err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	if !res.Requeue {
+		t.Error("expected reconcile to be requeued")
+	}
+
+	found := &pushv1alpha1.PushMessage{}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, found); err != nil {
+		t.Fatalf("get PushMessage: (%v)", err)
+	}
+	if found.Status.Phase != pushv1alpha1.PhaseFailing || found.Status.Delivered {
+		t.Errorf("expected message not to be delivered, got %+v", found.Status)
+	}
+}
+
+func TestReconcilePushMessage_Sending(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+
+	upsApp, err := unifiedpush.NewClient(server.URL).CreateApplication(unifiedpush.PushApplication{Name: "example-pushapplication"})
+	if err != nil {
+		t.Fatalf("create application: (%v)", err)
+	}
+
+	msg := pushMessage()
+	msg.Status.Phase = pushv1alpha1.PhaseSending
+	r := buildReconcileWithFakeClientWithMocks([]runtime.Object{
+		testutil.ReadyUnifiedPushServer(),
+		pushApplication(upsApp),
+		pushApplicationSecret(upsApp),
+		msg,
+	}, server, t)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: msg.Name, Namespace: msg.Namespace}}
+
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	if sent := server.Messages(); len(sent) != 0 {
+		t.Errorf("expected a message that may have been sent not to be sent again, got %d messages", len(sent))
+	}
+}
+
+func TestReconcilePushMessage_StaleRead(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+
+	upsApp, err := unifiedpush.NewClient(server.URL).CreateApplication(unifiedpush.PushApplication{Name: "example-pushapplication"})
+	if err != nil {
+		t.Fatalf("create application: (%v)", err)
+	}
+
+	msg := pushMessage()
+	r := buildReconcileWithFakeClientWithMocks([]runtime.Object{
+		testutil.ReadyUnifiedPushServer(),
+		pushApplication(upsApp),
+		pushApplicationSecret(upsApp),
+		msg,
+	}, server, t)
+	// The API server rejects status updates made on top of an out of
+	// date copy of the CR
+	r.client = conflictingStatusClient{r.client}
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: msg.Name, Namespace: msg.Namespace}}
+
+	res, err := r.Reconcile(req)
+	if err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	if !res.Requeue {
+		t.Error("expected reconcile to be requeued")
+	}
+	if sent := server.Messages(); len(sent) != 0 {
+		t.Errorf("expected the message not to be sent without claiming it first, got %d messages", len(sent))
+	}
+}
+
+type conflictingStatusClient struct {
+	client.Client
+}
+
+func (c conflictingStatusClient) Status() client.StatusWriter {
+	return conflictingStatusWriter{}
+}
+
+type conflictingStatusWriter struct{}
+
+func (conflictingStatusWriter) Update(ctx context.Context, obj runtime.Object) error {
+	return errors.NewConflict(schema.GroupResource{Group: "push.aerogear.org", Resource: "pushmessages"}, "example-pushmessage", fmt.Errorf("the object has been modified"))
+}
+
+func pushApplication(upsApp *unifiedpush.PushApplication) *pushv1alpha1.PushApplication {
+	app := testutil.PushApplication(upsApp.PushApplicationID)
+	app.Status.SecretName = "example-pushapplication-pushapplication"
+	return app
+}
+
+func pushApplicationSecret(upsApp *unifiedpush.PushApplication) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "example-pushapplication-pushapplication",
+			Namespace: "unifiedpush",
+		},
+		Data: map[string][]byte{
+			"pushApplicationID": []byte(upsApp.PushApplicationID),
+			"masterSecret":      []byte(upsApp.MasterSecret),
+		},
+	}
+}
+
+func pushMessage() *pushv1alpha1.PushMessage {
+	return &pushv1alpha1.PushMessage{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "example-pushmessage",
+			Namespace: "unifiedpush",
+		},
+		Spec: pushv1alpha1.PushMessageSpec{
+			PushApplication: "example-pushapplication",
+			Alert:           "Version 2.0 is out!",
+			Badge:           1,
+			Data:            map[string]string{"version": "2.0"},
+			Aliases:         []string{"alice"},
+			Categories:      []string{"releases"},
+			TTL:             3600,
+		},
+	}
+}
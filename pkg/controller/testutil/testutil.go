@@ -0,0 +1,167 @@
+// Package testutil holds the fixtures that the tests of the controllers
+// which call the UnifiedPush Server REST API have in common.
+package testutil
+
+import (
+	"context"
+	"testing"
+
+	pushv1alpha1 "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1"
+	"github.com/aerogear/unifiedpush-operator/pkg/controller/util"
+	"github.com/aerogear/unifiedpush-operator/pkg/unifiedpush"
+	"github.com/aerogear/unifiedpush-operator/pkg/unifiedpush/fake"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	Namespace             = "unifiedpush"
+	UnifiedPushServerName = "example-unifiedpushserver"
+	PushApplicationName   = "example-pushapplication"
+)
+
+// NewFakeClient returns a fake client holding objs, after registering
+// the push.aerogear.org types with the client-go scheme
+func NewFakeClient(t *testing.T, objs ...runtime.Object) client.Client {
+	if err := pushv1alpha1.SchemeBuilder.AddToScheme(scheme.Scheme); err != nil {
+		t.Fatalf("Unable to add push scheme: (%v)", err)
+	}
+	return fakeclient.NewFakeClient(objs...)
+}
+
+// UnifiedPushURL points the controllers at a fake server, whatever the
+// UnifiedPushServer
+func UnifiedPushURL(server *fake.Server) util.UnifiedPushURLFunc {
+	return func(ups *pushv1alpha1.UnifiedPushServer) string { return server.URL }
+}
+
+// ReadyUnifiedPushServer returns a UnifiedPushServer whose components
+// are all ready
+func ReadyUnifiedPushServer() *pushv1alpha1.UnifiedPushServer {
+	ready := true
+	return &pushv1alpha1.UnifiedPushServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      UnifiedPushServerName,
+			Namespace: Namespace,
+		},
+		Status: pushv1alpha1.UnifiedPushServerStatus{
+			Phase: pushv1alpha1.PhaseReconciling,
+			Ready: &ready,
+		},
+	}
+}
+
+// PushApplication returns a PushApplication registered with
+// ReadyUnifiedPushServer, which has been created in UPS with the given
+// id, unless it's empty
+func PushApplication(pushApplicationID string) *pushv1alpha1.PushApplication {
+	return &pushv1alpha1.PushApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      PushApplicationName,
+			Namespace: Namespace,
+		},
+		Spec: pushv1alpha1.PushApplicationSpec{
+			UnifiedPushServer: UnifiedPushServerName,
+		},
+		Status: pushv1alpha1.PushApplicationStatus{
+			Phase:             pushv1alpha1.PhaseReconciling,
+			PushApplicationId: pushApplicationID,
+		},
+	}
+}
+
+// VariantController describes the controller of one kind of variant to
+// TestPushApplicationChanged
+type VariantController struct {
+	// VariantType is the type of the variant in the REST API
+	VariantType string
+
+	// Variant is the variant CR, belonging to PushApplicationName
+	Variant runtime.Object
+
+	// Objects are created along with the variant, e.g. its credentials
+	Objects []runtime.Object
+
+	// NewReconciler returns the reconciler of the controller, using c
+	// and server
+	NewReconciler func(c client.Client, server *fake.Server) reconcile.Reconciler
+
+	// Status returns the application and variant ids in the status of
+	// a variant CR
+	Status func(variant runtime.Object) (pushApplicationID string, variantID string)
+}
+
+// TestPushApplicationChanged checks that the variant of the previous
+// application is deleted from UPS, and a new one is created, when the
+// PushApplication of a variant is recreated in UPS
+func TestPushApplicationChanged(t *testing.T, vc VariantController) {
+	server := fake.NewServer()
+	defer server.Close()
+
+	upsClient := unifiedpush.NewClient(server.URL)
+	oldApp, err := upsClient.CreateApplication(unifiedpush.PushApplication{Name: PushApplicationName})
+	if err != nil {
+		t.Fatalf("create application: (%v)", err)
+	}
+	newApp, err := upsClient.CreateApplication(unifiedpush.PushApplication{Name: PushApplicationName})
+	if err != nil {
+		t.Fatalf("create application: (%v)", err)
+	}
+
+	app := PushApplication(oldApp.PushApplicationID)
+	objs := append([]runtime.Object{ReadyUnifiedPushServer(), app, vc.Variant}, vc.Objects...)
+	c := NewFakeClient(t, objs...)
+	r := vc.NewReconciler(c, server)
+	variantMeta, err := meta.Accessor(vc.Variant)
+	if err != nil {
+		t.Fatalf("get variant metadata: (%v)", err)
+	}
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: variantMeta.GetName(), Namespace: variantMeta.GetNamespace()}}
+
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	oldIDs := server.VariantIDs(oldApp.PushApplicationID, vc.VariantType)
+	if len(oldIDs) != 1 {
+		t.Fatalf("expected a variant to be created in UPS, got %v", oldIDs)
+	}
+
+	// The PushApplication is recreated in UPS
+	app.Status.PushApplicationId = newApp.PushApplicationID
+	if err := c.Update(context.TODO(), app); err != nil {
+		t.Fatalf("update PushApplication: (%v)", err)
+	}
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+
+	if ids := server.VariantIDs(oldApp.PushApplicationID, vc.VariantType); len(ids) != 0 {
+		t.Errorf("expected the variant of the previous application to be deleted from UPS, got %v", ids)
+	}
+	newIDs := server.VariantIDs(newApp.PushApplicationID, vc.VariantType)
+	if len(newIDs) != 1 {
+		t.Fatalf("expected a variant to be created in the new application, got %v", newIDs)
+	}
+	found := vc.Variant.DeepCopyObject()
+	if err := c.Get(context.TODO(), req.NamespacedName, found); err != nil {
+		t.Fatalf("get variant: (%v)", err)
+	}
+	if pushApplicationID, variantID := vc.Status(found); pushApplicationID != newApp.PushApplicationID || variantID != newIDs[0] {
+		t.Errorf("expected status to point to variant %s of application %s, got %s of %s", newIDs[0], newApp.PushApplicationID, variantID, pushApplicationID)
+	}
+
+	// Reconciling again doesn't create another variant
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	if ids := server.VariantIDs(newApp.PushApplicationID, vc.VariantType); len(ids) != 1 {
+		t.Errorf("expected no new variant to be created in UPS, got %v", ids)
+	}
+}
@@ -1,7 +1,7 @@
 package unifiedpushserver
 
 import (
-	"github.com/aerogear/unifiedpush-operator/pkg/constants"
+	"fmt"
 
 	pushv1alpha1 "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1"
 	batchv1 "k8s.io/api/batch/v1"
@@ -10,7 +10,14 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-func backups(ups *pushv1alpha1.UnifiedPushServer) ([]batchv1beta1.CronJob, error) {
+// backupServiceAccountName is the name of the ServiceAccount that the
+// backup Jobs run with. It needs to be created beforehand, see
+// https://github.com/integr8ly/backup-container-image/tree/master/templates/openshift/rbac
+func backupServiceAccountName(cr *pushv1alpha1.UnifiedPushServer) string {
+	return fmt.Sprintf("%s-backupjob", cr.Name)
+}
+
+func backups(ups *pushv1alpha1.UnifiedPushServer, serviceAccountName string) ([]batchv1beta1.CronJob, error) {
 	cronjobs := []batchv1beta1.CronJob{}
 	for _, upsBackup := range ups.Spec.Backups {
 		cronJobLabels := labels(ups, "backup")
@@ -32,21 +39,20 @@ func backups(ups *pushv1alpha1.UnifiedPushServer) ([]batchv1beta1.CronJob, error
 								Labels: jobLabels,
 							},
 							Spec: corev1.PodSpec{
-								// This SA needs to be created beforehand
-								// https://github.com/integr8ly/backup-container-image/tree/master/templates/openshift/rbac
-								ServiceAccountName: "backupjob",
+								ServiceAccountName: serviceAccountName,
 								Containers: []corev1.Container{
 									{
 										Name:            upsBackup.Name + "-ups-backup",
-										Image:           constants.BackupImage,
-										ImagePullPolicy: "Always",
+										Image:           backupImage(ups),
+										ImagePullPolicy: imagePullPolicy(ups),
 										Command:         buildBackupContainerCommand(upsBackup, ups.Namespace),
 										Env:             buildBackupCronJobEnvVars(upsBackup, ups.Name, ups.Namespace, postgresqlSecretName(ups)),
 									},
 								},
-								RestartPolicy: corev1.RestartPolicyOnFailure,
-								Affinity:      ups.Spec.Affinity,
-								Tolerations:   ups.Spec.Tolerations,
+								RestartPolicy:    corev1.RestartPolicyOnFailure,
+								Affinity:         ups.Spec.Affinity,
+								Tolerations:      ups.Spec.Tolerations,
+								ImagePullSecrets: ups.Spec.ImagePullSecrets,
 							},
 						},
 					},
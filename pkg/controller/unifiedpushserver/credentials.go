@@ -0,0 +1,199 @@
+package unifiedpushserver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	pushv1alpha1 "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1"
+
+	messaginguserv1beta "github.com/enmasseproject/enmasse/pkg/apis/user/v1beta1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// RotateCredentialsAnnotation can be set on a UnifiedPushServer to
+	// rotate the passwords of the PostgreSQL and AMQ users that are
+	// managed by the operator. They're rotated whenever the value
+	// changes, e.g. to the current time.
+	RotateCredentialsAnnotation = "push.aerogear.org/rotate-credentials"
+
+	// credentialRotationAnnotation records on a Secret the value of
+	// RotateCredentialsAnnotation that its password was last rotated for
+	credentialRotationAnnotation = "push.aerogear.org/credential-rotation"
+
+	// credentialsRotatedAtAnnotation records on a Secret when its
+	// password was last rotated
+	credentialsRotatedAtAnnotation = "push.aerogear.org/credentials-rotated-at"
+
+	// newPostgresPasswordKey holds the new password in the PostgreSQL
+	// Secret while the rotation Job is changing it in the database
+	newPostgresPasswordKey = "POSTGRES_NEW_PASSWORD"
+)
+
+// initCredentialRotation starts the rotation schedule of a Secret that
+// hasn't been rotated before, so that neither new Secrets nor the ones
+// created by older versions of the operator are rotated right away
+func initCredentialRotation(secret *corev1.Secret, cr *pushv1alpha1.UnifiedPushServer) {
+	if _, ok := secret.Annotations[credentialRotationAnnotation]; ok {
+		return
+	}
+	secret.Annotations = mergeStringMaps(secret.Annotations, map[string]string{
+		credentialRotationAnnotation:   cr.Annotations[RotateCredentialsAnnotation],
+		credentialsRotatedAtAnnotation: time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// credentialRotationDue returns true if the password in the Secret
+// should be rotated, either because it has been requested with
+// RotateCredentialsAnnotation or because it's older than
+// spec.credentialRotationDays
+func credentialRotationDue(cr *pushv1alpha1.UnifiedPushServer, secret *corev1.Secret, now time.Time) bool {
+	if cr.Annotations[RotateCredentialsAnnotation] != secret.Annotations[credentialRotationAnnotation] {
+		return true
+	}
+	if cr.Spec.CredentialRotationDays <= 0 {
+		return false
+	}
+	rotatedAt, err := time.Parse(time.RFC3339, secret.Annotations[credentialsRotatedAtAnnotation])
+	if err != nil {
+		return false
+	}
+	return now.Sub(rotatedAt) >= time.Duration(cr.Spec.CredentialRotationDays)*24*time.Hour
+}
+
+// markCredentialsRotated records the rotation on the Secret and in the
+// status of the UnifiedPushServer
+func markCredentialsRotated(cr *pushv1alpha1.UnifiedPushServer, secret *corev1.Secret, now time.Time) {
+	secret.Annotations = mergeStringMaps(secret.Annotations, map[string]string{
+		credentialRotationAnnotation:   cr.Annotations[RotateCredentialsAnnotation],
+		credentialsRotatedAtAnnotation: now.UTC().Format(time.RFC3339),
+	})
+	cr.Status.LastCredentialRotation = &metav1.Time{Time: now}
+}
+
+// rotatePostgresqlPassword changes the password of the database user
+// when a rotation is due. The new password is kept in the Secret while
+// a Job runs ALTER ROLE with the old one, and only replaces it once the
+// Job has succeeded. It returns true while the rotation is in progress.
+func (r *ReconcileUnifiedPushServer) rotatePostgresqlPassword(instance *pushv1alpha1.UnifiedPushServer, secret *corev1.Secret) (bool, error) {
+	reqLogger := log.WithValues("Request.Namespace", instance.Namespace, "Request.Name", instance.Name)
+
+	job := &batchv1.Job{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: postgresqlRotationJobName(instance), Namespace: instance.Namespace}, job)
+	if err != nil && !errors.IsNotFound(err) {
+		return false, err
+	}
+	jobFound := err == nil
+
+	if len(secret.Data[newPostgresPasswordKey]) == 0 {
+		if jobFound {
+			// Left over from a rotation that has been completed
+			return false, r.deleteJob(job)
+		}
+
+		databaseReady := instance.Status.GetCondition(pushv1alpha1.ConditionDatabaseReady)
+		if databaseReady == nil || databaseReady.Status != corev1.ConditionTrue || !credentialRotationDue(instance, secret, time.Now()) {
+			return false, nil
+		}
+
+		reqLogger.Info("Rotating the password of the database user", "Secret.Namespace", secret.Namespace, "Secret.Name", secret.Name)
+		newPassword, err := generatePassword()
+		if err != nil {
+			return false, err
+		}
+		reconcileSecretData(secret, map[string]string{
+			newPostgresPasswordKey: newPassword,
+		})
+		if err := r.client.Update(context.TODO(), secret); err != nil {
+			return false, err
+		}
+	}
+
+	if !jobFound {
+		job = newPostgresqlRotationJob(instance)
+		if err := controllerutil.SetControllerReference(instance, job, r.scheme); err != nil {
+			return false, err
+		}
+		reqLogger.Info("Creating a new Job", "Job.Namespace", job.Namespace, "Job.Name", job.Name)
+		return true, r.client.Create(context.TODO(), job)
+	}
+
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			// Start over with a new Job on the next reconcile
+			if err := r.deleteJob(job); err != nil {
+				return false, err
+			}
+			return false, fmt.Errorf("failed to change the password of the database user: %s", condition.Message)
+		}
+	}
+
+	jobReady, err := isJobReady(job)
+	if err != nil || !jobReady {
+		return true, err
+	}
+
+	reconcileSecretData(secret, map[string]string{
+		"POSTGRES_PASSWORD": string(secret.Data[newPostgresPasswordKey]),
+	})
+	delete(secret.Data, newPostgresPasswordKey)
+	markCredentialsRotated(instance, secret, time.Now())
+	if err := r.client.Update(context.TODO(), secret); err != nil {
+		return true, err
+	}
+	reqLogger.Info("Rotated the password of the database user", "Secret.Namespace", secret.Namespace, "Secret.Name", secret.Name)
+
+	return false, r.deleteJob(job)
+}
+
+// rotateMessagingUserPassword changes the password of the AMQ user when
+// a rotation is due, along with the password in the AMQ Secret. It's
+// applied by AMQ Online as soon as the MessagingUser is updated.
+func (r *ReconcileUnifiedPushServer) rotateMessagingUserPassword(instance *pushv1alpha1.UnifiedPushServer, user *messaginguserv1beta.MessagingUser) error {
+	secret := &corev1.Secret{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: fmt.Sprintf("%s-amq", instance.Name), Namespace: instance.Namespace}, secret)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// The Secret will be created with the current password
+			return nil
+		}
+		return err
+	}
+
+	now := time.Now()
+	if !credentialRotationDue(instance, secret, now) {
+		return nil
+	}
+
+	log.Info("Rotating the password of the AMQ user", "MessagingUser.Namespace", user.Namespace, "MessagingUser.Name", user.Name)
+	newPassword, err := generatePassword()
+	if err != nil {
+		return err
+	}
+	user.Spec.Authentication.Password = []byte(newPassword)
+	if err := r.client.Update(context.TODO(), user); err != nil {
+		return err
+	}
+
+	reconcileSecretData(secret, map[string]string{
+		"artemis-password": newPassword,
+	})
+	markCredentialsRotated(instance, secret, now)
+	return r.client.Update(context.TODO(), secret)
+}
+
+func (r *ReconcileUnifiedPushServer) deleteJob(job *batchv1.Job) error {
+	// Delete the pods of the Job along with it
+	err := r.client.Delete(context.TODO(), job, client.PropagationPolicy(metav1.DeletePropagationBackground))
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
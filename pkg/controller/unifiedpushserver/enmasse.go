@@ -11,19 +11,39 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-func newAMQSecret(cr *pushv1alpha1.UnifiedPushServer, artemisPassword string, addressURL string) *corev1.Secret {
+func reconcileAMQSecret(secret *corev1.Secret, cr *pushv1alpha1.UnifiedPushServer, artemisPassword string, addressURL string) {
+	secret.Labels = mergeStringMaps(secret.Labels, labels(cr, "amq"))
+	reconcileSecretData(secret, map[string]string{
+		"artemis-password": artemisPassword,
+		"artemis-url":      addressURL,
+	})
+	initCredentialRotation(secret, cr)
+}
 
-	return &corev1.Secret{
-		ObjectMeta: objectMeta(cr, "amq"),
-		StringData: map[string]string{
-			"artemis-password": artemisPassword,
-			"artemis-url":      addressURL,
-		},
-	}
+// amqQueues and amqTopics are the addresses that UPS sends to and
+// receives from
+var (
+	amqQueues = []string{"APNsPushMessageQueue", "APNsTokenBatchQueue", "GCMPushMessageQueue", "GCMTokenBatchQueue", "WNSPushMessageQueue", "WNSTokenBatchQueue", "WebPushMessageQueue", "WebTokenBatchQueue", "MetricsQueue", "TriggerMetricCollectionQueue", "TriggerVariantMetricCollectionQueue", "BatchLoadedQueue", "AllBatchesLoadedQueue", "FreeServiceSlotQueue"}
+	amqTopics = []string{"MetricsProcessingStartedTopic", "topic/APNSClient"}
+)
+
+// addressSpaceName is the name of the AddressSpace of the
+// UnifiedPushServer. The MessagingUser and Address names need to be
+// prefixed with it.
+func addressSpaceName(cr *pushv1alpha1.UnifiedPushServer) string {
+	return cr.Name
+}
+
+func messagingUserName(addressSpace string) string {
+	return fmt.Sprintf("%s.upsuser", addressSpace)
+}
+
+func addressName(addressSpace string, address string) string {
+	return fmt.Sprintf("%s.%s", addressSpace, strings.ToLower(strings.Replace(address, "topic/", "", 1))) //a topic has a prefix.
 }
 
 func newQueue(cr *pushv1alpha1.UnifiedPushServer, address string) *enmassev1beta.Address {
-	name := fmt.Sprintf("ups.%s", strings.ToLower(address))
+	name := addressName(addressSpaceName(cr), address)
 	return &enmassev1beta.Address{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name,
@@ -41,8 +61,7 @@ func newQueue(cr *pushv1alpha1.UnifiedPushServer, address string) *enmassev1beta
 }
 
 func newTopic(cr *pushv1alpha1.UnifiedPushServer, address string) *enmassev1beta.Address {
-
-	name := fmt.Sprintf("ups.%s", strings.ToLower(strings.Replace(address, "topic/", "", 1))) //a topic has a prefix.
+	name := addressName(addressSpaceName(cr), address)
 	return &enmassev1beta.Address{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name,
@@ -69,7 +88,7 @@ func newMessagingUser(cr *pushv1alpha1.UnifiedPushServer) (*messaginguserv1beta.
 
 	return &messaginguserv1beta.MessagingUser{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "ups.upsuser",
+			Name:      messagingUserName(addressSpaceName(cr)),
 			Namespace: cr.Namespace,
 			Labels:    labels(cr, "ups.upsuser"),
 		},
@@ -97,7 +116,7 @@ func newMessagingUser(cr *pushv1alpha1.UnifiedPushServer) (*messaginguserv1beta.
 func newAddressSpace(cr *pushv1alpha1.UnifiedPushServer) *enmassev1beta.AddressSpace {
 	return &enmassev1beta.AddressSpace{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "ups",
+			Name:      addressSpaceName(cr),
 			Namespace: cr.Namespace,
 			Labels:    labels(cr, "ups"),
 		},
@@ -0,0 +1,47 @@
+package unifiedpushserver
+
+import (
+	pushv1alpha1 "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// The images set in the spec of a UnifiedPushServer take precedence
+// over the ones that the operator was configured with through the
+// RELATED_IMAGE_* environment variables, which default to the images
+// in pkg/constants
+
+func unifiedPushImage(cr *pushv1alpha1.UnifiedPushServer) string {
+	return imageOrDefault(cr.Spec.Images.UnifiedPush, cfg.UPSImage)
+}
+
+func oauthProxyImage(cr *pushv1alpha1.UnifiedPushServer) string {
+	if oidcAuth(cr) != nil {
+		return imageOrDefault(cr.Spec.Images.OAuthProxy, cfg.OIDCProxyImage)
+	}
+	return imageOrDefault(cr.Spec.Images.OAuthProxy, cfg.OauthProxyImage)
+}
+
+func postgresImage(cr *pushv1alpha1.UnifiedPushServer) string {
+	return imageOrDefault(cr.Spec.Images.Postgres, cfg.PostgresImage)
+}
+
+func backupImage(cr *pushv1alpha1.UnifiedPushServer) string {
+	return imageOrDefault(cr.Spec.Images.Backup, cfg.BackupImage)
+}
+
+func imageOrDefault(image string, defaultImage string) string {
+	if image == "" {
+		return defaultImage
+	}
+	return image
+}
+
+// imagePullPolicy returns the pull policy of all of the containers,
+// which has always been Always unless it's set in the spec
+func imagePullPolicy(cr *pushv1alpha1.UnifiedPushServer) corev1.PullPolicy {
+	if cr.Spec.ImagePullPolicy == "" {
+		return corev1.PullAlways
+	}
+	return cr.Spec.ImagePullPolicy
+}
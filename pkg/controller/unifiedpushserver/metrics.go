@@ -0,0 +1,21 @@
+package unifiedpushserver
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// skippedDeploymentUpdates counts the reconciles of the UnifiedPush
+// Server Deployment that found it up to date, and so didn't update it
+var skippedDeploymentUpdates = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "unifiedpush_operator_deployment_updates_skipped_total",
+		Help: "Number of reconciles of the UnifiedPush Server Deployment that found nothing to update",
+	},
+	[]string{"namespace", "name"},
+)
+
+func init() {
+	// Served along with the controller-runtime metrics
+	metrics.Registry.MustRegister(skippedDeploymentUpdates)
+}
@@ -0,0 +1,86 @@
+package unifiedpushserver
+
+import (
+	"context"
+
+	pushv1alpha1 "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1"
+
+	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
+	enmassev1beta "github.com/enmasseproject/enmasse/pkg/apis/enmasse/v1beta1"
+	messaginguserv1beta "github.com/enmasseproject/enmasse/pkg/apis/user/v1beta1"
+	integreatlyv1alpha1 "github.com/integr8ly/grafana-operator/pkg/apis/integreatly/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Names of the secondary resources from before they were derived from
+// the name of the UnifiedPushServer, when there could only be one of
+// them per namespace
+const (
+	legacyMonitoringName           = "unifiedpush"
+	legacyGrafanaDashboardName     = "unifiedpushserver-dashboard"
+	legacyAddressSpaceName         = "ups"
+	legacyBackupServiceAccountName = "backupjob"
+)
+
+type object interface {
+	metav1.Object
+	runtime.Object
+}
+
+// deleteLegacyObject deletes the object with the given legacy name, so
+// that it's renamed once its replacement with the given name has been
+// created. Objects that aren't controlled by the UnifiedPushServer,
+// e.g. the ones of another instance, are left alone.
+func (r *ReconcileUnifiedPushServer) deleteLegacyObject(instance *pushv1alpha1.UnifiedPushServer, obj object, legacyName string, name string) error {
+	if legacyName == name {
+		return nil
+	}
+
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: legacyName, Namespace: instance.Namespace}, obj)
+	if _, isNoKindMatchError := err.(*meta.NoKindMatchError); isNoKindMatchError || errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if !metav1.IsControlledBy(obj, instance) {
+		return nil
+	}
+
+	log.Info("Deleting object with a legacy name", "Namespace", instance.Namespace, "Name", legacyName, "Replacement", name)
+	err = r.client.Delete(context.TODO(), obj)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// deleteLegacyAMQObjects deletes the AddressSpace, MessagingUser and
+// Addresses with legacy names, once their replacements are ready
+func (r *ReconcileUnifiedPushServer) deleteLegacyAMQObjects(instance *pushv1alpha1.UnifiedPushServer) error {
+	addressSpace := addressSpaceName(instance)
+	for _, address := range append(append([]string{}, amqQueues...), amqTopics...) {
+		if err := r.deleteLegacyObject(instance, &enmassev1beta.Address{}, addressName(legacyAddressSpaceName, address), addressName(addressSpace, address)); err != nil {
+			return err
+		}
+	}
+	if err := r.deleteLegacyObject(instance, &messaginguserv1beta.MessagingUser{}, messagingUserName(legacyAddressSpaceName), messagingUserName(addressSpace)); err != nil {
+		return err
+	}
+	return r.deleteLegacyObject(instance, &enmassev1beta.AddressSpace{}, legacyAddressSpaceName, addressSpace)
+}
+
+// deleteLegacyMonitoringObjects deletes the ServiceMonitor,
+// PrometheusRule and GrafanaDashboard with legacy names
+func (r *ReconcileUnifiedPushServer) deleteLegacyMonitoringObjects(instance *pushv1alpha1.UnifiedPushServer, serviceMonitorName string, prometheusRuleName string, grafanaDashboardName string) error {
+	if err := r.deleteLegacyObject(instance, &monitoringv1.ServiceMonitor{}, legacyMonitoringName, serviceMonitorName); err != nil {
+		return err
+	}
+	if err := r.deleteLegacyObject(instance, &monitoringv1.PrometheusRule{}, legacyMonitoringName, prometheusRuleName); err != nil {
+		return err
+	}
+	return r.deleteLegacyObject(instance, &integreatlyv1alpha1.GrafanaDashboard{}, legacyGrafanaDashboardName, grafanaDashboardName)
+}
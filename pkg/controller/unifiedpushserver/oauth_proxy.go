@@ -0,0 +1,231 @@
+package unifiedpushserver
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	pushv1alpha1 "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// RotateCookieSecretAnnotation can be set on a UnifiedPushServer to
+	// rotate the cookie secret of the OAuth proxy. The secret is
+	// regenerated whenever the value changes, e.g. to the current time.
+	RotateCookieSecretAnnotation = "push.aerogear.org/rotate-cookie-secret"
+
+	// cookieSecretRotationAnnotation records on the Secret the value of
+	// RotateCookieSecretAnnotation that the cookie secret was last
+	// generated for
+	cookieSecretRotationAnnotation = "push.aerogear.org/cookie-secret-rotation"
+
+	// cookieSecretHashAnnotation is set on the pod template of the UPS
+	// Deployment, so that the pods are rolled when the secret changes
+	cookieSecretHashAnnotation = "push.aerogear.org/cookie-secret-hash"
+
+	cookieSecretKey        = "cookie-secret"
+	oauthProxyVolumeName   = "oauth-proxy-secret"
+	oauthProxySecretsMount = "/etc/proxy/secrets"
+
+	// The keys of the client credentials in the Secret referenced by
+	// spec.auth.oidc.clientSecret
+	oidcClientIDKey     = "client-id"
+	oidcClientSecretKey = "client-secret"
+
+	// oauthProxySkipAuthRegex matches the paths that don't need a login:
+	// the sender and device registration APIs, which are authenticated
+	// by UPS itself, and the metrics
+	oauthProxySkipAuthRegex = "/rest/sender,/rest/registry/device,/rest/prometheus/metrics,/rest/auth/config"
+)
+
+func oauthProxySecretName(cr *pushv1alpha1.UnifiedPushServer) string {
+	return fmt.Sprintf("%s-oauth-proxy", cr.Name)
+}
+
+// reconcileOauthProxySecret makes sure that the Secret holds a cookie
+// secret for the OAuth proxy. It's only generated when it's missing or
+// a rotation has been requested with RotateCookieSecretAnnotation. The
+// given previous value, e.g. taken from the arguments of an existing
+// proxy, is used instead of generating a new one when it's set.
+func reconcileOauthProxySecret(secret *corev1.Secret, cr *pushv1alpha1.UnifiedPushServer, previous string) error {
+	secret.Labels = mergeStringMaps(secret.Labels, labels(cr, "oauth-proxy"))
+
+	rotation := cr.Annotations[RotateCookieSecretAnnotation]
+	rotate := rotation != secret.Annotations[cookieSecretRotationAnnotation]
+	if len(secret.Data[cookieSecretKey]) > 0 && !rotate {
+		return nil
+	}
+
+	cookieSecret := previous
+	if cookieSecret == "" || len(secret.Data[cookieSecretKey]) > 0 {
+		var err error
+		cookieSecret, err = generatePassword()
+		if err != nil {
+			return errors.Wrap(err, "error generating cookie secret")
+		}
+	}
+
+	reconcileSecretData(secret, map[string]string{
+		cookieSecretKey: cookieSecret,
+	})
+	secret.Annotations = mergeStringMaps(secret.Annotations, map[string]string{
+		cookieSecretRotationAnnotation: rotation,
+	})
+	return nil
+}
+
+// oidcAuth returns the OpenID Connect settings of the admin console, or
+// nil if users log in with OpenShift
+func oidcAuth(cr *pushv1alpha1.UnifiedPushServer) *pushv1alpha1.UnifiedPushServerOIDC {
+	if cr.Spec.Auth == nil {
+		return nil
+	}
+	return cr.Spec.Auth.OIDC
+}
+
+// openshiftAuth returns the access checks of the OpenShift OAuth
+// proxy, or nil if all users are let in
+func openshiftAuth(cr *pushv1alpha1.UnifiedPushServer) *pushv1alpha1.UnifiedPushServerOpenShiftAuth {
+	if cr.Spec.Auth == nil {
+		return nil
+	}
+	return cr.Spec.Auth.OpenShift
+}
+
+// openshiftSAR returns the access check of the OpenShift OAuth proxy,
+// or nil if there is none. Once spec.auth.openshift is set, only the
+// admins of the namespace are let in by default, as they're the ones
+// that can create RoleBindings in it.
+func openshiftSAR(cr *pushv1alpha1.UnifiedPushServer) *pushv1alpha1.UnifiedPushServerSubjectAccessReview {
+	openshift := openshiftAuth(cr)
+	if openshift == nil {
+		return nil
+	}
+
+	if openshift.SubjectAccessReview != nil {
+		sar := *openshift.SubjectAccessReview
+		if sar.Namespace == "" {
+			sar.Namespace = cr.Namespace
+		}
+		return &sar
+	}
+	if len(openshift.AllowedGroups) > 0 {
+		return nil
+	}
+	return &pushv1alpha1.UnifiedPushServerSubjectAccessReview{
+		Namespace: cr.Namespace,
+		Group:     "rbac.authorization.k8s.io",
+		Resource:  "rolebindings",
+		Verb:      "create",
+	}
+}
+
+// oauthProxyArgs returns the arguments of the proxy in front of the
+// admin console. That's the OpenShift OAuth proxy, or oauth2-proxy for
+// an OpenID Connect provider.
+func oauthProxyArgs(cr *pushv1alpha1.UnifiedPushServer) []string {
+	oidc := oidcAuth(cr)
+	if oidc == nil {
+		args := []string{
+			"--provider=openshift",
+			fmt.Sprintf("--openshift-service-account=%s", cr.Name),
+			"--upstream=http://localhost:8080",
+			"--http-address=0.0.0.0:4180",
+			fmt.Sprintf("--skip-auth-regex=%s", oauthProxySkipAuthRegex),
+			"--https-address=",
+			fmt.Sprintf("--cookie-secret-file=%s/%s", oauthProxySecretsMount, cookieSecretKey),
+		}
+		openshift := openshiftAuth(cr)
+		if openshift == nil {
+			return args
+		}
+		if sar := openshiftSAR(cr); sar != nil {
+			// Marshalling a struct of strings can't fail
+			sarJSON, _ := json.Marshal(sar)
+			args = append(args, fmt.Sprintf("--openshift-sar=%s", sarJSON))
+			if openshift.BearerTokens {
+				// Bearer tokens, e.g. from `oc whoami -t`, need the same
+				// access as the users that log in
+				args = append(args, fmt.Sprintf(`--openshift-delegate-urls={"/":%s}`, sarJSON))
+			}
+		}
+		for _, group := range openshift.AllowedGroups {
+			args = append(args, fmt.Sprintf("--openshift-group=%s", group))
+		}
+		return args
+	}
+
+	args := []string{
+		"--provider=oidc",
+		fmt.Sprintf("--oidc-issuer-url=%s", oidc.IssuerURL),
+		"--upstream=http://localhost:8080",
+		"--http-address=0.0.0.0:4180",
+		fmt.Sprintf("--skip-auth-regex=%s", oauthProxySkipAuthRegex),
+		// TLS is terminated by the Route or Ingress, which tell the
+		// proxy the original scheme for its redirect URL
+		"--reverse-proxy=true",
+		// Users are let in based on their groups rather than on the
+		// domain of their email address
+		"--email-domain=*",
+	}
+	for _, group := range oidc.AllowedGroups {
+		args = append(args, fmt.Sprintf("--allowed-group=%s", group))
+	}
+	return args
+}
+
+// oauthProxyEnv returns the environment of the proxy. oauth2-proxy
+// takes the client credentials and the cookie secret from it, while
+// the OpenShift OAuth proxy doesn't need any.
+func oauthProxyEnv(cr *pushv1alpha1.UnifiedPushServer) []corev1.EnvVar {
+	oidc := oidcAuth(cr)
+	if oidc == nil {
+		return nil
+	}
+
+	secretKeyRef := func(name string, key string) *corev1.EnvVarSource {
+		return &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: name},
+				Key:                  key,
+			},
+		}
+	}
+	return []corev1.EnvVar{
+		{
+			Name:      "OAUTH2_PROXY_CLIENT_ID",
+			ValueFrom: secretKeyRef(oidc.ClientSecret, oidcClientIDKey),
+		},
+		{
+			Name:      "OAUTH2_PROXY_CLIENT_SECRET",
+			ValueFrom: secretKeyRef(oidc.ClientSecret, oidcClientSecretKey),
+		},
+		{
+			Name:      "OAUTH2_PROXY_COOKIE_SECRET",
+			ValueFrom: secretKeyRef(oauthProxySecretName(cr), cookieSecretKey),
+		},
+	}
+}
+
+// secretHash returns a hash of the data of the given Secret, which
+// changes whenever any of its values change
+func secretHash(secret *corev1.Secret) string {
+	keys := []string{}
+	for k := range secret.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, k := range keys {
+		h.Write([]byte(k))
+		h.Write([]byte{0})
+		h.Write(secret.Data[k])
+		h.Write([]byte{0})
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
@@ -0,0 +1,152 @@
+package unifiedpushserver
+
+import (
+	"reflect"
+	"testing"
+
+	pushv1alpha1 "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestOauthProxyArgs(t *testing.T) {
+
+	openshiftArgs := []string{
+		"--provider=openshift",
+		"--openshift-service-account=example-ups",
+		"--upstream=http://localhost:8080",
+		"--http-address=0.0.0.0:4180",
+		"--skip-auth-regex=" + oauthProxySkipAuthRegex,
+		"--https-address=",
+		"--cookie-secret-file=/etc/proxy/secrets/cookie-secret",
+	}
+	namespaceAdminSAR := `{"namespace":"unifiedpush","group":"rbac.authorization.k8s.io","resource":"rolebindings","verb":"create"}`
+
+	scenarios := []struct {
+		name   string
+		auth   *pushv1alpha1.UnifiedPushServerAuth
+		expect []string
+	}{
+		{
+			name:   "when auth is not set",
+			auth:   nil,
+			expect: openshiftArgs,
+		},
+		{
+			name:   "when OpenShift auth is not set",
+			auth:   &pushv1alpha1.UnifiedPushServerAuth{},
+			expect: openshiftArgs,
+		},
+		{
+			name: "when OpenShift auth is empty",
+			auth: &pushv1alpha1.UnifiedPushServerAuth{
+				OpenShift: &pushv1alpha1.UnifiedPushServerOpenShiftAuth{},
+			},
+			expect: append(append([]string{}, openshiftArgs...),
+				"--openshift-sar="+namespaceAdminSAR,
+			),
+		},
+		{
+			name: "when bearer tokens are allowed",
+			auth: &pushv1alpha1.UnifiedPushServerAuth{
+				OpenShift: &pushv1alpha1.UnifiedPushServerOpenShiftAuth{
+					BearerTokens: true,
+				},
+			},
+			expect: append(append([]string{}, openshiftArgs...),
+				"--openshift-sar="+namespaceAdminSAR,
+				`--openshift-delegate-urls={"/":`+namespaceAdminSAR+`}`,
+			),
+		},
+		{
+			name: "when groups are allowed",
+			auth: &pushv1alpha1.UnifiedPushServerAuth{
+				OpenShift: &pushv1alpha1.UnifiedPushServerOpenShiftAuth{
+					AllowedGroups: []string{"push-admins", "developers"},
+				},
+			},
+			expect: append(append([]string{}, openshiftArgs...),
+				"--openshift-group=push-admins",
+				"--openshift-group=developers",
+			),
+		},
+		{
+			name: "when a subject access review is set",
+			auth: &pushv1alpha1.UnifiedPushServerAuth{
+				OpenShift: &pushv1alpha1.UnifiedPushServerOpenShiftAuth{
+					SubjectAccessReview: &pushv1alpha1.UnifiedPushServerSubjectAccessReview{
+						Group:    "push.aerogear.org",
+						Resource: "pushapplications",
+						Verb:     "update",
+					},
+				},
+			},
+			expect: append(append([]string{}, openshiftArgs...),
+				`--openshift-sar={"namespace":"unifiedpush","group":"push.aerogear.org","resource":"pushapplications","verb":"update"}`,
+			),
+		},
+		{
+			name: "when a subject access review in another namespace and groups are set",
+			auth: &pushv1alpha1.UnifiedPushServerAuth{
+				OpenShift: &pushv1alpha1.UnifiedPushServerOpenShiftAuth{
+					SubjectAccessReview: &pushv1alpha1.UnifiedPushServerSubjectAccessReview{
+						Namespace:    "push-admin",
+						Resource:     "services",
+						ResourceName: "example-ups-unifiedpush",
+						Verb:         "get",
+					},
+					AllowedGroups: []string{"push-admins"},
+					BearerTokens:  true,
+				},
+			},
+			expect: append(append([]string{}, openshiftArgs...),
+				`--openshift-sar={"namespace":"push-admin","resource":"services","resourceName":"example-ups-unifiedpush","verb":"get"}`,
+				`--openshift-delegate-urls={"/":{"namespace":"push-admin","resource":"services","resourceName":"example-ups-unifiedpush","verb":"get"}}`,
+				"--openshift-group=push-admins",
+			),
+		},
+		{
+			name: "when OIDC auth is set",
+			auth: &pushv1alpha1.UnifiedPushServerAuth{
+				OIDC: &pushv1alpha1.UnifiedPushServerOIDC{
+					IssuerURL:     "https://keycloak.example.com/auth/realms/push",
+					ClientSecret:  "ups-oidc-client",
+					AllowedGroups: []string{"push-admins"},
+				},
+			},
+			expect: []string{
+				"--provider=oidc",
+				"--oidc-issuer-url=https://keycloak.example.com/auth/realms/push",
+				"--upstream=http://localhost:8080",
+				"--http-address=0.0.0.0:4180",
+				"--skip-auth-regex=" + oauthProxySkipAuthRegex,
+				"--reverse-proxy=true",
+				"--email-domain=*",
+				"--allowed-group=push-admins",
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			cr := &pushv1alpha1.UnifiedPushServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "example-ups",
+					Namespace: "unifiedpush",
+				},
+				Spec: pushv1alpha1.UnifiedPushServerSpec{
+					Auth: scenario.auth,
+				},
+			}
+			args := oauthProxyArgs(cr)
+			if !reflect.DeepEqual(args, scenario.expect) {
+				t.Fatalf(
+					"Actual vs. expected differs:\nActual: %v\nExpected: %v",
+					args,
+					scenario.expect,
+				)
+			}
+		})
+	}
+
+}
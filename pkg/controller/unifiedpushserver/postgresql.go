@@ -3,12 +3,12 @@ package unifiedpushserver
 import (
 	"fmt"
 
-	"github.com/aerogear/unifiedpush-operator/pkg/constants"
-
 	pushv1alpha1 "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1"
+	"github.com/aerogear/unifiedpush-operator/pkg/controller/util"
 	"github.com/pkg/errors"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -21,8 +21,10 @@ func newPostgresqlPersistentVolumeClaim(cr *pushv1alpha1.UnifiedPushServer) (*co
 		return nil, errors.Wrap(err, "error parsing PostgreSQL PVC storage size")
 	}
 
+	claimMeta := objectMeta(cr, "postgresql")
+	claimMeta.Name = util.PostgresClaimName(cr)
 	return &corev1.PersistentVolumeClaim{
-		ObjectMeta: objectMeta(cr, "postgresql"),
+		ObjectMeta: claimMeta,
 		Spec: corev1.PersistentVolumeClaimSpec{
 			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
 			Resources: corev1.ResourceRequirements{
@@ -34,41 +36,49 @@ func newPostgresqlPersistentVolumeClaim(cr *pushv1alpha1.UnifiedPushServer) (*co
 	}, nil
 }
 
-func newPostgresqlSecret(cr *pushv1alpha1.UnifiedPushServer) (*corev1.Secret, error) {
+// reconcilePostgresqlSecret sets the connection details of the
+// database in the Secret. The password of the database managed by the
+// operator is only generated if the Secret doesn't have one yet, and
+// keys that aren't set here are left alone.
+func reconcilePostgresqlSecret(secret *corev1.Secret, cr *pushv1alpha1.UnifiedPushServer) error {
+	var desired map[string]string
 	if !cr.Spec.ExternalDB {
-		databasePassword, err := generatePassword()
-		if err != nil {
-			return nil, err
+		databasePassword := string(secret.Data["POSTGRES_PASSWORD"])
+		if databasePassword == "" {
+			var err error
+			databasePassword, err = generatePassword()
+			if err != nil {
+				return err
+			}
 		}
 
-		return &corev1.Secret{
-			ObjectMeta: objectMeta(cr, "postgresql"),
-			StringData: map[string]string{
-				// backup-container-image relies on specific keys being set here:
-				// https://github.com/integr8ly/backup-container-image/blob/master/image/tools/lib/component/postgres.sh
-				"POSTGRES_DATABASE":  "unifiedpush",
-				"POSTGRES_USERNAME":  "unifiedpush",
-				"POSTGRES_PASSWORD":  databasePassword,
-				"POSTGRES_HOST":      fmt.Sprintf("%s-postgresql.%s.svc", cr.Name, cr.Namespace),
-				"POSTGRES_PORT":      "5432",
-				"POSTGRES_SUPERUSER": "false",
-				"POSTGRES_VERSION":   "10",
-			},
-		}, nil
+		desired = map[string]string{
+			// backup-container-image relies on specific keys being set here:
+			// https://github.com/integr8ly/backup-container-image/blob/master/image/tools/lib/component/postgres.sh
+			"POSTGRES_DATABASE":  "unifiedpush",
+			"POSTGRES_USERNAME":  "unifiedpush",
+			"POSTGRES_PASSWORD":  databasePassword,
+			"POSTGRES_HOST":      fmt.Sprintf("%s-postgresql.%s.svc", cr.Name, cr.Namespace),
+			"POSTGRES_PORT":      "5432",
+			"POSTGRES_SUPERUSER": "false",
+			"POSTGRES_VERSION":   "10",
+		}
+		initCredentialRotation(secret, cr)
 	} else {
-		return &corev1.Secret{
-			ObjectMeta: objectMeta(cr, "postgresql"),
-			StringData: map[string]string{
-				"POSTGRES_DATABASE":  cr.Spec.Database.Name,
-				"POSTGRES_USERNAME":  cr.Spec.Database.User,
-				"POSTGRES_PASSWORD":  cr.Spec.Database.Password,
-				"POSTGRES_HOST":      cr.Spec.Database.Host,
-				"POSTGRES_PORT":      cr.Spec.Database.Port.String(),
-				"POSTGRES_SUPERUSER": "false",
-				"POSTGRES_VERSION":   "10",
-			},
-		}, nil
+		desired = map[string]string{
+			"POSTGRES_DATABASE":  cr.Spec.Database.Name,
+			"POSTGRES_USERNAME":  cr.Spec.Database.User,
+			"POSTGRES_PASSWORD":  cr.Spec.Database.Password,
+			"POSTGRES_HOST":      cr.Spec.Database.Host,
+			"POSTGRES_PORT":      cr.Spec.Database.Port.String(),
+			"POSTGRES_SUPERUSER": "false",
+			"POSTGRES_VERSION":   "10",
+		}
 	}
+
+	secret.Labels = mergeStringMaps(secret.Labels, labels(cr, "postgresql"))
+	reconcileSecretData(secret, desired)
+	return nil
 }
 
 func newPostgresqlDeployment(cr *pushv1alpha1.UnifiedPushServer) (*appsv1.Deployment, error) {
@@ -92,8 +102,8 @@ func newPostgresqlDeployment(cr *pushv1alpha1.UnifiedPushServer) (*appsv1.Deploy
 					Containers: []corev1.Container{
 						{
 							Name:            cfg.PostgresContainerName,
-							Image:           constants.PostgresImage,
-							ImagePullPolicy: corev1.PullAlways,
+							Image:           postgresImage(cr),
+							ImagePullPolicy: imagePullPolicy(cr),
 							Env: []corev1.EnvVar{
 								{
 									Name: "POSTGRESQL_USER",
@@ -169,14 +179,15 @@ func newPostgresqlDeployment(cr *pushv1alpha1.UnifiedPushServer) (*appsv1.Deploy
 							},
 						},
 					},
-					Affinity:    cr.Spec.Affinity,
-					Tolerations: cr.Spec.Tolerations,
+					Affinity:         cr.Spec.Affinity,
+					Tolerations:      cr.Spec.Tolerations,
+					ImagePullSecrets: cr.Spec.ImagePullSecrets,
 					Volumes: []corev1.Volume{
 						{
 							Name: fmt.Sprintf("%s-postgresql-data", cr.Name),
 							VolumeSource: corev1.VolumeSource{
 								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
-									ClaimName: fmt.Sprintf("%s-postgresql", cr.Name),
+									ClaimName: util.PostgresClaimName(cr),
 								},
 							},
 						},
@@ -187,20 +198,81 @@ func newPostgresqlDeployment(cr *pushv1alpha1.UnifiedPushServer) (*appsv1.Deploy
 	}, nil
 }
 
-func newPostgresqlService(cr *pushv1alpha1.UnifiedPushServer) (*corev1.Service, error) {
-	return &corev1.Service{
-		ObjectMeta: objectMeta(cr, "postgresql"),
-		Spec: corev1.ServiceSpec{
-			Selector: labels(cr, "postgresql"),
-			Ports: []corev1.ServicePort{
-				corev1.ServicePort{
-					Name:     "postgresql",
-					Protocol: corev1.ProtocolTCP,
-					Port:     5432,
+func reconcilePostgresqlService(service *corev1.Service, cr *pushv1alpha1.UnifiedPushServer) {
+	service.Labels = mergeStringMaps(service.Labels, labels(cr, "postgresql"))
+	reconcileServiceSpec(service, labels(cr, "postgresql"), []corev1.ServicePort{
+		corev1.ServicePort{
+			Name:       "postgresql",
+			Protocol:   corev1.ProtocolTCP,
+			Port:       5432,
+			TargetPort: intstr.FromInt(5432),
+		},
+	})
+}
+
+func postgresqlRotationJobName(cr *pushv1alpha1.UnifiedPushServer) string {
+	return fmt.Sprintf("%s-postgresql-rotate", cr.Name)
+}
+
+// newPostgresqlRotationJob returns a Job that changes the password of
+// the database user to the one in POSTGRES_NEW_PASSWORD, logging in
+// with the current one
+func newPostgresqlRotationJob(cr *pushv1alpha1.UnifiedPushServer) *batchv1.Job {
+	backoffLimit := int32(3)
+	secretKeyRef := func(key string) *corev1.EnvVarSource {
+		return &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				Key: key,
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: postgresqlSecretName(cr),
+				},
+			},
+		}
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      postgresqlRotationJobName(cr),
+			Namespace: cr.Namespace,
+			Labels:    labels(cr, "postgresql-rotate"),
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					// Not the postgresql labels, or the pod would become
+					// an endpoint of the database Service
+					Labels: labels(cr, "postgresql-rotate"),
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:            "rotate-password",
+							Image:           postgresImage(cr),
+							ImagePullPolicy: imagePullPolicy(cr),
+							Env: []corev1.EnvVar{
+								{Name: "PGHOST", ValueFrom: secretKeyRef("POSTGRES_HOST")},
+								{Name: "PGPORT", ValueFrom: secretKeyRef("POSTGRES_PORT")},
+								{Name: "PGDATABASE", ValueFrom: secretKeyRef("POSTGRES_DATABASE")},
+								{Name: "PGUSER", ValueFrom: secretKeyRef("POSTGRES_USERNAME")},
+								{Name: "PGPASSWORD", ValueFrom: secretKeyRef("POSTGRES_PASSWORD")},
+								{Name: "NEW_PASSWORD", ValueFrom: secretKeyRef(newPostgresPasswordKey)},
+							},
+							Command: []string{
+								"/bin/sh",
+								"-c",
+								`source /opt/rh/rh-postgresql96/enable && psql -v ON_ERROR_STOP=1 -q -c "ALTER ROLE \"$PGUSER\" WITH PASSWORD '$NEW_PASSWORD'"`,
+							},
+						},
+					},
+					Affinity:         cr.Spec.Affinity,
+					Tolerations:      cr.Spec.Tolerations,
+					ImagePullSecrets: cr.Spec.ImagePullSecrets,
 				},
 			},
 		},
-	}, nil
+	}
 }
 
 func postgresqlSecretName(cr *pushv1alpha1.UnifiedPushServer) string {
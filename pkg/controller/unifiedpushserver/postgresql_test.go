@@ -0,0 +1,28 @@
+package unifiedpushserver
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+)
+
+func TestNewPostgresqlRotationJob(t *testing.T) {
+	cr := crWithDefaults.DeepCopy()
+
+	job := newPostgresqlRotationJob(cr)
+
+	service := &corev1.Service{}
+	reconcilePostgresqlService(service, cr)
+	selector := k8slabels.SelectorFromSet(service.Spec.Selector)
+	if selector.Matches(k8slabels.Set(job.Spec.Template.Labels)) {
+		t.Errorf("expected the Job pod labels (%v) not to match the database Service selector (%v)", job.Spec.Template.Labels, service.Spec.Selector)
+	}
+
+	command := job.Spec.Template.Spec.Containers[0].Command
+	script := command[len(command)-1]
+	if !strings.HasPrefix(script, "source /opt/rh/rh-postgresql96/enable && ") {
+		t.Errorf("expected the Job to enable the PostgreSQL collection before running psql, got (%s)", script)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	routev1 "github.com/openshift/api/route/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
 )
 
 const (
@@ -31,6 +32,15 @@ func isRouteReady(route *routev1.Route) bool {
 	return true
 }
 
+// isIngressReady returns true once the ingress controller has given
+// the Ingress an address
+func isIngressReady(ingress *extensionsv1beta1.Ingress) bool {
+	if ingress == nil {
+		return false
+	}
+	return len(ingress.Status.LoadBalancer.Ingress) > 0
+}
+
 func isDeploymentReady(deployment *appsv1.Deployment) (bool, error) {
 	if deployment == nil {
 		return false, nil
@@ -46,6 +56,16 @@ func isDeploymentReady(deployment *appsv1.Deployment) (bool, error) {
 	return deployment.Status.ReadyReplicas != 0, nil
 }
 
+// hasMinimumAvailableReplicas returns true if the deployment is ready
+// and at least min of its replicas are available
+func hasMinimumAvailableReplicas(deployment *appsv1.Deployment, min int32) (bool, error) {
+	ready, err := isDeploymentReady(deployment)
+	if err != nil || !ready {
+		return false, err
+	}
+	return deployment.Status.AvailableReplicas >= min, nil
+}
+
 func isJobReady(job *batchv1.Job) (bool, error) {
 	if job == nil {
 		return false, nil
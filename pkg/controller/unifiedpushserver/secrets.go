@@ -0,0 +1,77 @@
+package unifiedpushserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// secretsHashAnnotation is set on the pod templates of the UPS and
+// PostgreSQL Deployments, so that the pods are rolled when any of the
+// Secret values that they read through their env change
+const secretsHashAnnotation = "push.aerogear.org/secrets-hash"
+
+// secretKeyRefsHash returns a hash of the values of the Secret keys that
+// the env of the containers in the given pod spec refers to. Secrets and
+// keys that don't exist are left out, so the hash changes once they're
+// created.
+func (r *ReconcileUnifiedPushServer) secretKeyRefsHash(namespace string, podSpec *corev1.PodSpec) (string, error) {
+	refs := []*corev1.SecretKeySelector{}
+	for _, containers := range [][]corev1.Container{podSpec.InitContainers, podSpec.Containers} {
+		for _, container := range containers {
+			for _, env := range container.Env {
+				if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil {
+					refs = append(refs, env.ValueFrom.SecretKeyRef)
+				}
+			}
+		}
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].Name != refs[j].Name {
+			return refs[i].Name < refs[j].Name
+		}
+		return refs[i].Key < refs[j].Key
+	})
+
+	secrets := map[string]*corev1.Secret{}
+	h := sha256.New()
+	for _, ref := range refs {
+		secret, ok := secrets[ref.Name]
+		if !ok {
+			secret = &corev1.Secret{}
+			err := r.client.Get(context.TODO(), types.NamespacedName{Name: ref.Name, Namespace: namespace}, secret)
+			if err != nil && !errors.IsNotFound(err) {
+				return "", err
+			}
+			secrets[ref.Name] = secret
+		}
+		value, ok := secret.Data[ref.Key]
+		if !ok {
+			continue
+		}
+		h.Write([]byte(ref.Name))
+		h.Write([]byte{0})
+		h.Write([]byte(ref.Key))
+		h.Write([]byte{0})
+		h.Write(value)
+		h.Write([]byte{0})
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+// setSecretsHash sets secretsHashAnnotation on the given pod template
+func (r *ReconcileUnifiedPushServer) setSecretsHash(namespace string, template *corev1.PodTemplateSpec) error {
+	hash, err := r.secretKeyRefsHash(namespace, &template.Spec)
+	if err != nil {
+		return err
+	}
+	template.Annotations = mergeStringMaps(template.Annotations, map[string]string{
+		secretsHashAnnotation: hash,
+	})
+	return nil
+}
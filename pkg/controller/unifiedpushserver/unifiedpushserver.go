@@ -3,7 +3,6 @@ package unifiedpushserver
 import (
 	"fmt"
 
-	"github.com/aerogear/unifiedpush-operator/pkg/constants"
 	"github.com/aerogear/unifiedpush-operator/version"
 	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
 	integreatlyv1alpha1 "github.com/integr8ly/grafana-operator/pkg/apis/integreatly/v1alpha1"
@@ -11,63 +10,103 @@ import (
 	pushv1alpha1 "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1"
 	routev1 "github.com/openshift/api/route/v1"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta1 "k8s.io/api/autoscaling/v2beta1"
 	corev1 "k8s.io/api/core/v1"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"k8s.io/apimachinery/pkg/util/intstr"
-
-	"github.com/pkg/errors"
 )
 
-func newUnifiedPushServiceAccount(cr *pushv1alpha1.UnifiedPushServer) (*corev1.ServiceAccount, error) {
-	return &corev1.ServiceAccount{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      cr.Name,
-			Namespace: cr.Namespace,
-			Annotations: map[string]string{
-				"serviceaccounts.openshift.io/oauth-redirectreference.ups": fmt.Sprintf("{\"kind\":\"OAuthRedirectReference\",\"apiVersion\":\"v1\",\"reference\":{\"kind\":\"Route\",\"name\":\"%s-unifiedpush-proxy\"}}", cr.Name),
+// reconcileUnifiedPushServiceAccount sets the OAuth redirect reference
+// on the ServiceAccount, leaving its secrets to the token controller
+func reconcileUnifiedPushServiceAccount(serviceAccount *corev1.ServiceAccount, cr *pushv1alpha1.UnifiedPushServer) {
+	serviceAccount.Annotations = mergeStringMaps(serviceAccount.Annotations, map[string]string{
+		"serviceaccounts.openshift.io/oauth-redirectreference.ups": fmt.Sprintf("{\"kind\":\"OAuthRedirectReference\",\"apiVersion\":\"v1\",\"reference\":{\"kind\":\"Route\",\"name\":\"%s-unifiedpush-proxy\"}}", cr.Name),
+	})
+}
+
+func reconcileOauthProxyService(service *corev1.Service, cr *pushv1alpha1.UnifiedPushServer) {
+	service.Labels = mergeStringMaps(service.Labels, labels(cr, "unifiedpush-proxy"))
+	reconcileServiceSpec(service, map[string]string{
+		"app":     cr.Name,
+		"service": "ups",
+	}, []corev1.ServicePort{
+		corev1.ServicePort{
+			Name:     "web",
+			Protocol: corev1.ProtocolTCP,
+			Port:     80,
+			TargetPort: intstr.IntOrString{
+				Type:   intstr.Int,
+				IntVal: 4180,
 			},
 		},
-	}, nil
+	})
 }
 
-func newOauthProxyService(cr *pushv1alpha1.UnifiedPushServer) (*corev1.Service, error) {
-	return &corev1.Service{
-		ObjectMeta: objectMeta(cr, "unifiedpush-proxy"),
-		Spec: corev1.ServiceSpec{
-			Selector: map[string]string{
-				"app":     cr.Name,
-				"service": "ups",
-			},
-			Ports: []corev1.ServicePort{
-				corev1.ServicePort{
-					Name:     "web",
-					Protocol: corev1.ProtocolTCP,
-					Port:     80,
-					TargetPort: intstr.IntOrString{
-						Type:   intstr.Int,
-						IntVal: 4180,
+// reconcileOauthProxyRoute points the Route at the OAuth proxy Service
+// with edge TLS termination. The host is left alone, as it's either
+// set by the router or by the cluster admin.
+func reconcileOauthProxyRoute(route *routev1.Route, cr *pushv1alpha1.UnifiedPushServer) {
+	route.Labels = mergeStringMaps(route.Labels, labels(cr, "unifiedpush-proxy"))
+	route.Spec.To.Kind = "Service"
+	route.Spec.To.Name = fmt.Sprintf("%s-%s", cr.Name, "unifiedpush-proxy")
+	if route.Spec.TLS == nil {
+		route.Spec.TLS = &routev1.TLSConfig{}
+	}
+	route.Spec.TLS.Termination = routev1.TLSTerminationEdge
+	route.Spec.TLS.InsecureEdgeTerminationPolicy = routev1.InsecureEdgeTerminationPolicyNone
+}
+
+// ingressClassAnnotation selects the ingress controller of an Ingress
+const ingressClassAnnotation = "kubernetes.io/ingress.class"
+
+// reconcileOauthProxyIngress points the Ingress at the OAuth proxy
+// Service, on clusters without Routes
+func reconcileOauthProxyIngress(ingress *extensionsv1beta1.Ingress, cr *pushv1alpha1.UnifiedPushServer) {
+	config := pushv1alpha1.UnifiedPushServerIngress{}
+	if cr.Spec.Ingress != nil {
+		config = *cr.Spec.Ingress
+	}
+
+	ingress.Labels = mergeStringMaps(ingress.Labels, labels(cr, "unifiedpush-proxy"))
+	if config.ClassName != "" {
+		ingress.Annotations = mergeStringMaps(ingress.Annotations, map[string]string{
+			ingressClassAnnotation: config.ClassName,
+		})
+	} else {
+		delete(ingress.Annotations, ingressClassAnnotation)
+	}
+
+	ingress.Spec.Backend = nil
+	ingress.Spec.Rules = []extensionsv1beta1.IngressRule{
+		{
+			Host: config.Host,
+			IngressRuleValue: extensionsv1beta1.IngressRuleValue{
+				HTTP: &extensionsv1beta1.HTTPIngressRuleValue{
+					Paths: []extensionsv1beta1.HTTPIngressPath{
+						{
+							Path: "/",
+							Backend: extensionsv1beta1.IngressBackend{
+								ServiceName: fmt.Sprintf("%s-%s", cr.Name, "unifiedpush-proxy"),
+								ServicePort: intstr.FromString("web"),
+							},
+						},
 					},
 				},
 			},
 		},
-	}, nil
-}
+	}
 
-func newOauthProxyRoute(cr *pushv1alpha1.UnifiedPushServer) (*routev1.Route, error) {
-	return &routev1.Route{
-		ObjectMeta: objectMeta(cr, "unifiedpush-proxy"),
-		Spec: routev1.RouteSpec{
-			To: routev1.RouteTargetReference{
-				Kind: "Service",
-				Name: fmt.Sprintf("%s-%s", cr.Name, "unifiedpush-proxy"),
-			},
-			TLS: &routev1.TLSConfig{
-				Termination:                   routev1.TLSTerminationEdge,
-				InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyNone,
-			},
-		},
-	}, nil
+	ingress.Spec.TLS = nil
+	if config.TLSSecretName != "" {
+		tls := extensionsv1beta1.IngressTLS{SecretName: config.TLSSecretName}
+		if config.Host != "" {
+			tls.Hosts = []string{config.Host}
+		}
+		ingress.Spec.TLS = []extensionsv1beta1.IngressTLS{tls}
+	}
 }
 
 func buildEnv(cr *pushv1alpha1.UnifiedPushServer) []corev1.EnvVar {
@@ -168,165 +207,310 @@ func buildEnv(cr *pushv1alpha1.UnifiedPushServer) []corev1.EnvVar {
 
 }
 
-func newUnifiedPushServerDeployment(cr *pushv1alpha1.UnifiedPushServer) (*appsv1.Deployment, error) {
+// unifiedPushReplicas returns the number of UnifiedPush Server pods
+// that are requested in the spec, which is the minimum number of
+// replicas when autoscaling is enabled
+func unifiedPushReplicas(cr *pushv1alpha1.UnifiedPushServer) int32 {
+	if cr.Spec.Autoscaling != nil && cr.Spec.Autoscaling.MinReplicas != nil {
+		return *cr.Spec.Autoscaling.MinReplicas
+	}
+	if cr.Spec.Replicas == nil {
+		return 1
+	}
+	return *cr.Spec.Replicas
+}
 
-	labels := map[string]string{
+// unifiedPushMaxReplicas returns the number of UnifiedPush Server pods
+// that may be running at most, not counting rolling updates
+func unifiedPushMaxReplicas(cr *pushv1alpha1.UnifiedPushServer) int32 {
+	if cr.Spec.Autoscaling != nil {
+		return cr.Spec.Autoscaling.MaxReplicas
+	}
+	return unifiedPushReplicas(cr)
+}
+
+// unifiedPushMinAvailable returns the number of UnifiedPush Server pods
+// that have to be available, which defaults to all but one of them
+func unifiedPushMinAvailable(cr *pushv1alpha1.UnifiedPushServer) int32 {
+	if cr.Spec.MinAvailable != nil {
+		return *cr.Spec.MinAvailable
+	}
+	if replicas := unifiedPushReplicas(cr); replicas > 1 {
+		return replicas - 1
+	}
+	return 1
+}
+
+// reconcileUnifiedPushHorizontalPodAutoscaler sets the bounds and the
+// metrics that the UPS Deployment is scaled on
+func reconcileUnifiedPushHorizontalPodAutoscaler(hpa *autoscalingv2beta1.HorizontalPodAutoscaler, cr *pushv1alpha1.UnifiedPushServer) {
+	autoscaling := cr.Spec.Autoscaling
+	minReplicas := unifiedPushReplicas(cr)
+	targetCPUUtilization := int32(80)
+	if autoscaling.TargetCPUUtilizationPercentage != nil {
+		targetCPUUtilization = *autoscaling.TargetCPUUtilizationPercentage
+	}
+
+	hpa.Labels = mergeStringMaps(hpa.Labels, map[string]string{
 		"app":     cr.Name,
 		"service": "ups",
+	})
+	hpa.Spec.ScaleTargetRef = autoscalingv2beta1.CrossVersionObjectReference{
+		APIVersion: "apps/v1",
+		Kind:       "Deployment",
+		Name:       cr.Name,
+	}
+	hpa.Spec.MinReplicas = &minReplicas
+	hpa.Spec.MaxReplicas = autoscaling.MaxReplicas
+	hpa.Spec.Metrics = []autoscalingv2beta1.MetricSpec{
+		{
+			Type: autoscalingv2beta1.ResourceMetricSourceType,
+			Resource: &autoscalingv2beta1.ResourceMetricSource{
+				Name:                     corev1.ResourceCPU,
+				TargetAverageUtilization: &targetCPUUtilization,
+			},
+		},
 	}
+	if autoscaling.CustomMetric != nil {
+		hpa.Spec.Metrics = append(hpa.Spec.Metrics, autoscalingv2beta1.MetricSpec{
+			Type: autoscalingv2beta1.PodsMetricSourceType,
+			Pods: &autoscalingv2beta1.PodsMetricSource{
+				MetricName:         autoscaling.CustomMetric.Name,
+				TargetAverageValue: autoscaling.CustomMetric.TargetAverageValue,
+			},
+		})
+	}
+}
 
-	cookieSecret, err := generatePassword()
-	if err != nil {
-		return nil, errors.Wrap(err, "error generating cookie secret")
+// unifiedPushAntiAffinity prefers to schedule the pods with the given
+// labels on different nodes, so that a single node going down doesn't
+// take all of them with it
+func unifiedPushAntiAffinity(labels map[string]string) *corev1.Affinity {
+	return &corev1.Affinity{
+		PodAntiAffinity: &corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+				{
+					Weight: 100,
+					PodAffinityTerm: corev1.PodAffinityTerm{
+						LabelSelector: &metav1.LabelSelector{
+							MatchLabels: labels,
+						},
+						TopologyKey: "kubernetes.io/hostname",
+					},
+				},
+			},
+		},
 	}
+}
 
-	replicas := int32(1)
+// newUnifiedPushPodDisruptionBudget returns a PodDisruptionBudget that
+// keeps the minimum number of UnifiedPush Server pods available through
+// voluntary disruptions like node drains
+func newUnifiedPushPodDisruptionBudget(cr *pushv1alpha1.UnifiedPushServer) *policyv1beta1.PodDisruptionBudget {
+	labels := map[string]string{
+		"app":     cr.Name,
+		"service": "ups",
+	}
+	minAvailable := intstr.FromInt(int(unifiedPushMinAvailable(cr)))
 
-	return &appsv1.Deployment{
+	return &policyv1beta1.PodDisruptionBudget{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      cr.Name,
 			Namespace: cr.Namespace,
 			Labels:    labels,
 		},
-		Spec: appsv1.DeploymentSpec{
-			Replicas: &replicas,
+		Spec: policyv1beta1.PodDisruptionBudgetSpec{
+			MinAvailable: &minAvailable,
 			Selector: &metav1.LabelSelector{
 				MatchLabels: labels,
 			},
-			Strategy: appsv1.DeploymentStrategy{
-				Type: appsv1.RecreateDeploymentStrategyType,
+		},
+	}
+}
+
+// reconcileUnifiedPushServerDeployment sets the fields of the
+// Deployment that the operator manages. Fields that are defaulted by
+// the API server are left alone, so reconciling a Deployment that is
+// up to date doesn't change it. The hash of the OAuth proxy Secret is
+// set on the pod template, so that the pods are rolled when it
+// changes.
+func reconcileUnifiedPushServerDeployment(deployment *appsv1.Deployment, cr *pushv1alpha1.UnifiedPushServer, oauthProxySecretHash string) {
+	labels := map[string]string{
+		"app":     cr.Name,
+		"service": "ups",
+	}
+
+	replicas := unifiedPushReplicas(cr)
+	maxReplicas := unifiedPushMaxReplicas(cr)
+	secretMode := int32(0420)
+
+	deployment.Labels = mergeStringMaps(deployment.Labels, labels)
+	// The HorizontalPodAutoscaler owns the number of replicas once
+	// the Deployment has been created
+	if cr.Spec.Autoscaling == nil || deployment.Spec.Replicas == nil {
+		deployment.Spec.Replicas = &replicas
+	}
+	// The selector can't be changed once the Deployment is created
+	if deployment.Spec.Selector == nil {
+		deployment.Spec.Selector = &metav1.LabelSelector{
+			MatchLabels: labels,
+		}
+	}
+	if maxReplicas > 1 {
+		// Keep all of the replicas serving while a new pod starts
+		maxUnavailable := intstr.FromInt(0)
+		maxSurge := intstr.FromInt(1)
+		deployment.Spec.Strategy = appsv1.DeploymentStrategy{
+			Type: appsv1.RollingUpdateDeploymentStrategyType,
+			RollingUpdate: &appsv1.RollingUpdateDeployment{
+				MaxUnavailable: &maxUnavailable,
+				MaxSurge:       &maxSurge,
 			},
-			Template: corev1.PodTemplateSpec{
-				ObjectMeta: metav1.ObjectMeta{
-					Labels: labels,
+		}
+	} else {
+		deployment.Spec.Strategy = appsv1.DeploymentStrategy{
+			Type: appsv1.RecreateDeploymentStrategyType,
+		}
+	}
+
+	template := &deployment.Spec.Template
+	template.Labels = mergeStringMaps(template.Labels, labels)
+	template.Annotations = mergeStringMaps(template.Annotations, map[string]string{
+		cookieSecretHashAnnotation: oauthProxySecretHash,
+	})
+	template.Spec.ServiceAccountName = cr.Name
+	template.Spec.Volumes = []corev1.Volume{
+		{
+			Name: oauthProxyVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName:  oauthProxySecretName(cr),
+					DefaultMode: &secretMode,
 				},
-				Spec: corev1.PodSpec{
-					ServiceAccountName: cr.Name,
-					InitContainers: []corev1.Container{
-						{
-							Name:            cfg.PostgresContainerName,
-							Image:           constants.PostgresImage,
-							ImagePullPolicy: corev1.PullAlways,
-							Env: []corev1.EnvVar{
-								{
-									Name: "POSTGRES_SERVICE_HOST",
-									ValueFrom: &corev1.EnvVarSource{
-										SecretKeyRef: &corev1.SecretKeySelector{
-											Key: "POSTGRES_HOST",
-											LocalObjectReference: corev1.LocalObjectReference{
-												Name: postgresqlSecretName(cr),
-											},
-										},
-									},
-								},
-							},
-							Command: []string{
-								"/bin/sh",
-								"-c",
-								"source /opt/rh/rh-postgresql96/enable && until pg_isready -h $POSTGRES_SERVICE_HOST; do echo waiting for database; sleep 2; done;",
+			},
+		},
+	}
+	template.Spec.Affinity = cr.Spec.Affinity
+	if template.Spec.Affinity == nil && maxReplicas > 1 {
+		template.Spec.Affinity = unifiedPushAntiAffinity(labels)
+	}
+	template.Spec.Tolerations = cr.Spec.Tolerations
+	template.Spec.ImagePullSecrets = cr.Spec.ImagePullSecrets
+	template.Spec.InitContainers = reconcileContainers(template.Spec.InitContainers, []corev1.Container{
+		{
+			Name:            cfg.PostgresContainerName,
+			Image:           postgresImage(cr),
+			ImagePullPolicy: imagePullPolicy(cr),
+			Env: []corev1.EnvVar{
+				{
+					Name: "POSTGRES_SERVICE_HOST",
+					ValueFrom: &corev1.EnvVarSource{
+						SecretKeyRef: &corev1.SecretKeySelector{
+							Key: "POSTGRES_HOST",
+							LocalObjectReference: corev1.LocalObjectReference{
+								Name: postgresqlSecretName(cr),
 							},
 						},
 					},
-					Containers: []corev1.Container{
-						{
-							Name:            cfg.UPSContainerName,
-							Image:           constants.UPSImage,
-							ImagePullPolicy: corev1.PullAlways,
-							Env:             buildEnv(cr),
-							Resources:       getUnifiedPushResourceRequirements(cr),
-							Ports: []corev1.ContainerPort{
-								{
-									Name:          cfg.UPSContainerName,
-									Protocol:      corev1.ProtocolTCP,
-									ContainerPort: 8080,
-								},
-							},
-							ReadinessProbe: &corev1.Probe{
-								Handler: corev1.Handler{
-									HTTPGet: &corev1.HTTPGetAction{
-										Path: "/rest/applications",
-										Port: intstr.IntOrString{
-											Type:   intstr.Int,
-											IntVal: 8080,
-										},
-									},
-								},
-								InitialDelaySeconds: 15,
-								TimeoutSeconds:      2,
-							},
-							LivenessProbe: &corev1.Probe{
-								Handler: corev1.Handler{
-									HTTPGet: &corev1.HTTPGetAction{
-										Path: "/rest/applications",
-										Port: intstr.IntOrString{
-											Type:   intstr.Int,
-											IntVal: 8080,
-										},
-									},
-								},
-								InitialDelaySeconds: 120,
-								TimeoutSeconds:      10,
-							},
+				},
+			},
+			Command: []string{
+				"/bin/sh",
+				"-c",
+				"source /opt/rh/rh-postgresql96/enable && until pg_isready -h $POSTGRES_SERVICE_HOST; do echo waiting for database; sleep 2; done;",
+			},
+		},
+	})
+	template.Spec.Containers = reconcileContainers(template.Spec.Containers, []corev1.Container{
+		{
+			Name:            cfg.UPSContainerName,
+			Image:           unifiedPushImage(cr),
+			ImagePullPolicy: imagePullPolicy(cr),
+			Env:             buildEnv(cr),
+			Resources:       getUnifiedPushResourceRequirements(cr),
+			Ports: []corev1.ContainerPort{
+				{
+					Name:          cfg.UPSContainerName,
+					Protocol:      corev1.ProtocolTCP,
+					ContainerPort: 8080,
+				},
+			},
+			ReadinessProbe: &corev1.Probe{
+				Handler: corev1.Handler{
+					HTTPGet: &corev1.HTTPGetAction{
+						Path: "/rest/applications",
+						Port: intstr.IntOrString{
+							Type:   intstr.Int,
+							IntVal: 8080,
 						},
-						{
-							Name:            cfg.OauthProxyContainerName,
-							Image:           constants.OauthProxyImage,
-							ImagePullPolicy: corev1.PullAlways,
-							Ports: []corev1.ContainerPort{
-								{
-									Name:          "public",
-									Protocol:      corev1.ProtocolTCP,
-									ContainerPort: 4180,
-								},
-							},
-							Resources: getOauthProxyResourceRequirements(cr),
-							Args: []string{
-								"--provider=openshift",
-								fmt.Sprintf("--openshift-service-account=%s", cr.Name),
-								"--upstream=http://localhost:8080",
-								"--http-address=0.0.0.0:4180",
-								"--skip-auth-regex=/rest/sender,/rest/registry/device,/rest/prometheus/metrics,/rest/auth/config",
-								"--https-address=",
-								fmt.Sprintf("--cookie-secret=%s", cookieSecret),
-							},
+						Scheme: corev1.URISchemeHTTP,
+					},
+				},
+				InitialDelaySeconds: 15,
+				TimeoutSeconds:      2,
+			},
+			LivenessProbe: &corev1.Probe{
+				Handler: corev1.Handler{
+					HTTPGet: &corev1.HTTPGetAction{
+						Path: "/rest/applications",
+						Port: intstr.IntOrString{
+							Type:   intstr.Int,
+							IntVal: 8080,
 						},
+						Scheme: corev1.URISchemeHTTP,
 					},
-					Affinity:    cr.Spec.Affinity,
-					Tolerations: cr.Spec.Tolerations,
 				},
+				InitialDelaySeconds: 120,
+				TimeoutSeconds:      10,
 			},
 		},
-	}, nil
+		{
+			Name:            cfg.OauthProxyContainerName,
+			Image:           oauthProxyImage(cr),
+			ImagePullPolicy: imagePullPolicy(cr),
+			Ports: []corev1.ContainerPort{
+				{
+					Name:          "public",
+					Protocol:      corev1.ProtocolTCP,
+					ContainerPort: 4180,
+				},
+			},
+			Resources: getOauthProxyResourceRequirements(cr),
+			Args:      oauthProxyArgs(cr),
+			Env:       oauthProxyEnv(cr),
+			VolumeMounts: []corev1.VolumeMount{
+				{
+					Name:      oauthProxyVolumeName,
+					MountPath: oauthProxySecretsMount,
+					ReadOnly:  true,
+				},
+			},
+		},
+	})
 }
 
-func newUnifiedPushServerService(cr *pushv1alpha1.UnifiedPushServer) (*corev1.Service, error) {
-	serviceObjectMeta := objectMeta(cr, "unifiedpush")
-	serviceObjectMeta.Annotations = map[string]string{
+func reconcileUnifiedPushServerService(service *corev1.Service, cr *pushv1alpha1.UnifiedPushServer) {
+	serviceLabels := labels(cr, "unifiedpush")
+	serviceLabels["mobile"] = "enabled"
+	serviceLabels["internal"] = "unifiedpush"
+	service.Labels = mergeStringMaps(service.Labels, serviceLabels)
+	service.Annotations = mergeStringMaps(service.Annotations, map[string]string{
 		"org.aerogear.metrics/plain_endpoint": "/rest/prometheus/metrics",
-	}
-	serviceObjectMeta.Labels["mobile"] = "enabled"
-	serviceObjectMeta.Labels["internal"] = "unifiedpush"
-
-	return &corev1.Service{
-		ObjectMeta: serviceObjectMeta,
-		Spec: corev1.ServiceSpec{
-			Selector: map[string]string{
-				"app":     cr.Name,
-				"service": "ups",
-			},
-			Ports: []corev1.ServicePort{
-				corev1.ServicePort{
-					Name:     "web",
-					Protocol: corev1.ProtocolTCP,
-					Port:     80,
-					TargetPort: intstr.IntOrString{
-						Type:   intstr.Int,
-						IntVal: 8080,
-					},
-				},
+	})
+	reconcileServiceSpec(service, map[string]string{
+		"app":     cr.Name,
+		"service": "ups",
+	}, []corev1.ServicePort{
+		corev1.ServicePort{
+			Name:     "web",
+			Protocol: corev1.ProtocolTCP,
+			Port:     80,
+			TargetPort: intstr.IntOrString{
+				Type:   intstr.Int,
+				IntVal: 8080,
 			},
 		},
-	}, nil
+	})
 }
 
 func reconcilePrometheusRule(prometheusRule *monitoringv1.PrometheusRule, cr *pushv1alpha1.UnifiedPushServer) {
@@ -466,11 +650,12 @@ func reconcilePrometheusRule(prometheusRule *monitoringv1.PrometheusRule, cr *pu
 	}
 }
 
-func reconcileServiceMonitor(serviceMonitor *monitoringv1.ServiceMonitor) {
+func reconcileServiceMonitor(serviceMonitor *monitoringv1.ServiceMonitor, cr *pushv1alpha1.UnifiedPushServer) {
 	labels := map[string]string{
 		"monitoring-key": "middleware",
 	}
 	matchLabels := map[string]string{
+		"app":      cr.Name,
 		"internal": "unifiedpush",
 	}
 	serviceMonitor.ObjectMeta.Labels = labels
@@ -1476,7 +1661,7 @@ func reconcileGrafanaDashboard(grafanaDashboard *integreatlyv1alpha1.GrafanaDash
 				]
 			},
 			"timezone": "browser",
-			"title": "UnifiedPush Server",
+			"title": "UnifiedPush Server (` + namespace + `/` + metaName + `)",
 			"version": 1
 			}`,
 	}
@@ -2,13 +2,14 @@ package unifiedpushserver
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"reflect"
 	"time"
 
 	pushv1alpha1 "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1"
 	"github.com/aerogear/unifiedpush-operator/pkg/config"
-	"github.com/aerogear/unifiedpush-operator/pkg/constants"
+	"github.com/aerogear/unifiedpush-operator/pkg/controller/util"
 
 	enmassev1beta "github.com/enmasseproject/enmasse/pkg/apis/enmasse/v1beta1"
 	messaginguserv1beta "github.com/enmasseproject/enmasse/pkg/apis/user/v1beta1"
@@ -19,8 +20,12 @@ import (
 	routev1 "github.com/openshift/api/route/v1"
 
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta1 "k8s.io/api/autoscaling/v2beta1"
+	batchv1 "k8s.io/api/batch/v1"
 	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -108,6 +113,36 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
+	// Watch for changes to Secrets referenced by spec.databaseSecret,
+	// which aren't owned by the UnifiedPushServers, and requeue the ones
+	// that reference them
+	err = c.Watch(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+			return unifiedPushServersForSecret(mgr.GetClient(), o.Meta.GetNamespace(), o.Meta.GetName())
+		}),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to secondary resource HorizontalPodAutoscaler and requeue the owner UnifiedPushServer
+	err = c.Watch(&source.Kind{Type: &autoscalingv2beta1.HorizontalPodAutoscaler{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &pushv1alpha1.UnifiedPushServer{},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to secondary resource PodDisruptionBudget and requeue the owner UnifiedPushServer
+	err = c.Watch(&source.Kind{Type: &policyv1beta1.PodDisruptionBudget{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &pushv1alpha1.UnifiedPushServer{},
+	})
+	if err != nil {
+		return err
+	}
+
 	// Watch for changes to secondary resource PersistentVolumeClaim and requeue the owner UnifiedPushServer
 	err = c.Watch(&source.Kind{Type: &corev1.PersistentVolumeClaim{}}, &handler.EnqueueRequestForOwner{
 		IsController: true,
@@ -140,6 +175,16 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		IsController: true,
 		OwnerType:    &pushv1alpha1.UnifiedPushServer{},
 	})
+	// An Ingress is created instead on clusters without Routes
+	if _, isNoKindMatchError := err.(*meta.NoKindMatchError); err != nil && !isNoKindMatchError {
+		return err
+	}
+
+	// Watch for changes to secondary resource Ingress and requeue the owner UnifiedPushServer
+	err = c.Watch(&source.Kind{Type: &extensionsv1beta1.Ingress{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &pushv1alpha1.UnifiedPushServer{},
+	})
 	if err != nil {
 		return err
 	}
@@ -153,6 +198,15 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
+	// Watch for changes to secondary resource Job and requeue the owner UnifiedPushServer
+	err = c.Watch(&source.Kind{Type: &batchv1.Job{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &pushv1alpha1.UnifiedPushServer{},
+	})
+	if err != nil {
+		return err
+	}
+
 	// Watch for changes to secondary resource MessagingUser and requeue the owner UnifiedPushServer
 	err = c.Watch(&source.Kind{Type: &messaginguserv1beta.MessagingUser{}}, &handler.EnqueueRequestForOwner{
 		IsController: true,
@@ -216,6 +270,24 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	return nil
 }
 
+// unifiedPushServersForSecret returns a request for each
+// UnifiedPushServer that takes its database details from the given Secret
+func unifiedPushServersForSecret(c client.Client, namespace string, name string) []reconcile.Request {
+	unifiedPushServers := &pushv1alpha1.UnifiedPushServerList{}
+	if err := c.List(context.TODO(), client.InNamespace(namespace), unifiedPushServers); err != nil {
+		log.Error(err, "Failed to list UnifiedPushServers", "Namespace", namespace)
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, ups := range unifiedPushServers.Items {
+		if ups.Spec.DatabaseSecret == name {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: ups.Name, Namespace: ups.Namespace}})
+		}
+	}
+	return requests
+}
+
 var _ reconcile.Reconciler = &ReconcileUnifiedPushServer{}
 
 // ReconcileUnifiedPushServer reconciles a UnifiedPushServer object
@@ -264,6 +336,18 @@ func (r *ReconcileUnifiedPushServer) Reconcile(request reconcile.Request) (recon
 		}
 	}
 
+	// UnifiedPushServers that weren't defaulted by the webhook, e.g.
+	// because they were created before it existed, get the defaults in
+	// their spec on the first reconcile, so that changing the operator
+	// defaults doesn't resize them later
+	if instance.SetDefaults(cfg) {
+		reqLogger.Info("Setting defaults in the UnifiedPushServer spec")
+		err = r.client.Update(context.TODO(), instance)
+		if err != nil {
+			return r.manageError(instance, err)
+		}
+	}
+
 	//#region AMQ resource reconcile
 	if instance.Spec.UseMessageBroker {
 		//#region create addressSpace
@@ -271,7 +355,7 @@ func (r *ReconcileUnifiedPushServer) Reconcile(request reconcile.Request) (recon
 
 		// Set UnifiedPushServer instance as the owner and controller
 		if err := controllerutil.SetControllerReference(instance, addressSpace, r.scheme); err != nil {
-			return r.manageError(instance, err)
+			return r.manageComponentError(instance, pushv1alpha1.ConditionMessageBrokerReady, err)
 		}
 
 		foundAddressSpace := &enmassev1beta.AddressSpace{}
@@ -280,15 +364,15 @@ func (r *ReconcileUnifiedPushServer) Reconcile(request reconcile.Request) (recon
 			reqLogger.Info("Creating a new Address Space", "AddressSpace.Namespace", addressSpace.Namespace, "AddressSpace.Name", addressSpace.Name)
 			err = r.client.Create(context.TODO(), addressSpace)
 			if err != nil {
-				return r.manageError(instance, err)
+				return r.manageComponentError(instance, pushv1alpha1.ConditionMessageBrokerReady, err)
 			}
 			reqLogger.Info("Requeuing, AddressSpace not ready.", "AddressSpace.Namespace", addressSpace.Namespace, "AddressSpace.Name", addressSpace.Name)
-			return reconcile.Result{RequeueAfter: time.Second * 10}, nil
+			return r.manageWaiting(instance, pushv1alpha1.ConditionMessageBrokerReady, "AddressSpaceNotReady", fmt.Sprintf("Waiting for AddressSpace %s to be ready", addressSpace.Name), time.Second*10)
 		} else if err != nil {
-			return r.manageError(instance, err)
+			return r.manageComponentError(instance, pushv1alpha1.ConditionMessageBrokerReady, err)
 		} else if !foundAddressSpace.Status.IsReady {
 			reqLogger.Info("Requeuing, AddressSpace not ready.", "AddressSpace.Namespace", foundAddressSpace.Namespace, "AddressSpace.Name", foundAddressSpace.Name)
-			return reconcile.Result{RequeueAfter: time.Second * 10}, nil
+			return r.manageWaiting(instance, pushv1alpha1.ConditionMessageBrokerReady, "AddressSpaceNotReady", fmt.Sprintf("Waiting for AddressSpace %s to be ready", foundAddressSpace.Name), time.Second*10)
 		} else {
 			reqLogger.Info("Found AddressSpace for UPS")
 		}
@@ -299,12 +383,12 @@ func (r *ReconcileUnifiedPushServer) Reconcile(request reconcile.Request) (recon
 		//#region check that user exists
 		user, err := newMessagingUser(instance)
 		if err != nil {
-			return r.manageError(instance, err)
+			return r.manageComponentError(instance, pushv1alpha1.ConditionMessageBrokerReady, err)
 		}
 
 		// Set UnifiedPushServer instance as the owner and controller
 		if err := controllerutil.SetControllerReference(instance, user, r.scheme); err != nil {
-			return r.manageError(instance, err)
+			return r.manageComponentError(instance, pushv1alpha1.ConditionMessageBrokerReady, err)
 		}
 
 		foundUser := &messaginguserv1beta.MessagingUser{}
@@ -313,11 +397,13 @@ func (r *ReconcileUnifiedPushServer) Reconcile(request reconcile.Request) (recon
 			reqLogger.Info("Creating a new MessagingUser", "MessagingUser.Namespace", user.Namespace, "MessagingUser.Name", user.Name)
 			err = r.client.Create(context.TODO(), user)
 			if err != nil {
-				return r.manageError(instance, err)
+				return r.manageComponentError(instance, pushv1alpha1.ConditionMessageBrokerReady, err)
 			}
-
+			foundUser = user
 		} else if err != nil {
-			return r.manageError(instance, err)
+			return r.manageComponentError(instance, pushv1alpha1.ConditionMessageBrokerReady, err)
+		} else if err := r.rotateMessagingUserPassword(instance, foundUser); err != nil {
+			return r.manageComponentError(instance, pushv1alpha1.ConditionMessageBrokerReady, err)
 		}
 		secondaryResources.add("MessagingUser", user.Name)
 		//#endregion
@@ -326,18 +412,18 @@ func (r *ReconcileUnifiedPushServer) Reconcile(request reconcile.Request) (recon
 		for _, status := range foundAddressSpace.Status.EndpointStatus {
 			if status.Name == "messaging" { //"messaging" is a key from enmasse.
 				addressSpaceURL := status.ServiceHost
-				password := string(user.Spec.Authentication.Password)
-				secret := newAMQSecret(instance, password, addressSpaceURL)
-				foundSecret := &corev1.Secret{}
-				err = r.client.Get(context.TODO(), types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}, foundSecret)
-				if err != nil && errors.IsNotFound(err) {
-					reqLogger.Info("Creating a new Secret", "Secret.Namespace", secret.Namespace, "Secret.Name", secret.Name)
-					err = r.client.Create(context.TODO(), secret)
-					if err != nil {
-						return r.manageError(instance, err)
-					}
-				} else if err != nil {
-					return r.manageError(instance, err)
+				password := string(foundUser.Spec.Authentication.Password)
+				secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%s-amq", instance.Name), Namespace: instance.Namespace}}
+				op, err := controllerutil.CreateOrUpdate(context.TODO(), r.client, secret, func(ignore runtime.Object) error {
+					reconcileAMQSecret(secret, instance, password, addressSpaceURL)
+					// Set UnifiedPushServer instance as the owner and controller
+					return controllerutil.SetControllerReference(instance, secret, r.scheme)
+				})
+				if err != nil {
+					return r.manageComponentError(instance, pushv1alpha1.ConditionMessageBrokerReady, err)
+				}
+				if op != controllerutil.OperationResultNone {
+					reqLogger.Info("Secret reconciled", "Secret.Namespace", secret.Namespace, "Secret.Name", secret.Name, "Operation", op)
 				}
 				secondaryResources.add("Secret", secret.Name)
 				break
@@ -346,14 +432,13 @@ func (r *ReconcileUnifiedPushServer) Reconcile(request reconcile.Request) (recon
 		//#endregion
 
 		//#region queues
-		queues := []string{"APNsPushMessageQueue", "APNsTokenBatchQueue", "GCMPushMessageQueue", "GCMTokenBatchQueue", "WNSPushMessageQueue", "WNSTokenBatchQueue", "WebPushMessageQueue", "WebTokenBatchQueue", "MetricsQueue", "TriggerMetricCollectionQueue", "TriggerVariantMetricCollectionQueue", "BatchLoadedQueue", "AllBatchesLoadedQueue", "FreeServiceSlotQueue"}
 		requeueCreate := false
-		for _, address := range queues {
+		for _, address := range amqQueues {
 			queue := newQueue(instance, address)
 			foundQueue := &enmassev1beta.Address{}
 			// Set UnifiedPushServer instance as the owner and controller
 			if err := controllerutil.SetControllerReference(instance, queue, r.scheme); err != nil {
-				return r.manageError(instance, err)
+				return r.manageComponentError(instance, pushv1alpha1.ConditionMessageBrokerReady, err)
 			}
 
 			err = r.client.Get(context.TODO(), types.NamespacedName{Name: queue.Name, Namespace: queue.Namespace}, foundQueue)
@@ -361,12 +446,12 @@ func (r *ReconcileUnifiedPushServer) Reconcile(request reconcile.Request) (recon
 				reqLogger.Info("Creating a new Queue", "Queue.Namespace", queue.Namespace, "Queue.Name", queue.Name)
 				err = r.client.Create(context.TODO(), queue)
 				if err != nil {
-					return r.manageError(instance, err)
+					return r.manageComponentError(instance, pushv1alpha1.ConditionMessageBrokerReady, err)
 				}
 				requeueCreate = true
 			} else if err != nil {
 				reqLogger.Info("Queue Error")
-				return r.manageError(instance, err)
+				return r.manageComponentError(instance, pushv1alpha1.ConditionMessageBrokerReady, err)
 			} else if !foundQueue.Status.IsReady {
 				reqLogger.Info("Queue Not ready", "Queue.Name", foundQueue.Name)
 				requeueCreate = true
@@ -376,20 +461,19 @@ func (r *ReconcileUnifiedPushServer) Reconcile(request reconcile.Request) (recon
 
 		if requeueCreate {
 			reqLogger.Info("Requeueing while queues are created")
-			return reconcile.Result{RequeueAfter: time.Second * 5}, nil
+			return r.manageWaiting(instance, pushv1alpha1.ConditionMessageBrokerReady, "QueuesNotReady", "Waiting for the queues to be ready", time.Second*5)
 		}
 		//#endregion
 
 		reqLogger.Info("Found all queues  for UPS")
 
 		//#region topics
-		topics := []string{"MetricsProcessingStartedTopic", "topic/APNSClient"}
-		for _, address := range topics {
+		for _, address := range amqTopics {
 			topic := newTopic(instance, address)
 			foundTopic := &enmassev1beta.Address{}
 			// Set UnifiedPushServer instance as the owner and controller
 			if err := controllerutil.SetControllerReference(instance, topic, r.scheme); err != nil {
-				return r.manageError(instance, err)
+				return r.manageComponentError(instance, pushv1alpha1.ConditionMessageBrokerReady, err)
 			}
 
 			err = r.client.Get(context.TODO(), types.NamespacedName{Name: topic.Name, Namespace: topic.Namespace}, foundTopic)
@@ -397,23 +481,62 @@ func (r *ReconcileUnifiedPushServer) Reconcile(request reconcile.Request) (recon
 				reqLogger.Info("Creating a new Topic", "Topic.Namespace", topic.Namespace, "Topic.Name", topic.Name)
 				err = r.client.Create(context.TODO(), topic)
 				if err != nil {
-					return r.manageError(instance, err)
+					return r.manageComponentError(instance, pushv1alpha1.ConditionMessageBrokerReady, err)
 				}
 				requeueCreate = true
 			} else if err != nil {
-				return r.manageError(instance, err)
+				return r.manageComponentError(instance, pushv1alpha1.ConditionMessageBrokerReady, err)
 			}
 			secondaryResources.add("Address", topic.Name)
 		}
 
 		if requeueCreate {
 			reqLogger.Info("Requeueing while topics are created")
-			return reconcile.Result{RequeueAfter: time.Second * 5}, nil
+			return r.manageWaiting(instance, pushv1alpha1.ConditionMessageBrokerReady, "TopicsNotReady", "Waiting for the topics to be created", time.Second*5)
 		}
 		//#endregion
 
 		reqLogger.Info("Found All queues and topics for UPS")
 
+		// The AMQ Secret points to the new AddressSpace by now
+		if err := r.deleteLegacyAMQObjects(instance); err != nil {
+			return r.manageComponentError(instance, pushv1alpha1.ConditionMessageBrokerReady, err)
+		}
+		instance.Status.SetCondition(pushv1alpha1.ConditionMessageBrokerReady, corev1.ConditionTrue, "Ready", "AddressSpace, queues and topics are ready")
+	} else {
+		instance.Status.RemoveCondition(pushv1alpha1.ConditionMessageBrokerReady)
+	}
+	//#endregion
+
+	//#region Postgres Secret
+	if instance.Spec.DatabaseSecret == "" {
+		postgresqlSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%s-postgresql", instance.Name), Namespace: instance.Namespace}}
+		op, err := controllerutil.CreateOrUpdate(context.TODO(), r.client, postgresqlSecret, func(ignore runtime.Object) error {
+			if err := reconcilePostgresqlSecret(postgresqlSecret, instance); err != nil {
+				return err
+			}
+			// Set UnifiedPushServer instance as the owner and controller
+			return controllerutil.SetControllerReference(instance, postgresqlSecret, r.scheme)
+		})
+		if err != nil {
+			return r.manageComponentError(instance, pushv1alpha1.ConditionDatabaseReady, err)
+		}
+		if op != controllerutil.OperationResultNone {
+			reqLogger.Info("Secret reconciled", "Secret.Namespace", postgresqlSecret.Namespace, "Secret.Name", postgresqlSecret.Name, "Operation", op)
+		}
+		secondaryResources.add("Secret", postgresqlSecret.Name)
+
+		//#region Postgres credential rotation
+		if !instance.Spec.ExternalDB {
+			rotating, err := r.rotatePostgresqlPassword(instance, postgresqlSecret)
+			if err != nil {
+				return r.manageComponentError(instance, pushv1alpha1.ConditionDatabaseReady, err)
+			}
+			if rotating {
+				return r.manageWaiting(instance, pushv1alpha1.ConditionDatabaseReady, "RotatingCredentials", "Waiting for the password of the database user to be changed", 5*time.Second)
+			}
+		}
+		//#endregion
 	}
 	//#endregion
 
@@ -422,12 +545,12 @@ func (r *ReconcileUnifiedPushServer) Reconcile(request reconcile.Request) (recon
 		//#region Postgres PVC
 		persistentVolumeClaim, err := newPostgresqlPersistentVolumeClaim(instance)
 		if err != nil {
-			return r.manageError(instance, err)
+			return r.manageComponentError(instance, pushv1alpha1.ConditionDatabaseReady, err)
 		}
 
 		// Set UnifiedPushServer instance as the owner and controller
 		if err := controllerutil.SetControllerReference(instance, persistentVolumeClaim, r.scheme); err != nil {
-			return r.manageError(instance, err)
+			return r.manageComponentError(instance, pushv1alpha1.ConditionDatabaseReady, err)
 		}
 
 		// Check if this PersistentVolumeClaim already exists
@@ -437,10 +560,10 @@ func (r *ReconcileUnifiedPushServer) Reconcile(request reconcile.Request) (recon
 			reqLogger.Info("Creating a new PersistentVolumeClaim", "PersistentVolumeClaim.Namespace", persistentVolumeClaim.Namespace, "PersistentVolumeClaim.Name", persistentVolumeClaim.Name)
 			err = r.client.Create(context.TODO(), persistentVolumeClaim)
 			if err != nil {
-				return r.manageError(instance, err)
+				return r.manageComponentError(instance, pushv1alpha1.ConditionDatabaseReady, err)
 			}
 		} else if err != nil {
-			return r.manageError(instance, err)
+			return r.manageComponentError(instance, pushv1alpha1.ConditionDatabaseReady, err)
 		} else {
 			requiredPostgresPVCSize := getPostgresPVCSize(instance)
 
@@ -454,7 +577,7 @@ func (r *ReconcileUnifiedPushServer) Reconcile(request reconcile.Request) (recon
 				err = r.client.Update(context.TODO(), foundPersistentVolumeClaim)
 				if err != nil {
 					reqLogger.Error(err, "Failed to update PersistentVolumeClaim", "PersistentVolumeClaim.Namespace", foundPersistentVolumeClaim.Namespace, "PersistentVolumeClaim.Name", foundPersistentVolumeClaim.Name)
-					return r.manageError(instance, err)
+					return r.manageComponentError(instance, pushv1alpha1.ConditionDatabaseReady, err)
 				}
 				return reconcile.Result{Requeue: true}, nil
 			}
@@ -465,12 +588,15 @@ func (r *ReconcileUnifiedPushServer) Reconcile(request reconcile.Request) (recon
 		//#region Postgres Deployment
 		postgresqlDeployment, err := newPostgresqlDeployment(instance)
 		if err != nil {
-			return r.manageError(instance, err)
+			return r.manageComponentError(instance, pushv1alpha1.ConditionDatabaseReady, err)
+		}
+		if err := r.setSecretsHash(instance.Namespace, &postgresqlDeployment.Spec.Template); err != nil {
+			return r.manageComponentError(instance, pushv1alpha1.ConditionDatabaseReady, err)
 		}
 
 		// Set UnifiedPushServer instance as the owner and controller
 		if err := controllerutil.SetControllerReference(instance, postgresqlDeployment, r.scheme); err != nil {
-			return r.manageError(instance, err)
+			return r.manageComponentError(instance, pushv1alpha1.ConditionDatabaseReady, err)
 		}
 
 		// Check if this Deployment already exists
@@ -480,11 +606,30 @@ func (r *ReconcileUnifiedPushServer) Reconcile(request reconcile.Request) (recon
 			reqLogger.Info("Creating a new Deployment", "Deployment.Namespace", postgresqlDeployment.Namespace, "Deployment.Name", postgresqlDeployment.Name)
 			err = r.client.Create(context.TODO(), postgresqlDeployment)
 			if err != nil {
-				return r.manageError(instance, err)
+				return r.manageComponentError(instance, pushv1alpha1.ConditionDatabaseReady, err)
 			}
+			readyStatus = false
+			instance.Status.SetCondition(pushv1alpha1.ConditionDatabaseReady, corev1.ConditionFalse, "DeploymentNotReady", fmt.Sprintf("Waiting for Deployment %s to be ready", postgresqlDeployment.Name))
 		} else if err != nil {
-			return r.manageError(instance, err)
+			return r.manageComponentError(instance, pushv1alpha1.ConditionDatabaseReady, err)
 		} else {
+			desiredSecretsHash := postgresqlDeployment.Spec.Template.Annotations[secretsHashAnnotation]
+			if foundPostgresqlDeployment.Spec.Template.Annotations[secretsHashAnnotation] != desiredSecretsHash {
+				reqLogger.Info("Secrets referenced by the Postgres container have changed. Going to roll out the Deployment now.", "Deployment.Namespace", foundPostgresqlDeployment.Namespace, "Deployment.Name", foundPostgresqlDeployment.Name)
+
+				foundPostgresqlDeployment.Spec.Template.Annotations = mergeStringMaps(foundPostgresqlDeployment.Spec.Template.Annotations, map[string]string{
+					secretsHashAnnotation: desiredSecretsHash,
+				})
+
+				// No need to requeue, the rest of the reconcile doesn't
+				// depend on the rollout and the status has to be kept
+				err = r.client.Update(context.TODO(), foundPostgresqlDeployment)
+				if err != nil {
+					reqLogger.Error(err, "Failed to update Deployment", "Deployment.Namespace", foundPostgresqlDeployment.Namespace, "Deployment.Name", foundPostgresqlDeployment.Name)
+					return r.manageComponentError(instance, pushv1alpha1.ConditionDatabaseReady, err)
+				}
+			}
+
 			postgresResourceRequirements := getPostgresResourceRequirements(instance)
 
 			containers := foundPostgresqlDeployment.Spec.Template.Spec.Containers
@@ -499,14 +644,14 @@ func (r *ReconcileUnifiedPushServer) Reconcile(request reconcile.Request) (recon
 						err = r.client.Update(context.TODO(), foundPostgresqlDeployment)
 						if err != nil {
 							reqLogger.Error(err, "Failed to update Deployment", "Deployment.Namespace", foundPostgresqlDeployment.Namespace, "Deployment.Name", foundPostgresqlDeployment.Name)
-							return r.manageError(instance, err)
+							return r.manageComponentError(instance, pushv1alpha1.ConditionDatabaseReady, err)
 						}
 						return reconcile.Result{Requeue: true}, nil
 					}
 				}
 			}
 
-			desiredImage := constants.PostgresImage
+			desiredImage := postgresImage(instance)
 
 			containerSpec := findContainerSpec(foundPostgresqlDeployment, cfg.PostgresContainerName)
 			if containerSpec == nil {
@@ -522,340 +667,395 @@ func (r *ReconcileUnifiedPushServer) Reconcile(request reconcile.Request) (recon
 				err = r.client.Update(context.TODO(), foundPostgresqlDeployment)
 				if err != nil {
 					reqLogger.Error(err, "Failed to update Deployment", "Deployment.Namespace", foundPostgresqlDeployment.Namespace, "Deployment.Name", foundPostgresqlDeployment.Name)
-					return r.manageError(instance, err)
+					return r.manageComponentError(instance, pushv1alpha1.ConditionDatabaseReady, err)
+				}
+				return reconcile.Result{Requeue: true}, nil
+			} else if containerSpec.ImagePullPolicy != imagePullPolicy(instance) || !semantic.DeepEqual(foundPostgresqlDeployment.Spec.Template.Spec.ImagePullSecrets, instance.Spec.ImagePullSecrets) {
+				reqLogger.Info("Image pull settings of the Deployment are different than in the UnifiedPushServer spec. Going to update it now.", "Deployment.Namespace", foundPostgresqlDeployment.Namespace, "Deployment.Name", foundPostgresqlDeployment.Name)
+
+				// update
+				for i := range containers {
+					if containers[i].Name == cfg.PostgresContainerName {
+						containers[i].ImagePullPolicy = imagePullPolicy(instance)
+					}
+				}
+				foundPostgresqlDeployment.Spec.Template.Spec.ImagePullSecrets = instance.Spec.ImagePullSecrets
+
+				// enqueue
+				err = r.client.Update(context.TODO(), foundPostgresqlDeployment)
+				if err != nil {
+					reqLogger.Error(err, "Failed to update Deployment", "Deployment.Namespace", foundPostgresqlDeployment.Namespace, "Deployment.Name", foundPostgresqlDeployment.Name)
+					return r.manageComponentError(instance, pushv1alpha1.ConditionDatabaseReady, err)
 				}
 				return reconcile.Result{Requeue: true}, nil
 			}
+			instance.Status.Images.Postgres = containerSpec.Image
 
 			// Set ready status
 			deploymentReady, err := isDeploymentReady(foundPostgresqlDeployment)
 			if err != nil {
-				return r.manageError(instance, err)
+				return r.manageComponentError(instance, pushv1alpha1.ConditionDatabaseReady, err)
 			}
 			readyStatus = readyStatus && deploymentReady
+			if deploymentReady {
+				instance.Status.SetCondition(pushv1alpha1.ConditionDatabaseReady, corev1.ConditionTrue, "DeploymentReady", "")
+			} else {
+				instance.Status.SetCondition(pushv1alpha1.ConditionDatabaseReady, corev1.ConditionFalse, "DeploymentNotReady", fmt.Sprintf("Waiting for Deployment %s to be ready", postgresqlDeployment.Name))
+			}
 		}
 		secondaryResources.add("Deployment", postgresqlDeployment.Name)
 		//#endregion
 
 		//#region Postgres Service
-		postgresqlService, err := newPostgresqlService(instance)
+		postgresqlService := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%s-postgresql", instance.Name), Namespace: instance.Namespace}}
+		op, err := controllerutil.CreateOrUpdate(context.TODO(), r.client, postgresqlService, func(ignore runtime.Object) error {
+			reconcilePostgresqlService(postgresqlService, instance)
+			// Set UnifiedPushServer instance as the owner and controller
+			return controllerutil.SetControllerReference(instance, postgresqlService, r.scheme)
+		})
 		if err != nil {
-			return r.manageError(instance, err)
+			return r.manageComponentError(instance, pushv1alpha1.ConditionDatabaseReady, err)
 		}
-
-		// Set UnifiedPushServer instance as the owner and controller
-		if err := controllerutil.SetControllerReference(instance, postgresqlService, r.scheme); err != nil {
-			return r.manageError(instance, err)
-		}
-
-		// Check if this Service already exists
-		foundPostgresqlService := &corev1.Service{}
-		err = r.client.Get(context.TODO(), types.NamespacedName{Name: postgresqlService.Name, Namespace: postgresqlService.Namespace}, foundPostgresqlService)
-		if err != nil && errors.IsNotFound(err) {
-			reqLogger.Info("Creating a new Service", "Service.Namespace", postgresqlService.Namespace, "Service.Name", postgresqlService.Name)
-			err = r.client.Create(context.TODO(), postgresqlService)
-			if err != nil {
-				return r.manageError(instance, err)
-			}
-		} else if err != nil {
-			return r.manageError(instance, err)
+		if op != controllerutil.OperationResultNone {
+			reqLogger.Info("Service reconciled", "Service.Namespace", postgresqlService.Namespace, "Service.Name", postgresqlService.Name, "Operation", op)
 		}
 
 		secondaryResources.add("Service", postgresqlService.Name)
 		//#endregion
+	} else {
+		instance.Status.SetCondition(pushv1alpha1.ConditionDatabaseReady, corev1.ConditionTrue, "ExternalDatabase", "Using an external database")
+		instance.Status.Images.Postgres = ""
 	}
 
 	//#region ServiceAccount
-	serviceAccount, err := newUnifiedPushServiceAccount(instance)
-
-	// Set UnifiedPushServer instance as the owner and controller
-	if err := controllerutil.SetControllerReference(instance, serviceAccount, r.scheme); err != nil {
+	serviceAccount := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: instance.Name, Namespace: instance.Namespace}}
+	op, err := controllerutil.CreateOrUpdate(context.TODO(), r.client, serviceAccount, func(ignore runtime.Object) error {
+		reconcileUnifiedPushServiceAccount(serviceAccount, instance)
+		// Set UnifiedPushServer instance as the owner and controller
+		return controllerutil.SetControllerReference(instance, serviceAccount, r.scheme)
+	})
+	if err != nil {
 		return r.manageError(instance, err)
 	}
-
-	// Check if this ServiceAccount already exists
-	foundServiceAccount := &corev1.ServiceAccount{}
-	err = r.client.Get(context.TODO(), types.NamespacedName{Name: serviceAccount.Name, Namespace: serviceAccount.Namespace}, foundServiceAccount)
-	if err != nil && errors.IsNotFound(err) {
-		reqLogger.Info("Creating a new ServiceAccount", "ServiceAccount.Namespace", serviceAccount.Namespace, "ServiceAccount.Name", serviceAccount.Name)
-		err = r.client.Create(context.TODO(), serviceAccount)
-		if err != nil {
-			return r.manageError(instance, err)
-		}
-	} else if err != nil {
-		return r.manageError(instance, err)
+	if op != controllerutil.OperationResultNone {
+		reqLogger.Info("ServiceAccount reconciled", "ServiceAccount.Namespace", serviceAccount.Namespace, "ServiceAccount.Name", serviceAccount.Name, "Operation", op)
 	}
 	secondaryResources.add("ServiceAccount", serviceAccount.Name)
 	//#endregion
 
-	//#region Postgres Secret
-	if instance.Spec.DatabaseSecret == "" {
-		postgresqlSecret, err := newPostgresqlSecret(instance)
-		if err != nil {
-			return r.manageError(instance, err)
-		}
-
-		// Set UnifiedPushServer instance as the owner and controller
-		if err := controllerutil.SetControllerReference(instance, postgresqlSecret, r.scheme); err != nil {
-			return r.manageError(instance, err)
-		}
-
-		// Check if this Secret already exists
-		foundPostgresqlSecret := &corev1.Secret{}
-		err = r.client.Get(context.TODO(), types.NamespacedName{Name: postgresqlSecret.Name, Namespace: postgresqlSecret.Namespace}, foundPostgresqlSecret)
-		if err != nil && errors.IsNotFound(err) {
-			reqLogger.Info("Creating a new Secret", "Secret.Namespace", postgresqlSecret.Namespace, "Secret.Name", postgresqlSecret.Name)
-			err = r.client.Create(context.TODO(), postgresqlSecret)
-			if err != nil {
-				return r.manageError(instance, err)
-			}
-		} else if err != nil {
-			return r.manageError(instance, err)
-		}
-		secondaryResources.add("Secret", postgresqlSecret.Name)
-	}
-	//#endregion
-
 	//#region OauthProxy Service
-	oauthProxyService, err := newOauthProxyService(instance)
+	oauthProxyService := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%s-unifiedpush-proxy", instance.Name), Namespace: instance.Namespace}}
+	op, err = controllerutil.CreateOrUpdate(context.TODO(), r.client, oauthProxyService, func(ignore runtime.Object) error {
+		reconcileOauthProxyService(oauthProxyService, instance)
+		// Set UnifiedPushServer instance as the owner and controller
+		return controllerutil.SetControllerReference(instance, oauthProxyService, r.scheme)
+	})
 	if err != nil {
 		return r.manageError(instance, err)
 	}
-
-	// Set UnifiedPushServer instance as the owner and controller
-	if err := controllerutil.SetControllerReference(instance, oauthProxyService, r.scheme); err != nil {
-		return r.manageError(instance, err)
-	}
-
-	// Check if this Service already exists
-	foundOauthProxyService := &corev1.Service{}
-	err = r.client.Get(context.TODO(), types.NamespacedName{Name: oauthProxyService.Name, Namespace: oauthProxyService.Namespace}, foundOauthProxyService)
-	if err != nil && errors.IsNotFound(err) {
-		reqLogger.Info("Creating a new Service", "Service.Namespace", oauthProxyService.Namespace, "Service.Name", oauthProxyService.Name)
-		err = r.client.Create(context.TODO(), oauthProxyService)
-		if err != nil {
-			return r.manageError(instance, err)
-		}
-	} else if err != nil {
-		return r.manageError(instance, err)
+	if op != controllerutil.OperationResultNone {
+		reqLogger.Info("Service reconciled", "Service.Namespace", oauthProxyService.Namespace, "Service.Name", oauthProxyService.Name, "Operation", op)
 	}
 	secondaryResources.add("Service", oauthProxyService.Name)
 	//#endregion
 
 	//#region UPS Service
-	unifiedpushService, err := newUnifiedPushServerService(instance)
+	unifiedpushService := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%s-unifiedpush", instance.Name), Namespace: instance.Namespace}}
+	op, err = controllerutil.CreateOrUpdate(context.TODO(), r.client, unifiedpushService, func(ignore runtime.Object) error {
+		reconcileUnifiedPushServerService(unifiedpushService, instance)
+		// Set UnifiedPushServer instance as the owner and controller
+		return controllerutil.SetControllerReference(instance, unifiedpushService, r.scheme)
+	})
 	if err != nil {
 		return r.manageError(instance, err)
 	}
-
-	// Set UnifiedPushServer instance as the owner and controller
-	if err := controllerutil.SetControllerReference(instance, unifiedpushService, r.scheme); err != nil {
-		return r.manageError(instance, err)
-	}
-
-	// Check if this Service already exists
-	foundUnifiedpushService := &corev1.Service{}
-	err = r.client.Get(context.TODO(), types.NamespacedName{Name: unifiedpushService.Name, Namespace: unifiedpushService.Namespace}, foundUnifiedpushService)
-	if err != nil && errors.IsNotFound(err) {
-		reqLogger.Info("Creating a new Service", "Service.Namespace", unifiedpushService.Namespace, "Service.Name", unifiedpushService.Name)
-		err = r.client.Create(context.TODO(), unifiedpushService)
-		if err != nil {
-			return r.manageError(instance, err)
-		}
-	} else if err != nil {
-		return r.manageError(instance, err)
+	if op != controllerutil.OperationResultNone {
+		reqLogger.Info("Service reconciled", "Service.Namespace", unifiedpushService.Namespace, "Service.Name", unifiedpushService.Name, "Operation", op)
 	}
 	secondaryResources.add("Service", unifiedpushService.Name)
+	instance.Status.InternalServiceURL = util.UnifiedPushServerURL(instance)
 	//#endregion
 
-	//#region OauthProxy Route
-	oauthProxyRoute, err := newOauthProxyRoute(instance)
+	routesAvailable, err := r.apiVersionChecker.check(routev1.SchemeGroupVersion.String())
 	if err != nil {
 		return r.manageError(instance, err)
 	}
 
-	// Set UnifiedPushServer instance as the owner and controller
-	if err := controllerutil.SetControllerReference(instance, oauthProxyRoute, r.scheme); err != nil {
-		return r.manageError(instance, err)
+	//#region OauthProxy Route
+	if routesAvailable {
+		oauthProxyRoute := &routev1.Route{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%s-unifiedpush-proxy", instance.Name), Namespace: instance.Namespace}}
+		op, err = controllerutil.CreateOrUpdate(context.TODO(), r.client, oauthProxyRoute, func(ignore runtime.Object) error {
+			reconcileOauthProxyRoute(oauthProxyRoute, instance)
+			// Set UnifiedPushServer instance as the owner and controller
+			return controllerutil.SetControllerReference(instance, oauthProxyRoute, r.scheme)
+		})
+		if err != nil {
+			return r.manageComponentError(instance, pushv1alpha1.ConditionRouteAdmitted, err)
+		}
+		if op != controllerutil.OperationResultNone {
+			reqLogger.Info("Route reconciled", "Route.Namespace", oauthProxyRoute.Namespace, "Route.Name", oauthProxyRoute.Name, "Operation", op)
+		}
+
+		routeReady := isRouteReady(oauthProxyRoute)
+		readyStatus = readyStatus && routeReady
+		if routeReady {
+			instance.Status.SetCondition(pushv1alpha1.ConditionRouteAdmitted, corev1.ConditionTrue, "Admitted", "")
+			instance.Status.AdminConsoleURL = fmt.Sprintf("https://%s", oauthProxyRoute.Spec.Host)
+		} else {
+			instance.Status.SetCondition(pushv1alpha1.ConditionRouteAdmitted, corev1.ConditionFalse, "NotAdmitted", fmt.Sprintf("Waiting for Route %s to be admitted", oauthProxyRoute.Name))
+			instance.Status.AdminConsoleURL = ""
+		}
+		secondaryResources.add("Route", oauthProxyRoute.Name)
+		instance.Status.RemoveCondition(pushv1alpha1.ConditionIngressReady)
 	}
+	//#endregion
 
-	// Check if this Route already exists
-	foundOauthProxyRoute := &routev1.Route{}
-	err = r.client.Get(context.TODO(), types.NamespacedName{Name: oauthProxyRoute.Name, Namespace: oauthProxyRoute.Namespace}, foundOauthProxyRoute)
-	if err != nil && errors.IsNotFound(err) {
-		reqLogger.Info("Creating a new Route", "Route.Namespace", oauthProxyRoute.Namespace, "Route.Name", oauthProxyRoute.Name)
-		err = r.client.Create(context.TODO(), oauthProxyRoute)
+	//#region OauthProxy Ingress
+	if !routesAvailable {
+		oauthProxyIngress := &extensionsv1beta1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%s-unifiedpush-proxy", instance.Name), Namespace: instance.Namespace}}
+		op, err = controllerutil.CreateOrUpdate(context.TODO(), r.client, oauthProxyIngress, func(ignore runtime.Object) error {
+			reconcileOauthProxyIngress(oauthProxyIngress, instance)
+			// Set UnifiedPushServer instance as the owner and controller
+			return controllerutil.SetControllerReference(instance, oauthProxyIngress, r.scheme)
+		})
 		if err != nil {
-			return r.manageError(instance, err)
+			return r.manageComponentError(instance, pushv1alpha1.ConditionIngressReady, err)
+		}
+		if op != controllerutil.OperationResultNone {
+			reqLogger.Info("Ingress reconciled", "Ingress.Namespace", oauthProxyIngress.Namespace, "Ingress.Name", oauthProxyIngress.Name, "Operation", op)
 		}
-	} else if err != nil {
-		return r.manageError(instance, err)
-	}
 
-	readyStatus = readyStatus && isRouteReady(foundOauthProxyRoute)
-	secondaryResources.add("Route", oauthProxyRoute.Name)
+		ingressReady := isIngressReady(oauthProxyIngress)
+		readyStatus = readyStatus && ingressReady
+		if ingressReady {
+			instance.Status.SetCondition(pushv1alpha1.ConditionIngressReady, corev1.ConditionTrue, "AddressAssigned", "")
+			instance.Status.AdminConsoleURL = util.IngressURL(oauthProxyIngress)
+		} else {
+			instance.Status.SetCondition(pushv1alpha1.ConditionIngressReady, corev1.ConditionFalse, "NoAddress", fmt.Sprintf("Waiting for Ingress %s to be given an address", oauthProxyIngress.Name))
+			instance.Status.AdminConsoleURL = ""
+		}
+		secondaryResources.add("Ingress", oauthProxyIngress.Name)
+		instance.Status.RemoveCondition(pushv1alpha1.ConditionRouteAdmitted)
+	}
 	//#endregion
 
 	//#region UPS Deployment
-	unifiedpushDeployment, err := newUnifiedPushServerDeployment(instance)
-
-	if err := controllerutil.SetControllerReference(instance, unifiedpushDeployment, r.scheme); err != nil {
+	foundUnifiedpushDeployment := &appsv1.Deployment{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace}, foundUnifiedpushDeployment)
+	if err != nil && !errors.IsNotFound(err) {
 		return r.manageError(instance, err)
 	}
-
-	// Check if this Deployment already exists
-	foundUnifiedpushDeployment := &appsv1.Deployment{}
-	err = r.client.Get(context.TODO(), types.NamespacedName{Name: unifiedpushDeployment.Name, Namespace: unifiedpushDeployment.Namespace}, foundUnifiedpushDeployment)
-	if err != nil && errors.IsNotFound(err) {
-		reqLogger.Info("Creating a new Deployment", "Deployment.Namespace", unifiedpushDeployment.Namespace, "Deployment.Name", unifiedpushDeployment.Name)
-		err = r.client.Create(context.TODO(), unifiedpushDeployment)
-		if err != nil {
-			return r.manageError(instance, err)
+	deploymentNotFound := errors.IsNotFound(err)
+
+	//#region OauthProxy Secret
+	// Deployments created by older versions of the operator pass the
+	// cookie secret as an argument, which is kept so that nobody is
+	// logged out by the move to the Secret
+	previousCookieSecret := findArg(findContainerSpec(foundUnifiedpushDeployment, cfg.OauthProxyContainerName), "--cookie-secret=")
+	oauthProxySecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: oauthProxySecretName(instance), Namespace: instance.Namespace}}
+	op, err = controllerutil.CreateOrUpdate(context.TODO(), r.client, oauthProxySecret, func(ignore runtime.Object) error {
+		if err := reconcileOauthProxySecret(oauthProxySecret, instance, previousCookieSecret); err != nil {
+			return err
 		}
-	}
+		// Set UnifiedPushServer instance as the owner and controller
+		return controllerutil.SetControllerReference(instance, oauthProxySecret, r.scheme)
+	})
 	if err != nil {
 		return r.manageError(instance, err)
 	}
+	if op != controllerutil.OperationResultNone {
+		reqLogger.Info("Secret reconciled", "Secret.Namespace", oauthProxySecret.Namespace, "Secret.Name", oauthProxySecret.Name, "Operation", op)
+	}
+	secondaryResources.add("Secret", oauthProxySecret.Name)
+	oauthProxySecretHash := secretHash(oauthProxySecret)
+	//#endregion
 
-	unifiedPushResourceRequirements := getUnifiedPushResourceRequirements(instance)
-	oauthProxyResourceRequirements := getOauthProxyResourceRequirements(instance)
-
-	containers := foundUnifiedpushDeployment.Spec.Template.Spec.Containers
-	for i := range containers {
-		if containers[i].Name == cfg.UPSContainerName {
-			if reflect.DeepEqual(containers[i].Resources, unifiedPushResourceRequirements) == false {
-				reqLogger.Info("UnifiedPush container resource requirements are different than in the UnifiedPushServer spec or the operator defaults", "Deployment.Namespace", foundUnifiedpushDeployment.Namespace, "Deployment.Name", foundUnifiedpushDeployment.Name, "Found resource requirements", containers[i].Resources, "Spec resource requirements", unifiedPushResourceRequirements)
+	if deploymentNotFound {
+		unifiedpushDeployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: instance.Name, Namespace: instance.Namespace}}
+		reconcileUnifiedPushServerDeployment(unifiedpushDeployment, instance, oauthProxySecretHash)
+		if err := r.setSecretsHash(instance.Namespace, &unifiedpushDeployment.Spec.Template); err != nil {
+			return r.manageError(instance, err)
+		}
 
-				containers[i].Resources = unifiedPushResourceRequirements
+		// Set UnifiedPushServer instance as the owner and controller
+		if err := controllerutil.SetControllerReference(instance, unifiedpushDeployment, r.scheme); err != nil {
+			return r.manageError(instance, err)
+		}
 
-				// enqueue
-				err = r.client.Update(context.TODO(), foundUnifiedpushDeployment)
-				if err != nil {
-					reqLogger.Error(err, "Failed to update Deployment", "Deployment.Namespace", foundUnifiedpushDeployment.Namespace, "Deployment.Name", foundUnifiedpushDeployment.Name)
-					return r.manageError(instance, err)
-				}
-				return reconcile.Result{Requeue: true}, nil
-			}
-		} else if containers[i].Name == cfg.OauthProxyContainerName {
-			if reflect.DeepEqual(containers[i].Resources, oauthProxyResourceRequirements) == false {
-				reqLogger.Info("OauthProxy container resource requirements are different than in the UnifiedPushServer spec or the operator defaults", "Deployment.Namespace", foundUnifiedpushDeployment.Namespace, "Deployment.Name", foundUnifiedpushDeployment.Name, "Found resource requirements", containers[i].Resources, "Spec resource requirements", oauthProxyResourceRequirements)
+		reqLogger.Info("Creating a new Deployment", "Deployment.Namespace", unifiedpushDeployment.Namespace, "Deployment.Name", unifiedpushDeployment.Name)
+		err = r.client.Create(context.TODO(), unifiedpushDeployment)
+		if err != nil {
+			return r.manageError(instance, err)
+		}
+		foundUnifiedpushDeployment = unifiedpushDeployment
+	} else {
+		unifiedpushDeployment := foundUnifiedpushDeployment.DeepCopy()
+		reconcileUnifiedPushServerDeployment(unifiedpushDeployment, instance, oauthProxySecretHash)
+		if err := r.setSecretsHash(instance.Namespace, &unifiedpushDeployment.Spec.Template); err != nil {
+			return r.manageError(instance, err)
+		}
 
-				containers[i].Resources = oauthProxyResourceRequirements
+		// Set UnifiedPushServer instance as the owner and controller
+		if err := controllerutil.SetControllerReference(instance, unifiedpushDeployment, r.scheme); err != nil {
+			return r.manageError(instance, err)
+		}
 
-				// enqueue
-				err = r.client.Update(context.TODO(), foundUnifiedpushDeployment)
-				if err != nil {
-					reqLogger.Error(err, "Failed to update Deployment", "Deployment.Namespace", foundUnifiedpushDeployment.Namespace, "Deployment.Name", foundUnifiedpushDeployment.Name)
-					return r.manageError(instance, err)
-				}
-				return reconcile.Result{Requeue: true}, nil
+		// Only update the Deployment when the pod template, resources,
+		// affinity or images have changed, as any update is a write to
+		// the API server and may roll out the pods
+		if semantic.DeepEqual(foundUnifiedpushDeployment, unifiedpushDeployment) {
+			skippedDeploymentUpdates.WithLabelValues(instance.Namespace, instance.Name).Inc()
+		} else {
+			reqLogger.Info("Deployment is different than in the UnifiedPushServer spec or the operator defaults. Going to update it now.", "Deployment.Namespace", unifiedpushDeployment.Namespace, "Deployment.Name", unifiedpushDeployment.Name)
+			err = r.client.Update(context.TODO(), unifiedpushDeployment)
+			if err != nil {
+				reqLogger.Error(err, "Failed to update Deployment", "Deployment.Namespace", unifiedpushDeployment.Namespace, "Deployment.Name", unifiedpushDeployment.Name)
+				return r.manageError(instance, err)
 			}
+			foundUnifiedpushDeployment = unifiedpushDeployment
 		}
 	}
 
-	desiredUnifiedPushImage := constants.UPSImage
-	desiredProxyImage := constants.OauthProxyImage
+	if unifiedPushContainerSpec := findContainerSpec(foundUnifiedpushDeployment, cfg.UPSContainerName); unifiedPushContainerSpec != nil {
+		instance.Status.Images.UnifiedPush = unifiedPushContainerSpec.Image
+	}
+	if proxyContainerSpec := findContainerSpec(foundUnifiedpushDeployment, cfg.OauthProxyContainerName); proxyContainerSpec != nil {
+		instance.Status.Images.OAuthProxy = proxyContainerSpec.Image
+	}
 
-	unifiedPushContainerSpec := findContainerSpec(foundUnifiedpushDeployment, cfg.UPSContainerName)
-	if unifiedPushContainerSpec == nil {
-		reqLogger.Info("Unable to do image reconcile: Unable to find container spec in deployment", "Deployment.Namespace", foundUnifiedpushDeployment.Namespace, "Deployment.Name", foundUnifiedpushDeployment.Name, "ContainerSpec", cfg.UPSContainerName)
-		return reconcile.Result{Requeue: true}, nil
-	} else if unifiedPushContainerSpec.Image != desiredUnifiedPushImage {
-		reqLogger.Info("Container spec in deployment is using a different image. Going to update it now.", "Deployment.Namespace", foundUnifiedpushDeployment.Namespace, "Deployment.Name", foundUnifiedpushDeployment.Name, "ContainerSpec", cfg.UPSContainerName, "ExistingImage", unifiedPushContainerSpec.Image, "DesiredImage", desiredUnifiedPushImage)
+	// Set ready status
+	deploymentReady, err := hasMinimumAvailableReplicas(foundUnifiedpushDeployment, unifiedPushMinAvailable(instance))
+	if err != nil {
+		return r.manageError(instance, err)
+	}
+	readyStatus = readyStatus && deploymentReady
 
-		// update
-		updateContainerSpecImage(foundUnifiedpushDeployment, cfg.UPSContainerName, desiredUnifiedPushImage)
+	secondaryResources.add("Deployment", foundUnifiedpushDeployment.Name)
+	//#endregion
 
-		// enqueue
-		err = r.client.Update(context.TODO(), foundUnifiedpushDeployment)
+	//#region UPS HorizontalPodAutoscaler
+	unifiedpushAutoscaler := &autoscalingv2beta1.HorizontalPodAutoscaler{ObjectMeta: metav1.ObjectMeta{Name: instance.Name, Namespace: instance.Namespace}}
+	if instance.Spec.Autoscaling != nil {
+		op, err = controllerutil.CreateOrUpdate(context.TODO(), r.client, unifiedpushAutoscaler, func(ignore runtime.Object) error {
+			reconcileUnifiedPushHorizontalPodAutoscaler(unifiedpushAutoscaler, instance)
+			// Set UnifiedPushServer instance as the owner and controller
+			return controllerutil.SetControllerReference(instance, unifiedpushAutoscaler, r.scheme)
+		})
 		if err != nil {
-			reqLogger.Error(err, "Failed to update Deployment", "Deployment.Namespace", foundUnifiedpushDeployment.Namespace, "Deployment.Name", foundUnifiedpushDeployment.Name)
 			return r.manageError(instance, err)
 		}
-		return reconcile.Result{Requeue: true}, nil
+		if op != controllerutil.OperationResultNone {
+			reqLogger.Info("HorizontalPodAutoscaler reconciled", "HorizontalPodAutoscaler.Namespace", unifiedpushAutoscaler.Namespace, "HorizontalPodAutoscaler.Name", unifiedpushAutoscaler.Name, "Operation", op)
+		}
+		secondaryResources.add("HorizontalPodAutoscaler", unifiedpushAutoscaler.Name)
+	} else {
+		err = r.client.Get(context.TODO(), types.NamespacedName{Name: unifiedpushAutoscaler.Name, Namespace: unifiedpushAutoscaler.Namespace}, unifiedpushAutoscaler)
+		if err != nil && !errors.IsNotFound(err) {
+			return r.manageError(instance, err)
+		}
+		if err == nil {
+			// Autoscaling has been turned off, so the Deployment is
+			// scaled to spec.replicas again
+			reqLogger.Info("Deleting HorizontalPodAutoscaler", "HorizontalPodAutoscaler.Namespace", unifiedpushAutoscaler.Namespace, "HorizontalPodAutoscaler.Name", unifiedpushAutoscaler.Name)
+			err = r.client.Delete(context.TODO(), unifiedpushAutoscaler)
+			if err != nil && !errors.IsNotFound(err) {
+				return r.manageError(instance, err)
+			}
+		}
 	}
+	//#endregion
 
-	proxyContainerSpec := findContainerSpec(foundUnifiedpushDeployment, cfg.OauthProxyContainerName)
-	if proxyContainerSpec == nil {
-		reqLogger.Info("Unable to do image reconcile: Unable to find container spec in deployment", "Deployment.Namespace", foundUnifiedpushDeployment.Namespace, "Deployment.Name", foundUnifiedpushDeployment.Name, "ContainerSpec", cfg.OauthProxyContainerName)
-		return reconcile.Result{Requeue: true}, nil
-	} else if proxyContainerSpec.Image != desiredProxyImage {
-		reqLogger.Info("Container spec in deployment is using a different image. Going to update it now.", "Deployment.Namespace", foundUnifiedpushDeployment.Namespace, "Deployment.Name", foundUnifiedpushDeployment.Name, "ContainerSpec", cfg.OauthProxyContainerName, "ExistingImage", proxyContainerSpec.Image, "DesiredImage", desiredProxyImage)
+	//#region UPS PodDisruptionBudget
+	foundPodDisruptionBudget := &policyv1beta1.PodDisruptionBudget{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace}, foundPodDisruptionBudget)
+	if err != nil && !errors.IsNotFound(err) {
+		return r.manageError(instance, err)
+	}
+	podDisruptionBudgetFound := err == nil
 
-		// update
-		updateContainerSpecImage(foundUnifiedpushDeployment, cfg.OauthProxyContainerName, desiredProxyImage)
+	if unifiedPushReplicas(instance) > 1 {
+		podDisruptionBudget := newUnifiedPushPodDisruptionBudget(instance)
 
-		// enqueue
-		err = r.client.Update(context.TODO(), foundUnifiedpushDeployment)
-		if err != nil {
-			reqLogger.Error(err, "Failed to update Deployment", "Deployment.Namespace", foundUnifiedpushDeployment.Namespace, "Deployment.Name", foundUnifiedpushDeployment.Name)
+		// Set UnifiedPushServer instance as the owner and controller
+		if err := controllerutil.SetControllerReference(instance, podDisruptionBudget, r.scheme); err != nil {
 			return r.manageError(instance, err)
 		}
-		return reconcile.Result{Requeue: true}, nil
-	}
-
-	podSpec := findPodSpec(foundUnifiedpushDeployment)
-	if podSpec == nil {
-		reqLogger.Info("Unable to do image reconcile: Unable to find pod spec in deployment", "Deployment.Namespace", foundUnifiedpushDeployment.Namespace, "Deployment.Name", foundUnifiedpushDeployment.Name)
-		return reconcile.Result{Requeue: true}, nil
-	}
-	podSpec.Affinity = instance.Spec.Affinity
-	podSpec.Tolerations = instance.Spec.Tolerations
 
-	// update
-	err = r.client.Update(context.TODO(), foundUnifiedpushDeployment)
-	if err != nil {
-		reqLogger.Error(err, "Failed to update Deployment", "Deployment.Namespace", foundUnifiedpushDeployment.Namespace, "Deployment.Name", foundUnifiedpushDeployment.Name)
-		return r.manageError(instance, err)
-	}
+		// The spec of a PodDisruptionBudget can't be updated before
+		// Kubernetes 1.15, so it's replaced instead
+		if podDisruptionBudgetFound && !semantic.DeepEqual(foundPodDisruptionBudget.Spec, podDisruptionBudget.Spec) {
+			reqLogger.Info("PodDisruptionBudget is different than in the UnifiedPushServer spec. Going to replace it now.", "PodDisruptionBudget.Namespace", podDisruptionBudget.Namespace, "PodDisruptionBudget.Name", podDisruptionBudget.Name)
+			err = r.client.Delete(context.TODO(), foundPodDisruptionBudget)
+			if err != nil && !errors.IsNotFound(err) {
+				return r.manageError(instance, err)
+			}
+			podDisruptionBudgetFound = false
+		}
 
-	// Set ready status
-	deploymentReady, err := isDeploymentReady(foundUnifiedpushDeployment)
-	if err != nil {
-		return r.manageError(instance, err)
+		if !podDisruptionBudgetFound {
+			reqLogger.Info("Creating a new PodDisruptionBudget", "PodDisruptionBudget.Namespace", podDisruptionBudget.Namespace, "PodDisruptionBudget.Name", podDisruptionBudget.Name)
+			err = r.client.Create(context.TODO(), podDisruptionBudget)
+			if err != nil {
+				return r.manageError(instance, err)
+			}
+		}
+		secondaryResources.add("PodDisruptionBudget", podDisruptionBudget.Name)
+	} else if podDisruptionBudgetFound {
+		// A single replica can't be kept available through disruptions,
+		// and a budget for it would block node drains
+		reqLogger.Info("Deleting PodDisruptionBudget", "PodDisruptionBudget.Namespace", foundPodDisruptionBudget.Namespace, "PodDisruptionBudget.Name", foundPodDisruptionBudget.Name)
+		err = r.client.Delete(context.TODO(), foundPodDisruptionBudget)
+		if err != nil && !errors.IsNotFound(err) {
+			return r.manageError(instance, err)
+		}
 	}
-	readyStatus = readyStatus && deploymentReady
-
-	secondaryResources.add("Deployment", unifiedpushDeployment.Name)
 	//#endregion
 
 	//#region Backups
+	backupjobSAName := ""
 	if len(instance.Spec.Backups) > 0 {
 		backupjobSA := &corev1.ServiceAccount{}
-		err = r.client.Get(context.TODO(), types.NamespacedName{Name: "backupjob", Namespace: instance.Namespace}, backupjobSA)
+		err = r.client.Get(context.TODO(), types.NamespacedName{Name: backupServiceAccountName(instance), Namespace: instance.Namespace}, backupjobSA)
+		if err != nil && errors.IsNotFound(err) {
+			// Fall back to the ServiceAccount that was shared by all the
+			// UnifiedPushServers in the namespace
+			err = r.client.Get(context.TODO(), types.NamespacedName{Name: legacyBackupServiceAccountName, Namespace: instance.Namespace}, backupjobSA)
+		}
 		if err != nil {
-			reqLogger.Error(err, "A 'backupjob' ServiceAccount is required for the requested backup CronJob(s). Will check again in 10 seconds")
-			return reconcile.Result{RequeueAfter: time.Second * 10}, nil
+			msg := fmt.Sprintf("A '%s' ServiceAccount is required for the requested backup CronJob(s)", backupServiceAccountName(instance))
+			reqLogger.Error(err, msg+". Will check again in 10 seconds")
+			return r.manageWaiting(instance, pushv1alpha1.ConditionBackupsConfigured, "ServiceAccountMissing", msg, time.Second*10)
 		}
+		backupjobSAName = backupjobSA.Name
 	}
 
 	existingCronJobs := &batchv1beta1.CronJobList{}
 	opts := client.InNamespace(instance.Namespace).MatchingLabels(labels(instance, "backup"))
 	err = r.client.List(context.TODO(), opts, existingCronJobs)
 	if err != nil {
-		return r.manageError(instance, err)
+		return r.manageComponentError(instance, pushv1alpha1.ConditionBackupsConfigured, err)
 	}
 
-	desiredCronJobs, err := backups(instance)
+	desiredCronJobs, err := backups(instance, backupjobSAName)
 	if err != nil {
-		return r.manageError(instance, err)
+		return r.manageComponentError(instance, pushv1alpha1.ConditionBackupsConfigured, err)
 	}
 
 	for _, desiredCronJob := range desiredCronJobs {
 		if err := controllerutil.SetControllerReference(instance, &desiredCronJob, r.scheme); err != nil {
-			return r.manageError(instance, err)
+			return r.manageComponentError(instance, pushv1alpha1.ConditionBackupsConfigured, err)
 		}
 
 		if exists := containsCronJob(existingCronJobs.Items, &desiredCronJob); exists {
 			err = r.client.Update(context.TODO(), &desiredCronJob)
 			if err != nil {
-				return r.manageError(instance, err)
+				return r.manageComponentError(instance, pushv1alpha1.ConditionBackupsConfigured, err)
 			}
 		} else {
 			reqLogger.Info("Creating a new CronJob", "CronJob.Namespace", desiredCronJob.Namespace, "CronJob.Name", desiredCronJob.Name)
 			err = r.client.Create(context.TODO(), &desiredCronJob)
 			if err != nil {
-				return r.manageError(instance, err)
+				return r.manageComponentError(instance, pushv1alpha1.ConditionBackupsConfigured, err)
 			}
 		}
 		secondaryResources.add("CronJob", desiredCronJob.Name)
@@ -867,18 +1067,26 @@ func (r *ReconcileUnifiedPushServer) Reconcile(request reconcile.Request) (recon
 			reqLogger.Info("Deleting backup CronJob since it was removed from CR", "CronJob.Namespace", existingCronJob.Namespace, "CronJob.Name", existingCronJob.Name)
 			err = r.client.Delete(context.TODO(), &existingCronJob)
 			if err != nil {
-				return r.manageError(instance, err)
+				return r.manageComponentError(instance, pushv1alpha1.ConditionBackupsConfigured, err)
 			}
 			secondaryResources.remove("CronJob", existingCronJob.Name)
 		}
 	}
+
+	if len(desiredCronJobs) > 0 {
+		instance.Status.Images.Backup = backupImage(instance)
+		instance.Status.SetCondition(pushv1alpha1.ConditionBackupsConfigured, corev1.ConditionTrue, "CronJobsReconciled", fmt.Sprintf("%d backup CronJob(s) configured", len(desiredCronJobs)))
+	} else {
+		instance.Status.Images.Backup = ""
+		instance.Status.RemoveCondition(pushv1alpha1.ConditionBackupsConfigured)
+	}
 	//#endregion
 
 	//#region Monitoring
 	//## region ServiceMonitor
-	serviceMonitor := &monitoringv1.ServiceMonitor{ObjectMeta: metav1.ObjectMeta{Name: "unifiedpush", Namespace: instance.Namespace}}
-	op, err := controllerutil.CreateOrUpdate(context.TODO(), r.client, serviceMonitor, func(ignore runtime.Object) error {
-		reconcileServiceMonitor(serviceMonitor)
+	serviceMonitor := &monitoringv1.ServiceMonitor{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%s-unifiedpush", instance.Name), Namespace: instance.Namespace}}
+	op, err = controllerutil.CreateOrUpdate(context.TODO(), r.client, serviceMonitor, func(ignore runtime.Object) error {
+		reconcileServiceMonitor(serviceMonitor, instance)
 		// Set UnifiedPushServer instance as the owner and controller
 		err := controllerutil.SetControllerReference(instance, serviceMonitor, r.scheme)
 		return err
@@ -892,7 +1100,7 @@ func (r *ReconcileUnifiedPushServer) Reconcile(request reconcile.Request) (recon
 	//## endregion ServiceMonitor
 
 	//## region PrometheusRule
-	prometheusRule := &monitoringv1.PrometheusRule{ObjectMeta: metav1.ObjectMeta{Name: "unifiedpush", Namespace: instance.Namespace}}
+	prometheusRule := &monitoringv1.PrometheusRule{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%s-unifiedpush", instance.Name), Namespace: instance.Namespace}}
 	op, err = controllerutil.CreateOrUpdate(context.TODO(), r.client, prometheusRule, func(ignore runtime.Object) error {
 		reconcilePrometheusRule(prometheusRule, instance)
 		// Set UnifiedPushServer instance as the owner and controller
@@ -908,7 +1116,7 @@ func (r *ReconcileUnifiedPushServer) Reconcile(request reconcile.Request) (recon
 	//## endregion PrometheusRule
 
 	//## region GrafanaDasboard
-	grafanaDashboard := &integreatlyv1alpha1.GrafanaDashboard{ObjectMeta: metav1.ObjectMeta{Name: "unifiedpushserver-dashboard", Namespace: instance.Namespace}}
+	grafanaDashboard := &integreatlyv1alpha1.GrafanaDashboard{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%s-dashboard", instance.Name), Namespace: instance.Namespace}}
 	op, err = controllerutil.CreateOrUpdate(context.TODO(), r.client, grafanaDashboard, func(ignore runtime.Object) error {
 		reconcileGrafanaDashboard(grafanaDashboard, instance)
 		// Set UnifiedPushServer instance as the owner and controller
@@ -922,11 +1130,23 @@ func (r *ReconcileUnifiedPushServer) Reconcile(request reconcile.Request) (recon
 		reqLogger.Info("GrafanaDashboard reconciled:", "GrafanaDashboard.Name", grafanaDashboard.Name, "GrafanaDashboard.Namespace", grafanaDashboard.Namespace, "Operation", op)
 	}
 	//## endregion GrafanaDasboard
+
+	err = r.deleteLegacyMonitoringObjects(instance, serviceMonitor.Name, prometheusRule.Name, grafanaDashboard.Name)
+	if err != nil {
+		return r.manageError(instance, err)
+	}
 	//#endregion
 
 	return r.manageSuccess(instance, secondaryResources, readyStatus)
 }
 
+// manageComponentError marks the component described by the given
+// condition as failing, on top of manageError
+func (r *ReconcileUnifiedPushServer) manageComponentError(instance *pushv1alpha1.UnifiedPushServer, conditionType pushv1alpha1.UnifiedPushServerConditionType, issue error) (reconcile.Result, error) {
+	instance.Status.SetCondition(conditionType, corev1.ConditionFalse, "ReconcileFailed", issue.Error())
+	return r.manageError(instance, issue)
+}
+
 func (r *ReconcileUnifiedPushServer) manageError(instance *pushv1alpha1.UnifiedPushServer, issue error) (reconcile.Result, error) {
 	r.recorder.Event(instance, "Warning", "ReconcileFailed", issue.Error())
 
@@ -934,6 +1154,8 @@ func (r *ReconcileUnifiedPushServer) manageError(instance *pushv1alpha1.UnifiedP
 	instance.Status.Ready = &ready
 	instance.Status.Message = issue.Error()
 	instance.Status.Phase = pushv1alpha1.PhaseFailing
+	instance.Status.SetCondition(pushv1alpha1.ConditionAvailable, corev1.ConditionFalse, "ReconcileFailed", issue.Error())
+	instance.Status.SetCondition(pushv1alpha1.ConditionDegraded, corev1.ConditionTrue, "ReconcileFailed", issue.Error())
 
 	err := r.client.Status().Update(context.TODO(), instance)
 	if err != nil {
@@ -946,16 +1168,43 @@ func (r *ReconcileUnifiedPushServer) manageError(instance *pushv1alpha1.UnifiedP
 	}, nil
 }
 
+// manageWaiting marks the component described by the given condition
+// as not ready yet, and requeues after the given delay
+func (r *ReconcileUnifiedPushServer) manageWaiting(instance *pushv1alpha1.UnifiedPushServer, conditionType pushv1alpha1.UnifiedPushServerConditionType, reason string, message string, requeueAfter time.Duration) (reconcile.Result, error) {
+	ready := false
+	instance.Status.Ready = &ready
+	instance.Status.Message = message
+	instance.Status.Phase = pushv1alpha1.PhaseInitializing
+	instance.Status.SetCondition(conditionType, corev1.ConditionFalse, reason, message)
+	instance.Status.SetCondition(pushv1alpha1.ConditionAvailable, corev1.ConditionFalse, "ComponentsNotReady", message)
+	instance.Status.SetCondition(pushv1alpha1.ConditionProgressing, corev1.ConditionTrue, reason, message)
+	instance.Status.SetCondition(pushv1alpha1.ConditionDegraded, corev1.ConditionFalse, "ReconcileSucceeded", "")
+
+	err := r.client.Status().Update(context.TODO(), instance)
+	if err != nil {
+		log.Error(err, "Unable to update status")
+	}
+
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
+}
+
 func (r *ReconcileUnifiedPushServer) manageSuccess(instance *pushv1alpha1.UnifiedPushServer, secondaryResources resources, readyStatus bool) (reconcile.Result, error) {
 	instance.Status.Ready = &readyStatus
 	instance.Status.Message = ""
 	instance.Status.SecondaryResources = secondaryResources
+	instance.Status.SetCondition(pushv1alpha1.ConditionDegraded, corev1.ConditionFalse, "ReconcileSucceeded", "")
 
 	// If resources are ready and we have not errored before now, we are in a reconciling phase
 	if readyStatus {
+		// Only once the pods have rolled out is the spec applied
+		instance.Status.ObservedGeneration = instance.Generation
 		instance.Status.Phase = pushv1alpha1.PhaseReconciling
+		instance.Status.SetCondition(pushv1alpha1.ConditionAvailable, corev1.ConditionTrue, "AllComponentsReady", "")
+		instance.Status.SetCondition(pushv1alpha1.ConditionProgressing, corev1.ConditionFalse, "ReconcileComplete", "")
 	} else {
 		instance.Status.Phase = pushv1alpha1.PhaseInitializing
+		instance.Status.SetCondition(pushv1alpha1.ConditionAvailable, corev1.ConditionFalse, "ComponentsNotReady", "Waiting for all of the components to be ready")
+		instance.Status.SetCondition(pushv1alpha1.ConditionProgressing, corev1.ConditionTrue, "WaitingForComponents", "Waiting for all of the components to be ready")
 	}
 
 	err := r.client.Status().Update(context.TODO(), instance)
@@ -4,14 +4,24 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	pushv1alpha1 "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1"
 
+	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
+	integreatlyv1alpha1 "github.com/integr8ly/grafana-operator/pkg/apis/integreatly/v1alpha1"
 	routev1 "github.com/openshift/api/route/v1"
+	dto "github.com/prometheus/client_model/go"
 
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta1 "k8s.io/api/autoscaling/v2beta1"
+	batchv1 "k8s.io/api/batch/v1"
 	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -115,6 +125,965 @@ func TestReconcileUnifiedPushServer_Reconcile(t *testing.T) {
 	}
 }
 
+func TestReconcileUnifiedPushServer_DriftCorrection(t *testing.T) {
+	cr := crWithDefaults.DeepCopy()
+	namespacedName := func(suffix string) types.NamespacedName {
+		return types.NamespacedName{Name: cr.Name + suffix, Namespace: cr.Namespace}
+	}
+
+	scenarios := []struct {
+		name   string
+		object runtime.Object
+		key    types.NamespacedName
+		mutate func(runtime.Object)
+		verify func(*testing.T, runtime.Object)
+	}{
+		{
+			name:   "OauthProxy Service",
+			object: &corev1.Service{},
+			key:    namespacedName("-unifiedpush-proxy"),
+			mutate: func(o runtime.Object) {
+				service := o.(*corev1.Service)
+				service.Labels["team"] = "push"
+				service.Spec.ClusterIP = "172.30.0.10"
+				service.Spec.Selector = map[string]string{"app": "something-else"}
+				service.Spec.Ports[0].TargetPort = intstr.FromInt(8080)
+			},
+			verify: func(t *testing.T, o runtime.Object) {
+				service := o.(*corev1.Service)
+				if service.Spec.Selector["service"] != "ups" || service.Spec.Ports[0].TargetPort.IntValue() != 4180 {
+					t.Errorf("expected selector and ports to be restored, got %+v", service.Spec)
+				}
+				if service.Spec.ClusterIP != "172.30.0.10" || service.Labels["team"] != "push" {
+					t.Errorf("expected clusterIP and extra labels to be kept, got %+v", service)
+				}
+			},
+		},
+		{
+			name:   "UPS Service",
+			object: &corev1.Service{},
+			key:    namespacedName("-unifiedpush"),
+			mutate: func(o runtime.Object) {
+				service := o.(*corev1.Service)
+				delete(service.Annotations, "org.aerogear.metrics/plain_endpoint")
+				delete(service.Labels, "mobile")
+				service.Spec.Ports = nil
+			},
+			verify: func(t *testing.T, o runtime.Object) {
+				service := o.(*corev1.Service)
+				if service.Annotations["org.aerogear.metrics/plain_endpoint"] == "" || service.Labels["mobile"] != "enabled" {
+					t.Errorf("expected annotations and labels to be restored, got %+v", service.ObjectMeta)
+				}
+				if len(service.Spec.Ports) != 1 || service.Spec.Ports[0].TargetPort.IntValue() != 8080 {
+					t.Errorf("expected ports to be restored, got %+v", service.Spec.Ports)
+				}
+			},
+		},
+		{
+			name:   "Postgres Service",
+			object: &corev1.Service{},
+			key:    namespacedName("-postgresql"),
+			mutate: func(o runtime.Object) {
+				o.(*corev1.Service).Spec.Ports[0].Port = 5433
+			},
+			verify: func(t *testing.T, o runtime.Object) {
+				if port := o.(*corev1.Service).Spec.Ports[0].Port; port != 5432 {
+					t.Errorf("expected port 5432, got %d", port)
+				}
+			},
+		},
+		{
+			name:   "OauthProxy Route",
+			object: &routev1.Route{},
+			key:    namespacedName("-unifiedpush-proxy"),
+			mutate: func(o runtime.Object) {
+				route := o.(*routev1.Route)
+				route.Spec.Host = "push.example.com"
+				route.Spec.To.Name = "something-else"
+				route.Spec.TLS = nil
+			},
+			verify: func(t *testing.T, o runtime.Object) {
+				route := o.(*routev1.Route)
+				if route.Spec.To.Name != cr.Name+"-unifiedpush-proxy" || route.Spec.TLS == nil || route.Spec.TLS.Termination != routev1.TLSTerminationEdge {
+					t.Errorf("expected target and TLS to be restored, got %+v", route.Spec)
+				}
+				if route.Spec.Host != "push.example.com" {
+					t.Errorf("expected host to be kept, got %s", route.Spec.Host)
+				}
+			},
+		},
+		{
+			name:   "ServiceAccount",
+			object: &corev1.ServiceAccount{},
+			key:    namespacedName(""),
+			mutate: func(o runtime.Object) {
+				serviceAccount := o.(*corev1.ServiceAccount)
+				serviceAccount.Annotations = nil
+				serviceAccount.Secrets = []corev1.ObjectReference{{Name: "token"}}
+			},
+			verify: func(t *testing.T, o runtime.Object) {
+				serviceAccount := o.(*corev1.ServiceAccount)
+				if serviceAccount.Annotations["serviceaccounts.openshift.io/oauth-redirectreference.ups"] == "" {
+					t.Errorf("expected OAuth redirect reference to be restored, got %+v", serviceAccount.Annotations)
+				}
+				if len(serviceAccount.Secrets) != 1 {
+					t.Errorf("expected secrets to be kept, got %+v", serviceAccount.Secrets)
+				}
+			},
+		},
+		{
+			name:   "Postgres Secret",
+			object: &corev1.Secret{},
+			key:    namespacedName("-postgresql"),
+			mutate: func(o runtime.Object) {
+				secret := o.(*corev1.Secret)
+				secret.Data["POSTGRES_HOST"] = []byte("elsewhere")
+				delete(secret.Data, "POSTGRES_PORT")
+				secret.Data["POSTGRES_PASSWORD"] = []byte("kept")
+			},
+			verify: func(t *testing.T, o runtime.Object) {
+				secret := o.(*corev1.Secret)
+				if host := string(secret.Data["POSTGRES_HOST"]); host != fmt.Sprintf("%s-postgresql.%s.svc", cr.Name, cr.Namespace) {
+					t.Errorf("expected host to be restored, got %s", host)
+				}
+				if port := string(secret.Data["POSTGRES_PORT"]); port != "5432" {
+					t.Errorf("expected port to be restored, got %s", port)
+				}
+				if password := string(secret.Data["POSTGRES_PASSWORD"]); password != "kept" {
+					t.Errorf("expected password not to be regenerated, got %s", password)
+				}
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			r := buildReconcileWithFakeClientWithMocks([]runtime.Object{cr.DeepCopy()}, t)
+			req := reconcile.Request{NamespacedName: namespacedName("")}
+
+			if _, err := r.Reconcile(req); err != nil {
+				t.Fatalf("reconcile: (%v)", err)
+			}
+
+			if err := r.client.Get(context.TODO(), scenario.key, scenario.object); err != nil {
+				t.Fatalf("get %s: (%v)", scenario.key, err)
+			}
+			scenario.mutate(scenario.object)
+			if err := r.client.Update(context.TODO(), scenario.object); err != nil {
+				t.Fatalf("update %s: (%v)", scenario.key, err)
+			}
+
+			if _, err := r.Reconcile(req); err != nil {
+				t.Fatalf("reconcile: (%v)", err)
+			}
+
+			if err := r.client.Get(context.TODO(), scenario.key, scenario.object); err != nil {
+				t.Fatalf("get %s: (%v)", scenario.key, err)
+			}
+			scenario.verify(t, scenario.object)
+		})
+	}
+}
+
+func TestReconcileUnifiedPushServer_ExternalDatabaseChange(t *testing.T) {
+	cr := crWithExternalDatabase.DeepCopy()
+	r := buildReconcileWithFakeClientWithMocks([]runtime.Object{cr}, t)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace}}
+
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+
+	instance := &pushv1alpha1.UnifiedPushServer{}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, instance); err != nil {
+		t.Fatalf("get UnifiedPushServer: (%v)", err)
+	}
+	instance.Spec.Database.Host = "db.example.com"
+	instance.Spec.Database.Password = "changed"
+	if err := r.client.Update(context.TODO(), instance); err != nil {
+		t.Fatalf("update UnifiedPushServer: (%v)", err)
+	}
+
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: cr.Name + "-postgresql", Namespace: cr.Namespace}, secret); err != nil {
+		t.Fatalf("get Secret: (%v)", err)
+	}
+	if host := string(secret.Data["POSTGRES_HOST"]); host != "db.example.com" {
+		t.Errorf("expected host from the spec, got %s", host)
+	}
+	if password := string(secret.Data["POSTGRES_PASSWORD"]); password != "changed" {
+		t.Errorf("expected password from the spec, got %s", password)
+	}
+}
+
+func TestReconcileUnifiedPushServer_DeploymentUpdates(t *testing.T) {
+	cr := crWithDefaults.DeepCopy()
+	r := buildReconcileWithFakeClientWithMocks([]runtime.Object{cr}, t)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace}}
+
+	skipped := func() float64 {
+		m := &dto.Metric{}
+		if err := skippedDeploymentUpdates.WithLabelValues(cr.Namespace, cr.Name).Write(m); err != nil {
+			t.Fatalf("read metric: (%v)", err)
+		}
+		return m.GetCounter().GetValue()
+	}
+	getDeployment := func() *appsv1.Deployment {
+		deployment := &appsv1.Deployment{}
+		if err := r.client.Get(context.TODO(), req.NamespacedName, deployment); err != nil {
+			t.Fatalf("get Deployment: (%v)", err)
+		}
+		return deployment
+	}
+
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	created := getDeployment()
+	skippedBefore := skipped()
+
+	// Nothing changed, so the Deployment is left alone
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	if skipped() != skippedBefore+1 {
+		t.Errorf("expected the skipped update to be counted, got %v", skipped())
+	}
+
+	// A change to the resources in the spec is applied
+	instance := &pushv1alpha1.UnifiedPushServer{}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, instance); err != nil {
+		t.Fatalf("get UnifiedPushServer: (%v)", err)
+	}
+	instance.Spec.UnifiedPushResourceRequirements.Limits[corev1.ResourceMemory] = resource.MustParse("4Gi")
+	if err := r.client.Update(context.TODO(), instance); err != nil {
+		t.Fatalf("update UnifiedPushServer: (%v)", err)
+	}
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	if skipped() != skippedBefore+1 {
+		t.Errorf("expected the Deployment to be updated, got %v skipped updates", skipped())
+	}
+	updated := getDeployment()
+	memory := findContainerSpec(updated, cfg.UPSContainerName).Resources.Limits[corev1.ResourceMemory]
+	if memory.String() != "4Gi" {
+		t.Errorf("expected memory limit 4Gi, got %s", memory.String())
+	}
+	if created.Spec.Template.Annotations[cookieSecretHashAnnotation] != updated.Spec.Template.Annotations[cookieSecretHashAnnotation] {
+		t.Errorf("expected cookie secret to be kept, got %+v", updated.Spec.Template.Annotations)
+	}
+}
+
+func TestReconcileUnifiedPushServer_CookieSecret(t *testing.T) {
+	cr := crWithDefaults.DeepCopy()
+	existing := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: cr.Name, Namespace: cr.Namespace},
+	}
+	reconcileUnifiedPushServerDeployment(existing, cr, "")
+	existing.Spec.Template.Spec.Containers[1].Args = []string{"--cookie-secret=from-args"}
+
+	r := buildReconcileWithFakeClientWithMocks([]runtime.Object{cr, existing}, t)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace}}
+	getCookieSecret := func() (string, string) {
+		secret := &corev1.Secret{}
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Name: cr.Name + "-oauth-proxy", Namespace: cr.Namespace}, secret); err != nil {
+			t.Fatalf("get Secret: (%v)", err)
+		}
+		deployment := &appsv1.Deployment{}
+		if err := r.client.Get(context.TODO(), req.NamespacedName, deployment); err != nil {
+			t.Fatalf("get Deployment: (%v)", err)
+		}
+		if hash := deployment.Spec.Template.Annotations[cookieSecretHashAnnotation]; hash != secretHash(secret) {
+			t.Errorf("expected pod template to have the hash of the Secret, got %s", hash)
+		}
+		return string(secret.Data[cookieSecretKey]), deployment.Spec.Template.Annotations[cookieSecretHashAnnotation]
+	}
+
+	// The secret passed as an argument by older operators is kept
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	cookieSecret, hash := getCookieSecret()
+	if cookieSecret != "from-args" {
+		t.Errorf("expected cookie secret from the arguments, got %s", cookieSecret)
+	}
+	deployment := &appsv1.Deployment{}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, deployment); err != nil {
+		t.Fatalf("get Deployment: (%v)", err)
+	}
+	proxy := findContainerSpec(deployment, cfg.OauthProxyContainerName)
+	if findArg(proxy, "--cookie-secret=") != "" || findArg(proxy, "--cookie-secret-file=") == "" {
+		t.Errorf("expected the cookie secret to be read from a file, got %v", proxy.Args)
+	}
+
+	// Another reconcile keeps it
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	if kept, _ := getCookieSecret(); kept != cookieSecret {
+		t.Errorf("expected cookie secret to be kept, got %s", kept)
+	}
+
+	// Changing the rotation annotation regenerates it and rolls the pods
+	instance := &pushv1alpha1.UnifiedPushServer{}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, instance); err != nil {
+		t.Fatalf("get UnifiedPushServer: (%v)", err)
+	}
+	instance.Annotations = map[string]string{RotateCookieSecretAnnotation: "1"}
+	if err := r.client.Update(context.TODO(), instance); err != nil {
+		t.Fatalf("update UnifiedPushServer: (%v)", err)
+	}
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	rotated, rotatedHash := getCookieSecret()
+	if rotated == cookieSecret || rotated == "" || rotatedHash == hash {
+		t.Errorf("expected cookie secret to be rotated, got %s", rotated)
+	}
+
+	// Only once per value of the annotation
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	if kept, _ := getCookieSecret(); kept != rotated {
+		t.Errorf("expected rotated cookie secret to be kept, got %s", kept)
+	}
+}
+
+func TestReconcileUnifiedPushServer_CredentialRotation(t *testing.T) {
+	cr := crWithDefaults.DeepCopy()
+	r := buildReconcileWithFakeClientWithMocks([]runtime.Object{cr}, t)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace}}
+	secretName := types.NamespacedName{Name: cr.Name + "-postgresql", Namespace: cr.Namespace}
+	jobName := types.NamespacedName{Name: cr.Name + "-postgresql-rotate", Namespace: cr.Namespace}
+	getSecret := func() *corev1.Secret {
+		secret := &corev1.Secret{}
+		if err := r.client.Get(context.TODO(), secretName, secret); err != nil {
+			t.Fatalf("get Secret: (%v)", err)
+		}
+		return secret
+	}
+	getInstance := func() *pushv1alpha1.UnifiedPushServer {
+		instance := &pushv1alpha1.UnifiedPushServer{}
+		if err := r.client.Get(context.TODO(), req.NamespacedName, instance); err != nil {
+			t.Fatalf("get UnifiedPushServer: (%v)", err)
+		}
+		return instance
+	}
+
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	postgresql := &appsv1.Deployment{}
+	if err := r.client.Get(context.TODO(), secretName, postgresql); err != nil {
+		t.Fatalf("get Deployment: (%v)", err)
+	}
+	postgresql.Status.ReadyReplicas = 1
+	if err := r.client.Update(context.TODO(), postgresql); err != nil {
+		t.Fatalf("update Deployment: (%v)", err)
+	}
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	password := string(getSecret().Data["POSTGRES_PASSWORD"])
+	getSecretsHash := func() string {
+		deployment := &appsv1.Deployment{}
+		if err := r.client.Get(context.TODO(), req.NamespacedName, deployment); err != nil {
+			t.Fatalf("get Deployment: (%v)", err)
+		}
+		return deployment.Spec.Template.Annotations[secretsHashAnnotation]
+	}
+	secretsHash := getSecretsHash()
+
+	// Nothing is rotated until it's requested
+	if err := r.client.Get(context.TODO(), jobName, &batchv1.Job{}); !errors.IsNotFound(err) {
+		t.Fatalf("expected no rotation Job, got (%v)", err)
+	}
+
+	instance := getInstance()
+	instance.Annotations = map[string]string{RotateCredentialsAnnotation: "1"}
+	if err := r.client.Update(context.TODO(), instance); err != nil {
+		t.Fatalf("update UnifiedPushServer: (%v)", err)
+	}
+	res, err := r.Reconcile(req)
+	if err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	if res.RequeueAfter == 0 {
+		t.Error("expected a requeue while the password is being changed")
+	}
+	secret := getSecret()
+	newPassword := string(secret.Data[newPostgresPasswordKey])
+	if newPassword == "" || string(secret.Data["POSTGRES_PASSWORD"]) != password {
+		t.Errorf("expected the new password to be kept aside until it's changed, got %v", secret.Data)
+	}
+
+	// The password is swapped once the Job has succeeded
+	job := &batchv1.Job{}
+	if err := r.client.Get(context.TODO(), jobName, job); err != nil {
+		t.Fatalf("get Job: (%v)", err)
+	}
+	job.Status.Succeeded = 1
+	if err := r.client.Update(context.TODO(), job); err != nil {
+		t.Fatalf("update Job: (%v)", err)
+	}
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	secret = getSecret()
+	if string(secret.Data["POSTGRES_PASSWORD"]) != newPassword {
+		t.Errorf("expected the password to be rotated, got %s", secret.Data["POSTGRES_PASSWORD"])
+	}
+	if _, ok := secret.Data[newPostgresPasswordKey]; ok {
+		t.Errorf("expected %s to be removed", newPostgresPasswordKey)
+	}
+	if err := r.client.Get(context.TODO(), jobName, &batchv1.Job{}); !errors.IsNotFound(err) {
+		t.Errorf("expected the rotation Job to be deleted, got (%v)", err)
+	}
+	if getInstance().Status.LastCredentialRotation == nil {
+		t.Error("expected the rotation to be recorded in the status")
+	}
+	if getSecretsHash() == secretsHash {
+		t.Error("expected UPS to be restarted with the new password")
+	}
+
+	// Only once per value of the annotation
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	if err := r.client.Get(context.TODO(), jobName, &batchv1.Job{}); !errors.IsNotFound(err) {
+		t.Errorf("expected no new rotation Job, got (%v)", err)
+	}
+}
+
+func TestReconcileUnifiedPushServer_SecretsHash(t *testing.T) {
+	cr := crWithExternalDatabaseSecret.DeepCopy()
+	databaseSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: cr.Spec.DatabaseSecret, Namespace: cr.Namespace},
+		Data: map[string][]byte{
+			"POSTGRES_USERNAME": []byte("unifiedpush"),
+			"POSTGRES_PASSWORD": []byte("secret"),
+		},
+	}
+	r := buildReconcileWithFakeClientWithMocks([]runtime.Object{cr, databaseSecret}, t)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace}}
+	getSecretsHash := func() string {
+		deployment := &appsv1.Deployment{}
+		if err := r.client.Get(context.TODO(), req.NamespacedName, deployment); err != nil {
+			t.Fatalf("get Deployment: (%v)", err)
+		}
+		return deployment.Spec.Template.Annotations[secretsHashAnnotation]
+	}
+
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	hash := getSecretsHash()
+	if hash == "" {
+		t.Fatal("expected the pod template to have the hash of the referenced Secrets")
+	}
+
+	// The Secret isn't owned by the UnifiedPushServer, but still requeues it
+	if requests := unifiedPushServersForSecret(r.client, cr.Namespace, databaseSecret.Name); len(requests) != 1 || requests[0] != req {
+		t.Errorf("expected a request for %v, got %v", req, requests)
+	}
+	if requests := unifiedPushServersForSecret(r.client, cr.Namespace, "unrelated"); len(requests) != 0 {
+		t.Errorf("expected no requests for an unrelated Secret, got %v", requests)
+	}
+
+	// Nothing changed, so the pods aren't rolled
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	if getSecretsHash() != hash {
+		t.Error("expected the hash to be kept")
+	}
+
+	// A new password rolls the pods
+	databaseSecret.Data["POSTGRES_PASSWORD"] = []byte("changed")
+	if err := r.client.Update(context.TODO(), databaseSecret); err != nil {
+		t.Fatalf("update Secret: (%v)", err)
+	}
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	if getSecretsHash() == hash {
+		t.Error("expected the hash to change with the Secret")
+	}
+}
+
+func TestReconcileUnifiedPushServer_HighAvailability(t *testing.T) {
+	cr := crWithDefaults.DeepCopy()
+	replicas := int32(3)
+	cr.Spec.Replicas = &replicas
+	r := buildReconcileWithFakeClientWithMocks([]runtime.Object{cr}, t)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace}}
+	getDeployment := func() *appsv1.Deployment {
+		deployment := &appsv1.Deployment{}
+		if err := r.client.Get(context.TODO(), req.NamespacedName, deployment); err != nil {
+			t.Fatalf("get Deployment: (%v)", err)
+		}
+		return deployment
+	}
+	updateInstance := func(update func(*pushv1alpha1.UnifiedPushServer)) {
+		instance := &pushv1alpha1.UnifiedPushServer{}
+		if err := r.client.Get(context.TODO(), req.NamespacedName, instance); err != nil {
+			t.Fatalf("get UnifiedPushServer: (%v)", err)
+		}
+		update(instance)
+		if err := r.client.Update(context.TODO(), instance); err != nil {
+			t.Fatalf("update UnifiedPushServer: (%v)", err)
+		}
+		if _, err := r.Reconcile(req); err != nil {
+			t.Fatalf("reconcile: (%v)", err)
+		}
+	}
+
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	deployment := getDeployment()
+	if *deployment.Spec.Replicas != 3 || deployment.Spec.Strategy.Type != appsv1.RollingUpdateDeploymentStrategyType {
+		t.Errorf("expected 3 replicas with a rolling update, got %d with %s", *deployment.Spec.Replicas, deployment.Spec.Strategy.Type)
+	}
+	if affinity := deployment.Spec.Template.Spec.Affinity; affinity == nil || affinity.PodAntiAffinity == nil {
+		t.Errorf("expected the pods to be spread across nodes, got %+v", affinity)
+	}
+	pdb := &policyv1beta1.PodDisruptionBudget{}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, pdb); err != nil {
+		t.Fatalf("get PodDisruptionBudget: (%v)", err)
+	}
+	if pdb.Spec.MinAvailable.IntValue() != 2 {
+		t.Errorf("expected 2 pods to be kept available, got %s", pdb.Spec.MinAvailable.String())
+	}
+
+	// The PodDisruptionBudget is replaced when minAvailable changes
+	updateInstance(func(instance *pushv1alpha1.UnifiedPushServer) {
+		minAvailable := int32(3)
+		instance.Spec.MinAvailable = &minAvailable
+	})
+	if err := r.client.Get(context.TODO(), req.NamespacedName, pdb); err != nil {
+		t.Fatalf("get PodDisruptionBudget: (%v)", err)
+	}
+	if pdb.Spec.MinAvailable.IntValue() != 3 {
+		t.Errorf("expected 3 pods to be kept available, got %s", pdb.Spec.MinAvailable.String())
+	}
+
+	// Going back to a single replica removes the PodDisruptionBudget
+	updateInstance(func(instance *pushv1alpha1.UnifiedPushServer) {
+		instance.Spec.Replicas = nil
+		instance.Spec.MinAvailable = nil
+	})
+	deployment = getDeployment()
+	if *deployment.Spec.Replicas != 1 || deployment.Spec.Strategy.Type != appsv1.RecreateDeploymentStrategyType || deployment.Spec.Strategy.RollingUpdate != nil {
+		t.Errorf("expected a single replica that is recreated, got %d with %+v", *deployment.Spec.Replicas, deployment.Spec.Strategy)
+	}
+	if deployment.Spec.Template.Spec.Affinity != nil {
+		t.Errorf("expected no affinity, got %+v", deployment.Spec.Template.Spec.Affinity)
+	}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, pdb); !errors.IsNotFound(err) {
+		t.Errorf("expected the PodDisruptionBudget to be deleted, got (%v)", err)
+	}
+}
+
+func TestReconcileUnifiedPushServer_Autoscaling(t *testing.T) {
+	cr := crWithDefaults.DeepCopy()
+	minReplicas := int32(2)
+	cr.Spec.Autoscaling = &pushv1alpha1.UnifiedPushServerAutoscaling{
+		MinReplicas: &minReplicas,
+		MaxReplicas: 5,
+		CustomMetric: &pushv1alpha1.UnifiedPushServerCustomMetric{
+			Name:               "push_requests_per_second",
+			TargetAverageValue: resource.MustParse("10"),
+		},
+	}
+	r := buildReconcileWithFakeClientWithMocks([]runtime.Object{cr}, t)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace}}
+	getDeployment := func() *appsv1.Deployment {
+		deployment := &appsv1.Deployment{}
+		if err := r.client.Get(context.TODO(), req.NamespacedName, deployment); err != nil {
+			t.Fatalf("get Deployment: (%v)", err)
+		}
+		return deployment
+	}
+
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	hpa := &autoscalingv2beta1.HorizontalPodAutoscaler{}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, hpa); err != nil {
+		t.Fatalf("get HorizontalPodAutoscaler: (%v)", err)
+	}
+	if *hpa.Spec.MinReplicas != 2 || hpa.Spec.MaxReplicas != 5 || hpa.Spec.ScaleTargetRef.Name != cr.Name {
+		t.Errorf("unexpected HorizontalPodAutoscaler spec %+v", hpa.Spec)
+	}
+	if len(hpa.Spec.Metrics) != 2 || *hpa.Spec.Metrics[0].Resource.TargetAverageUtilization != 80 || hpa.Spec.Metrics[1].Pods.MetricName != "push_requests_per_second" {
+		t.Errorf("expected CPU and custom metrics, got %+v", hpa.Spec.Metrics)
+	}
+	deployment := getDeployment()
+	if *deployment.Spec.Replicas != 2 || deployment.Spec.Strategy.Type != appsv1.RollingUpdateDeploymentStrategyType {
+		t.Errorf("expected the minimum number of replicas with a rolling update, got %d with %s", *deployment.Spec.Replicas, deployment.Spec.Strategy.Type)
+	}
+
+	// The replicas set by the autoscaler are left alone
+	scaled := int32(4)
+	deployment.Spec.Replicas = &scaled
+	if err := r.client.Update(context.TODO(), deployment); err != nil {
+		t.Fatalf("update Deployment: (%v)", err)
+	}
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	if replicas := *getDeployment().Spec.Replicas; replicas != 4 {
+		t.Errorf("expected the autoscaled replicas to be kept, got %d", replicas)
+	}
+
+	// Turning autoscaling off removes the autoscaler and restores the replicas
+	instance := &pushv1alpha1.UnifiedPushServer{}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, instance); err != nil {
+		t.Fatalf("get UnifiedPushServer: (%v)", err)
+	}
+	instance.Spec.Autoscaling = nil
+	if err := r.client.Update(context.TODO(), instance); err != nil {
+		t.Fatalf("update UnifiedPushServer: (%v)", err)
+	}
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, hpa); !errors.IsNotFound(err) {
+		t.Errorf("expected the HorizontalPodAutoscaler to be deleted, got (%v)", err)
+	}
+	if replicas := *getDeployment().Spec.Replicas; replicas != 1 {
+		t.Errorf("expected a single replica, got %d", replicas)
+	}
+}
+
+func TestReconcileUnifiedPushServer_Images(t *testing.T) {
+	cr := crWithDefaults.DeepCopy()
+	upsImage := "mirror.example.com/aerogear/unifiedpush-configurable-container@sha256:9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+	cr.Spec.Images.UnifiedPush = upsImage
+	cr.Spec.ImagePullPolicy = corev1.PullIfNotPresent
+	cr.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "mirror"}}
+	r := buildReconcileWithFakeClientWithMocks([]runtime.Object{cr}, t)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace}}
+	postgresqlName := types.NamespacedName{Name: cr.Name + "-postgresql", Namespace: cr.Namespace}
+
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	deployment := &appsv1.Deployment{}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, deployment); err != nil {
+		t.Fatalf("get Deployment: (%v)", err)
+	}
+	if ups := findContainerSpec(deployment, cfg.UPSContainerName); ups.Image != upsImage || ups.ImagePullPolicy != corev1.PullIfNotPresent {
+		t.Errorf("expected the image from the spec, got %s with %s", ups.Image, ups.ImagePullPolicy)
+	}
+	if proxy := findContainerSpec(deployment, cfg.OauthProxyContainerName); proxy.Image != cfg.OauthProxyImage {
+		t.Errorf("expected the default OAuth proxy image, got %s", proxy.Image)
+	}
+	if secrets := deployment.Spec.Template.Spec.ImagePullSecrets; len(secrets) != 1 || secrets[0].Name != "mirror" {
+		t.Errorf("expected the image pull secret from the spec, got %v", secrets)
+	}
+
+	// An image set later is rolled out to the PostgreSQL Deployment too
+	instance := &pushv1alpha1.UnifiedPushServer{}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, instance); err != nil {
+		t.Fatalf("get UnifiedPushServer: (%v)", err)
+	}
+	instance.Spec.Images.Postgres = "mirror.example.com/centos/postgresql-10-centos7:1"
+	if err := r.client.Update(context.TODO(), instance); err != nil {
+		t.Fatalf("update UnifiedPushServer: (%v)", err)
+	}
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	postgresql := &appsv1.Deployment{}
+	if err := r.client.Get(context.TODO(), postgresqlName, postgresql); err != nil {
+		t.Fatalf("get Deployment: (%v)", err)
+	}
+	if container := findContainerSpec(postgresql, cfg.PostgresContainerName); container.Image != instance.Spec.Images.Postgres || container.ImagePullPolicy != corev1.PullIfNotPresent {
+		t.Errorf("expected the PostgreSQL image from the spec, got %s with %s", container.Image, container.ImagePullPolicy)
+	}
+	if secrets := postgresql.Spec.Template.Spec.ImagePullSecrets; len(secrets) != 1 || secrets[0].Name != "mirror" {
+		t.Errorf("expected the image pull secret from the spec, got %v", secrets)
+	}
+}
+
+func TestReconcileUnifiedPushServer_LegacyNames(t *testing.T) {
+	cr := crWithBackup.DeepCopy()
+	cr.UID = "example-uid"
+	otherCR := crWithDefaults.DeepCopy()
+	otherCR.UID = "other-uid"
+	ownedBy := func(owner *pushv1alpha1.UnifiedPushServer) []metav1.OwnerReference {
+		controller := true
+		return []metav1.OwnerReference{{
+			APIVersion: "push.aerogear.org/v1alpha1",
+			Kind:       "UnifiedPushServer",
+			Name:       owner.Name,
+			UID:        owner.UID,
+			Controller: &controller,
+		}}
+	}
+	legacyServiceMonitor := &monitoringv1.ServiceMonitor{ObjectMeta: metav1.ObjectMeta{Name: "unifiedpush", Namespace: cr.Namespace, OwnerReferences: ownedBy(cr)}}
+	legacyPrometheusRule := &monitoringv1.PrometheusRule{ObjectMeta: metav1.ObjectMeta{Name: "unifiedpush", Namespace: cr.Namespace, OwnerReferences: ownedBy(otherCR)}}
+	legacyGrafanaDashboard := &integreatlyv1alpha1.GrafanaDashboard{ObjectMeta: metav1.ObjectMeta{Name: "unifiedpushserver-dashboard", Namespace: cr.Namespace, OwnerReferences: ownedBy(cr)}}
+	serviceAccount := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "example-with-backups-backupjob", Namespace: cr.Namespace}}
+	legacyServiceAccount := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "backupjob", Namespace: cr.Namespace}}
+
+	r := buildReconcileWithFakeClientWithMocks([]runtime.Object{cr, legacyServiceMonitor, legacyPrometheusRule, legacyGrafanaDashboard, serviceAccount, legacyServiceAccount}, t)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace}}
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+
+	for name, obj := range map[string]runtime.Object{
+		"example-with-backups-unifiedpush": &monitoringv1.ServiceMonitor{},
+		"example-with-backups-dashboard":   &integreatlyv1alpha1.GrafanaDashboard{},
+	} {
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: cr.Namespace}, obj); err != nil {
+			t.Errorf("expected %T %s to be created: (%v)", obj, name, err)
+		}
+	}
+	serviceMonitor := &monitoringv1.ServiceMonitor{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: "example-with-backups-unifiedpush", Namespace: cr.Namespace}, serviceMonitor); err == nil && serviceMonitor.Spec.Selector.MatchLabels["app"] != cr.Name {
+		t.Errorf("expected the ServiceMonitor to only select the Service of %s, got %v", cr.Name, serviceMonitor.Spec.Selector.MatchLabels)
+	}
+
+	// Objects of this instance with legacy names are replaced
+	for name, obj := range map[string]runtime.Object{
+		legacyServiceMonitor.Name:   &monitoringv1.ServiceMonitor{},
+		legacyGrafanaDashboard.Name: &integreatlyv1alpha1.GrafanaDashboard{},
+	} {
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: cr.Namespace}, obj); !errors.IsNotFound(err) {
+			t.Errorf("expected %T %s to be deleted, got (%v)", obj, name, err)
+		}
+	}
+
+	// The ones of another instance are left alone
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: legacyPrometheusRule.Name, Namespace: cr.Namespace}, &monitoringv1.PrometheusRule{}); err != nil {
+		t.Errorf("expected the PrometheusRule of another UnifiedPushServer to be kept: (%v)", err)
+	}
+
+	// The backup Jobs prefer the ServiceAccount of this instance
+	cronJob := &batchv1beta1.CronJob{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: "example-backup-1", Namespace: cr.Namespace}, cronJob); err != nil {
+		t.Fatalf("get CronJob: (%v)", err)
+	}
+	if sa := cronJob.Spec.JobTemplate.Spec.Template.Spec.ServiceAccountName; sa != serviceAccount.Name {
+		t.Errorf("expected the backup Jobs to run as %s, got %s", serviceAccount.Name, sa)
+	}
+}
+
+func TestAMQNames(t *testing.T) {
+	cr := crWithDefaults.DeepCopy()
+	if name := newAddressSpace(cr).Name; name != cr.Name {
+		t.Errorf("expected the AddressSpace to be named %s, got %s", cr.Name, name)
+	}
+	user, err := newMessagingUser(cr)
+	if err != nil {
+		t.Fatalf("new MessagingUser: (%v)", err)
+	}
+	if expected := cr.Name + ".upsuser"; user.Name != expected {
+		t.Errorf("expected the MessagingUser to be named %s, got %s", expected, user.Name)
+	}
+	if expected := cr.Name + ".metricsqueue"; newQueue(cr, "MetricsQueue").Name != expected {
+		t.Errorf("expected the queue to be named %s, got %s", expected, newQueue(cr, "MetricsQueue").Name)
+	}
+	if expected := cr.Name + ".apnsclient"; newTopic(cr, "topic/APNSClient").Name != expected {
+		t.Errorf("expected the topic to be named %s, got %s", expected, newTopic(cr, "topic/APNSClient").Name)
+	}
+}
+
+func TestReconcileUnifiedPushServer_Ingress(t *testing.T) {
+	cr := crWithDefaults.DeepCopy()
+	cr.Spec.Ingress = &pushv1alpha1.UnifiedPushServerIngress{
+		ClassName:     "nginx",
+		Host:          "push.example.com",
+		TLSSecretName: "push-tls",
+	}
+	r := buildReconcileWithFakeClientWithMocks([]runtime.Object{cr}, t)
+	// A cluster without Routes
+	r.apiVersionChecker = &apiVersionChecker{
+		check: func(apiGroupVersion string) (bool, error) {
+			return apiGroupVersion != routev1.SchemeGroupVersion.String(), nil
+		},
+	}
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace}}
+	key := types.NamespacedName{Name: fmt.Sprintf("%s-unifiedpush-proxy", cr.Name), Namespace: cr.Namespace}
+	getStatus := func() pushv1alpha1.UnifiedPushServerStatus {
+		ups := &pushv1alpha1.UnifiedPushServer{}
+		if err := r.client.Get(context.TODO(), req.NamespacedName, ups); err != nil {
+			t.Fatalf("get UnifiedPushServer: (%v)", err)
+		}
+		return ups.Status
+	}
+
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+
+	if err := r.client.Get(context.TODO(), key, &routev1.Route{}); !errors.IsNotFound(err) {
+		t.Errorf("expected no Route to be created, got (%v)", err)
+	}
+	ingress := &extensionsv1beta1.Ingress{}
+	if err := r.client.Get(context.TODO(), key, ingress); err != nil {
+		t.Fatalf("get Ingress: (%v)", err)
+	}
+	if class := ingress.Annotations["kubernetes.io/ingress.class"]; class != "nginx" {
+		t.Errorf("expected the nginx ingress class, got %q", class)
+	}
+	if len(ingress.Spec.Rules) != 1 || ingress.Spec.Rules[0].Host != "push.example.com" {
+		t.Fatalf("expected a rule for push.example.com, got %v", ingress.Spec.Rules)
+	}
+	if backend := ingress.Spec.Rules[0].HTTP.Paths[0].Backend; backend.ServiceName != fmt.Sprintf("%s-unifiedpush-proxy", cr.Name) || backend.ServicePort.String() != "web" {
+		t.Errorf("expected the Ingress to point at the OAuth proxy Service, got %v", backend)
+	}
+	if len(ingress.Spec.TLS) != 1 || ingress.Spec.TLS[0].SecretName != "push-tls" || ingress.Spec.TLS[0].Hosts[0] != "push.example.com" {
+		t.Errorf("expected TLS for push.example.com with the push-tls Secret, got %v", ingress.Spec.TLS)
+	}
+
+	status := getStatus()
+	if condition := status.GetCondition(pushv1alpha1.ConditionIngressReady); condition == nil || condition.Status != corev1.ConditionFalse {
+		t.Errorf("expected IngressReady to be False until the Ingress has an address, got %v", condition)
+	}
+	if status.GetCondition(pushv1alpha1.ConditionRouteAdmitted) != nil {
+		t.Error("expected no RouteAdmitted condition without Routes")
+	}
+	if status.AdminConsoleURL != "" {
+		t.Errorf("expected no admin console URL yet, got %s", status.AdminConsoleURL)
+	}
+
+	// The ingress controller gives it an address
+	ingress.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "192.0.2.10"}}
+	if err := r.client.Update(context.TODO(), ingress); err != nil {
+		t.Fatalf("update Ingress: (%v)", err)
+	}
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	status = getStatus()
+	if condition := status.GetCondition(pushv1alpha1.ConditionIngressReady); condition == nil || condition.Status != corev1.ConditionTrue {
+		t.Errorf("expected IngressReady to be True, got %v", condition)
+	}
+	if status.AdminConsoleURL != "https://push.example.com" {
+		t.Errorf("expected the admin console URL to be https://push.example.com, got %s", status.AdminConsoleURL)
+	}
+}
+
+func TestReconcileUnifiedPushServer_OIDCAuth(t *testing.T) {
+	cr := crWithDefaults.DeepCopy()
+	cr.Spec.Auth = &pushv1alpha1.UnifiedPushServerAuth{
+		OIDC: &pushv1alpha1.UnifiedPushServerOIDC{
+			IssuerURL:     "https://keycloak.example.com/auth/realms/push",
+			ClientSecret:  "ups-oidc-client",
+			AllowedGroups: []string{"push-admins", "push-operators"},
+		},
+	}
+	clientSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ups-oidc-client", Namespace: cr.Namespace},
+		Data: map[string][]byte{
+			"client-id":     []byte("unifiedpush"),
+			"client-secret": []byte("secret"),
+		},
+	}
+	r := buildReconcileWithFakeClientWithMocks([]runtime.Object{cr, clientSecret}, t)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace}}
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+
+	deployment := &appsv1.Deployment{}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, deployment); err != nil {
+		t.Fatalf("get Deployment: (%v)", err)
+	}
+	proxy := findContainerSpec(deployment, cfg.OauthProxyContainerName)
+	if proxy == nil {
+		t.Fatal("expected the Deployment to have a proxy container")
+	}
+	if proxy.Image != cfg.OIDCProxyImage {
+		t.Errorf("expected the %s image, got %s", cfg.OIDCProxyImage, proxy.Image)
+	}
+	args := map[string]bool{}
+	for _, arg := range proxy.Args {
+		args[arg] = true
+	}
+	for _, arg := range []string{
+		"--provider=oidc",
+		"--oidc-issuer-url=https://keycloak.example.com/auth/realms/push",
+		"--skip-auth-regex=/rest/sender,/rest/registry/device,/rest/prometheus/metrics,/rest/auth/config",
+		"--allowed-group=push-admins",
+		"--allowed-group=push-operators",
+	} {
+		if !args[arg] {
+			t.Errorf("expected the proxy args to contain %s, got %v", arg, proxy.Args)
+		}
+	}
+	env := map[string]*corev1.SecretKeySelector{}
+	for _, e := range proxy.Env {
+		if e.ValueFrom != nil {
+			env[e.Name] = e.ValueFrom.SecretKeyRef
+		}
+	}
+	if ref := env["OAUTH2_PROXY_CLIENT_SECRET"]; ref == nil || ref.Name != "ups-oidc-client" || ref.Key != "client-secret" {
+		t.Errorf("expected the client secret to come from the ups-oidc-client Secret, got %v", ref)
+	}
+	if ref := env["OAUTH2_PROXY_COOKIE_SECRET"]; ref == nil || ref.Name != oauthProxySecretName(cr) {
+		t.Errorf("expected the cookie secret to come from the %s Secret, got %v", oauthProxySecretName(cr), ref)
+	}
+
+	// A new client secret rolls the pods
+	hash := deployment.Spec.Template.Annotations[secretsHashAnnotation]
+	clientSecret.Data["client-secret"] = []byte("changed")
+	if err := r.client.Update(context.TODO(), clientSecret); err != nil {
+		t.Fatalf("update Secret: (%v)", err)
+	}
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, deployment); err != nil {
+		t.Fatalf("get Deployment: (%v)", err)
+	}
+	if deployment.Spec.Template.Annotations[secretsHashAnnotation] == hash {
+		t.Error("expected the secrets hash to change with the client secret")
+	}
+}
+
+func TestCredentialRotationDue(t *testing.T) {
+	now := time.Now()
+	scenarios := []struct {
+		name       string
+		days       int32
+		annotation string
+		rotatedAt  time.Time
+		expect     bool
+	}{
+		{name: "not due without an interval", rotatedAt: now.Add(-1000 * 24 * time.Hour)},
+		{name: "due when requested", annotation: "1", rotatedAt: now, expect: true},
+		{name: "not due within the interval", days: 30, rotatedAt: now.Add(-29 * 24 * time.Hour)},
+		{name: "due after the interval", days: 30, rotatedAt: now.Add(-30 * 24 * time.Hour), expect: true},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			cr := crWithDefaults.DeepCopy()
+			cr.Spec.CredentialRotationDays = scenario.days
+			cr.Annotations = map[string]string{RotateCredentialsAnnotation: scenario.annotation}
+			secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+				credentialRotationAnnotation:   "",
+				credentialsRotatedAtAnnotation: scenario.rotatedAt.UTC().Format(time.RFC3339),
+			}}}
+			if due := credentialRotationDue(cr, secret, now); due != scenario.expect {
+				t.Errorf("expected %v, got %v", scenario.expect, due)
+			}
+		})
+	}
+}
+
 var (
 	crWithDefaults = pushv1alpha1.UnifiedPushServer{
 		ObjectMeta: metav1.ObjectMeta{
@@ -171,3 +1140,32 @@ var (
 		},
 	}
 )
+
+func TestReconcileUnifiedPushServer_ObservedGeneration(t *testing.T) {
+	cr := crWithDefaults.DeepCopy()
+	cr.Generation = 2
+	r := buildReconcileWithFakeClientWithMocks([]runtime.Object{cr}, t)
+	key := types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace}
+
+	// The new pods are still rolling out
+	if _, err := r.manageSuccess(cr, resources{}, false); err != nil {
+		t.Fatalf("manage success: (%v)", err)
+	}
+	found := &pushv1alpha1.UnifiedPushServer{}
+	if err := r.client.Get(context.TODO(), key, found); err != nil {
+		t.Fatalf("get UnifiedPushServer: (%v)", err)
+	}
+	if found.Status.ObservedGeneration != 0 {
+		t.Errorf("expected observedGeneration not to be set before the components are ready, got %d", found.Status.ObservedGeneration)
+	}
+
+	if _, err := r.manageSuccess(found, resources{}, true); err != nil {
+		t.Fatalf("manage success: (%v)", err)
+	}
+	if err := r.client.Get(context.TODO(), key, found); err != nil {
+		t.Fatalf("get UnifiedPushServer: (%v)", err)
+	}
+	if found.Status.ObservedGeneration != 2 {
+		t.Errorf("expected observedGeneration 2 once the components are ready, got %d", found.Status.ObservedGeneration)
+	}
+}
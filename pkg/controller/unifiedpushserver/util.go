@@ -10,7 +10,9 @@ import (
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/conversion"
 )
 
 func labels(cr *pushv1alpha1.UnifiedPushServer, suffix string) map[string]string {
@@ -29,6 +31,105 @@ func objectMeta(cr *pushv1alpha1.UnifiedPushServer, suffix string) metav1.Object
 	}
 }
 
+// mergeStringMaps sets the entries of desired in existing, and keeps
+// the entries that were added by someone else, e.g. labels and
+// annotations set by other controllers
+func mergeStringMaps(existing map[string]string, desired map[string]string) map[string]string {
+	if existing == nil {
+		existing = map[string]string{}
+	}
+	for k, v := range desired {
+		existing[k] = v
+	}
+	return existing
+}
+
+// reconcileServiceSpec sets the selector and ports of a Service. The
+// fields allocated by the cluster, like the clusterIP, are left alone.
+func reconcileServiceSpec(service *corev1.Service, selector map[string]string, ports []corev1.ServicePort) {
+	service.Spec.Selector = selector
+	service.Spec.Ports = ports
+}
+
+// reconcileSecretData sets the given keys in the data of a Secret,
+// leaving any other keys alone
+func reconcileSecretData(secret *corev1.Secret, desired map[string]string) {
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	for k, v := range desired {
+		secret.Data[k] = []byte(v)
+	}
+}
+
+// semantic compares API objects by the meaning of their fields, e.g.
+// "1Gi" and "1024Mi" are the same memory limit
+var semantic = conversion.EqualitiesOrDie(
+	func(a, b resource.Quantity) bool {
+		return a.Cmp(b) == 0
+	},
+	func(a, b metav1.MicroTime) bool {
+		return a.UTC() == b.UTC()
+	},
+	func(a, b metav1.Time) bool {
+		return a.UTC() == b.UTC()
+	},
+)
+
+// reconcileContainers returns the desired containers, keeping the
+// fields of the existing containers that the API server defaults, e.g.
+// the termination message path and the probe periods
+func reconcileContainers(existing []corev1.Container, desired []corev1.Container) []corev1.Container {
+	containers := []corev1.Container{}
+	for _, d := range desired {
+		container := d
+		for _, e := range existing {
+			if e.Name != d.Name {
+				continue
+			}
+			container = e
+			container.Image = d.Image
+			container.ImagePullPolicy = d.ImagePullPolicy
+			container.Command = d.Command
+			container.Args = d.Args
+			container.Env = d.Env
+			container.Ports = d.Ports
+			container.Resources = d.Resources
+			container.ReadinessProbe = reconcileProbe(e.ReadinessProbe, d.ReadinessProbe)
+			container.LivenessProbe = reconcileProbe(e.LivenessProbe, d.LivenessProbe)
+			container.VolumeMounts = d.VolumeMounts
+			break
+		}
+		containers = append(containers, container)
+	}
+	return containers
+}
+
+func reconcileProbe(existing *corev1.Probe, desired *corev1.Probe) *corev1.Probe {
+	if existing == nil || desired == nil {
+		return desired
+	}
+	probe := existing.DeepCopy()
+	probe.Handler = desired.Handler
+	probe.InitialDelaySeconds = desired.InitialDelaySeconds
+	probe.TimeoutSeconds = desired.TimeoutSeconds
+	return probe
+}
+
+// findArg returns the value of the argument with the given prefix,
+// e.g. "--cookie-secret=", or an empty string if it isn't set
+func findArg(container *corev1.Container, prefix string) string {
+	if container == nil {
+		return ""
+	}
+	for _, arg := range container.Args {
+		if strings.HasPrefix(arg, prefix) {
+			return strings.TrimPrefix(arg, prefix)
+		}
+	}
+	return ""
+}
+
 func generatePassword() (string, error) {
 	generatedPassword, err := uuid.NewRandom()
 	if err != nil {
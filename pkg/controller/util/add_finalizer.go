@@ -10,8 +10,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-// AddFinalizer will add a finalizer to the PushApplication CR so that
-// we can delete from UPS appropriately
+const finalizer = "finalizer.push.aerogear.org"
+
+// AddFinalizer will add a finalizer to a PushApplication or variant CR
+// so that we can delete from UPS appropriately
 func AddFinalizer(client client.Client, reqLogger logr.Logger, o metav1.Object) error {
 	// This is based on the example code at:
 	// https://github.com/operator-framework/operator-sdk/blob/master/doc/user-guide.md#handle-cleanup-on-deletion
@@ -20,8 +22,8 @@ func AddFinalizer(client client.Client, reqLogger logr.Logger, o metav1.Object)
 		return nil
 	}
 
-	reqLogger.Info("Adding Finalizer to the PushApplication")
-	o.SetFinalizers([]string{"finalizer.push.aerogear.org"})
+	reqLogger.Info("Adding Finalizer")
+	o.SetFinalizers([]string{finalizer})
 
 	runtimeObject, ok := o.(runtime.Object)
 	if !ok {
@@ -0,0 +1,34 @@
+package util
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ImportApplicationsAnnotation can be set to "true" on a
+	// UnifiedPushServer to create CRs for the applications and
+	// variants that already exist in it. It is removed once they have
+	// been imported.
+	ImportApplicationsAnnotation = "push.aerogear.org/import-applications"
+
+	// AdoptedAnnotation is set on the CRs created by an import, with
+	// the id of the application or variant in UPS that they were
+	// created from, so that it's used instead of creating a new one
+	AdoptedAnnotation = "push.aerogear.org/adopted"
+)
+
+// AdoptedID returns the id of the application or variant in UPS that
+// a CR was imported from, or an empty string if it wasn't imported
+func AdoptedID(o metav1.Object) string {
+	return o.GetAnnotations()[AdoptedAnnotation]
+}
+
+// NameInUPS returns the name of the application or variant in UPS
+// for a CR, which is the name in its spec, or the name of the CR if
+// that isn't set
+func NameInUPS(o metav1.Object, specName string) string {
+	if specName != "" {
+		return specName
+	}
+	return o.GetName()
+}
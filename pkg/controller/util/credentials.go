@@ -0,0 +1,15 @@
+package util
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+)
+
+// CredentialsHash returns a hash of the given credentials. It's kept
+// in the status of a variant to tell whether the credentials have
+// changed since they were last sent to UPS, which doesn't reliably
+// return key material in its responses.
+func CredentialsHash(credentials ...string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join(credentials, "\x00"))))
+}
@@ -0,0 +1,42 @@
+package util
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RemoveFinalizer will remove our finalizer from a CR once it has been
+// cleaned up in UPS, so that the deletion can go ahead
+func RemoveFinalizer(client client.Client, reqLogger logr.Logger, o metav1.Object) error {
+	finalizers := []string{}
+	for _, f := range o.GetFinalizers() {
+		if f != finalizer {
+			finalizers = append(finalizers, f)
+		}
+	}
+	if len(finalizers) == len(o.GetFinalizers()) {
+		return nil
+	}
+
+	reqLogger.Info("Removing Finalizer")
+	o.SetFinalizers(finalizers)
+
+	runtimeObject, ok := o.(runtime.Object)
+	if !ok {
+		reqLogger.Info("Can't determine the type of thing to remove finalizer from")
+		return nil
+	}
+
+	err := client.Update(context.TODO(), runtimeObject)
+	if err != nil {
+		reqLogger.Error(err, "Failed to remove finalizer from a CR")
+		return err
+	}
+
+	return nil
+}
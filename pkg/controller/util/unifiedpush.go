@@ -0,0 +1,116 @@
+package util
+
+import (
+	"context"
+	"fmt"
+
+	pushv1alpha1 "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1"
+
+	routev1 "github.com/openshift/api/route/v1"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// UnifiedPushURLFunc returns the base URL of the REST API of a
+// UnifiedPushServer. The controllers that call the REST API take one,
+// so that their tests can point them at a fake server.
+type UnifiedPushURLFunc func(ups *pushv1alpha1.UnifiedPushServer) string
+
+// UnifiedPushServerURL returns the in-cluster URL of the REST API of the
+// UnifiedPush Server created for the given CR. This goes straight to the
+// UPS Service, so there is no OAuth proxy in the way.
+func UnifiedPushServerURL(ups *pushv1alpha1.UnifiedPushServer) string {
+	return fmt.Sprintf("http://%s-unifiedpush.%s.svc", ups.Name, ups.Namespace)
+}
+
+// PostgresClaimName returns the name of the PersistentVolumeClaim of
+// the PostgreSQL instance that the operator creates for a
+// UnifiedPushServer
+func PostgresClaimName(ups *pushv1alpha1.UnifiedPushServer) string {
+	return fmt.Sprintf("%s-postgresql", ups.Name)
+}
+
+// UnifiedPushServerPublicURL returns the public URL of a
+// UnifiedPushServer, from the host of its OAuth proxy Route, or of its
+// Ingress on clusters without Routes. It returns an empty string if
+// the Route hasn't been created or admitted yet.
+func UnifiedPushServerPublicURL(c client.Client, ups *pushv1alpha1.UnifiedPushServer) (string, error) {
+	key := types.NamespacedName{Name: fmt.Sprintf("%s-unifiedpush-proxy", ups.Name), Namespace: ups.Namespace}
+	route := &routev1.Route{}
+	err := c.Get(context.TODO(), key, route)
+	if _, isNoKindMatchError := err.(*meta.NoKindMatchError); isNoKindMatchError {
+		ingress := &extensionsv1beta1.Ingress{}
+		err = c.Get(context.TODO(), key, ingress)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return "", nil
+			}
+			return "", err
+		}
+		return IngressURL(ingress), nil
+	}
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	if route.Spec.Host == "" {
+		return "", nil
+	}
+	return fmt.Sprintf("https://%s", route.Spec.Host), nil
+}
+
+// IngressURL returns the URL that an Ingress is served on, from its
+// host or else from the address that the ingress controller gave it.
+// It returns an empty string if it has neither yet.
+func IngressURL(ingress *extensionsv1beta1.Ingress) string {
+	host := ""
+	if len(ingress.Spec.Rules) > 0 {
+		host = ingress.Spec.Rules[0].Host
+	}
+	for _, lb := range ingress.Status.LoadBalancer.Ingress {
+		if host != "" {
+			break
+		}
+		host = lb.Hostname
+		if host == "" {
+			host = lb.IP
+		}
+	}
+	if host == "" {
+		return ""
+	}
+
+	scheme := "http"
+	if len(ingress.Spec.TLS) > 0 {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s", scheme, host)
+}
+
+// IsUnifiedPushServerReady returns true once the UnifiedPushServer CR
+// reports all of its resources as ready
+func IsUnifiedPushServerReady(ups *pushv1alpha1.UnifiedPushServer) bool {
+	return ups.Status.Ready != nil && *ups.Status.Ready
+}
+
+// GetPushApplication fetches the named PushApplication and the
+// UnifiedPushServer that it is registered with. The returned error
+// can be checked with errors.IsNotFound if either one is missing.
+func GetPushApplication(c client.Client, namespace string, name string) (*pushv1alpha1.PushApplication, *pushv1alpha1.UnifiedPushServer, error) {
+	app := &pushv1alpha1.PushApplication{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, app); err != nil {
+		return nil, nil, err
+	}
+
+	ups := &pushv1alpha1.UnifiedPushServer{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: app.Spec.UnifiedPushServer, Namespace: namespace}, ups); err != nil {
+		return app, nil, err
+	}
+
+	return app, ups, nil
+}
@@ -0,0 +1,28 @@
+package webpushvariant
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+)
+
+// generateVAPIDKeys generates a new VAPID key pair, as described in
+// RFC 8292. The public key is the uncompressed P-256 point and the
+// private key is the raw 32 byte scalar, both encoded with URL-safe
+// base64 and no padding, which is the format that UPS expects.
+func generateVAPIDKeys() (string, string, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+
+	publicKey := elliptic.Marshal(elliptic.P256(), key.X, key.Y)
+
+	// The scalar may be shorter than 32 bytes, so left-pad it
+	privateKey := make([]byte, 32)
+	d := key.D.Bytes()
+	copy(privateKey[32-len(d):], d)
+
+	return base64.RawURLEncoding.EncodeToString(publicKey), base64.RawURLEncoding.EncodeToString(privateKey), nil
+}
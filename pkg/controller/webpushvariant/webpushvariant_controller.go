@@ -0,0 +1,383 @@
+package webpushvariant
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	pushv1alpha1 "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1"
+	"github.com/aerogear/unifiedpush-operator/pkg/controller/util"
+	"github.com/aerogear/unifiedpush-operator/pkg/unifiedpush"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	controllerName    = "webpushvariant-controller"
+	variantType       = "web_push"
+	requeueDelay      = 30 * time.Second
+	requeueErrorDelay = 5 * time.Second
+)
+
+var log = logf.Log.WithName(controllerName)
+
+// Add creates a new WebPushVariant Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileWebPushVariant{
+		client:         mgr.GetClient(),
+		scheme:         mgr.GetScheme(),
+		recorder:       mgr.GetRecorder(controllerName),
+		unifiedpushURL: util.UnifiedPushServerURL,
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource WebPushVariant
+	err = c.Watch(&source.Kind{Type: &pushv1alpha1.WebPushVariant{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to secondary resource Secret and requeue the owner WebPushVariant
+	err = c.Watch(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &pushv1alpha1.WebPushVariant{},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the credentials Secrets, which aren't owned
+	// by the WebPushVariants, and requeue the ones that reference them
+	err = c.Watch(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+			return variantsForSecret(mgr.GetClient(), o.Meta.GetNamespace(), o.Meta.GetName())
+		}),
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// variantsForSecret returns a request for each WebPushVariant that
+// takes its credentials from the given Secret
+func variantsForSecret(c client.Client, namespace string, name string) []reconcile.Request {
+	variants := &pushv1alpha1.WebPushVariantList{}
+	if err := c.List(context.TODO(), client.InNamespace(namespace), variants); err != nil {
+		log.Error(err, "Failed to list WebPushVariants", "Namespace", namespace)
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, v := range variants.Items {
+		if v.Spec.CredentialsSecret == name {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: v.Name, Namespace: v.Namespace}})
+		}
+	}
+	return requests
+}
+
+var _ reconcile.Reconciler = &ReconcileWebPushVariant{}
+
+// ReconcileWebPushVariant reconciles a WebPushVariant object
+type ReconcileWebPushVariant struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	client   client.Client
+	scheme   *runtime.Scheme
+	recorder record.EventRecorder
+
+	unifiedpushURL util.UnifiedPushURLFunc
+}
+
+// Reconcile makes sure that a Web Push variant exists in the
+// application of the referenced PushApplication. The VAPID key pair is
+// taken from the referenced Secret, or generated and stored in an
+// owned Secret if there is none.
+func (r *ReconcileWebPushVariant) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling WebPushVariant")
+
+	// Fetch the WebPushVariant instance
+	instance := &pushv1alpha1.WebPushVariant{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Return and don't requeue
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return r.manageError(instance, err)
+	}
+
+	app, ups, err := util.GetPushApplication(r.client, instance.Namespace, instance.Spec.PushApplication)
+	if err != nil {
+		if errors.IsNotFound(err) && instance.DeletionTimestamp != nil {
+			// The application is gone from UPS, and so are its variants
+			reqLogger.Info("PushApplication or UnifiedPushServer not found, nothing to clean up", "PushApplication.Name", instance.Spec.PushApplication)
+			return reconcile.Result{}, util.RemoveFinalizer(r.client, reqLogger, instance)
+		}
+		return r.manageError(instance, err)
+	}
+	upsClient := unifiedpush.NewClient(r.unifiedpushURL(ups))
+
+	if instance.DeletionTimestamp != nil {
+		if instance.Status.VariantId != "" {
+			reqLogger.Info("Deleting variant from UPS", "VariantId", instance.Status.VariantId)
+			if err := upsClient.DeleteVariant(instance.Status.PushApplicationId, variantType, instance.Status.VariantId); err != nil {
+				return r.manageError(instance, err)
+			}
+		}
+		return reconcile.Result{}, util.RemoveFinalizer(r.client, reqLogger, instance)
+	}
+
+	if err := util.AddFinalizer(r.client, reqLogger, instance); err != nil {
+		return r.manageError(instance, err)
+	}
+
+	if app.Status.PushApplicationId == "" || !util.IsUnifiedPushServerReady(ups) {
+		reqLogger.Info("Requeuing, PushApplication not ready.", "PushApplication.Name", app.Name)
+		return r.manageWaiting(instance, fmt.Sprintf("Waiting for PushApplication %s to be ready", app.Name))
+	}
+
+	//#region VAPID keys
+	var credentials *corev1.Secret
+	if instance.Spec.CredentialsSecret != "" {
+		credentials = &corev1.Secret{}
+		err = r.client.Get(context.TODO(), types.NamespacedName{Name: instance.Spec.CredentialsSecret, Namespace: instance.Namespace}, credentials)
+		if err != nil {
+			return r.manageError(instance, err)
+		}
+	} else {
+		credentials, err = r.generatedCredentials(instance)
+		if err != nil {
+			return r.manageError(instance, err)
+		}
+	}
+	//#endregion
+
+	desiredVariant, err := newWebPushVariant(instance, credentials)
+	if err != nil {
+		return r.manageError(instance, err)
+	}
+
+	// Variants imported from UPS take over the existing variant
+	if instance.Status.VariantId == "" && util.AdoptedID(instance) != "" {
+		instance.Status.VariantId = util.AdoptedID(instance)
+		instance.Status.PushApplicationId = app.Status.PushApplicationId
+	}
+
+	credentialsHash := util.CredentialsHash(desiredVariant.PrivateKey)
+
+	//#region UPS variant
+	// The variant of a previous application, e.g. when the
+	// PushApplication has been recreated, is deleted rather than left
+	// behind in UPS
+	if instance.Status.VariantId != "" && instance.Status.PushApplicationId != app.Status.PushApplicationId {
+		reqLogger.Info("Deleting variant of the previous application from UPS", "PushApplicationId", instance.Status.PushApplicationId, "VariantId", instance.Status.VariantId)
+		if err := upsClient.DeleteVariant(instance.Status.PushApplicationId, variantType, instance.Status.VariantId); err != nil {
+			return r.manageError(instance, err)
+		}
+		instance.Status.VariantId = ""
+		instance.Status.Secret = ""
+		instance.Status.CredentialsHash = ""
+	}
+
+	var variant *unifiedpush.WebPushVariant
+	if instance.Status.VariantId != "" {
+		variant, err = upsClient.GetWebPushVariant(app.Status.PushApplicationId, instance.Status.VariantId)
+		if err != nil {
+			return r.manageError(instance, err)
+		}
+	}
+
+	if variant == nil {
+		reqLogger.Info("Creating a new Web Push variant in UPS", "PushApplicationId", app.Status.PushApplicationId)
+		variant, err = upsClient.CreateWebPushVariant(app.Status.PushApplicationId, desiredVariant)
+		if err != nil {
+			return r.manageError(instance, err)
+		}
+
+		// Save the id straight away, so we don't create the variant
+		// twice if something below fails
+		instance.Status.PushApplicationId = app.Status.PushApplicationId
+		instance.Status.VariantId = variant.VariantID
+		instance.Status.Secret = variant.Secret
+		instance.Status.CredentialsHash = credentialsHash
+		err = r.client.Status().Update(context.TODO(), instance)
+		if err != nil {
+			reqLogger.Error(err, "Failed to update WebPushVariant status", "VariantId", variant.VariantID)
+			return r.manageError(instance, err)
+		}
+	} else if variant.Name != desiredVariant.Name ||
+		variant.Description != desiredVariant.Description ||
+		variant.PublicKey != desiredVariant.PublicKey ||
+		instance.Status.CredentialsHash != credentialsHash ||
+		variant.Alias != desiredVariant.Alias {
+		reqLogger.Info("Variant in UPS is different than the WebPushVariant spec. Going to update it now.", "VariantId", variant.VariantID)
+		desiredVariant.VariantID = variant.VariantID
+		if err := upsClient.UpdateWebPushVariant(app.Status.PushApplicationId, desiredVariant); err != nil {
+			return r.manageError(instance, err)
+		}
+	}
+	//#endregion
+
+	instance.Status.PushApplicationId = app.Status.PushApplicationId
+	instance.Status.VariantId = variant.VariantID
+	instance.Status.Secret = variant.Secret
+	instance.Status.PublicKey = desiredVariant.PublicKey
+	instance.Status.CredentialsSecret = credentials.Name
+	instance.Status.CredentialsHash = credentialsHash
+	return r.manageSuccess(instance)
+}
+
+func (r *ReconcileWebPushVariant) manageError(instance *pushv1alpha1.WebPushVariant, issue error) (reconcile.Result, error) {
+	r.recorder.Event(instance, "Warning", "ReconcileFailed", issue.Error())
+
+	instance.Status.Message = issue.Error()
+	instance.Status.Phase = pushv1alpha1.PhaseFailing
+
+	err := r.client.Status().Update(context.TODO(), instance)
+	if err != nil {
+		log.Error(err, "Unable to update status")
+	}
+
+	return reconcile.Result{
+		RequeueAfter: requeueErrorDelay,
+		Requeue:      true,
+	}, nil
+}
+
+func (r *ReconcileWebPushVariant) manageWaiting(instance *pushv1alpha1.WebPushVariant, message string) (reconcile.Result, error) {
+	instance.Status.Message = message
+	instance.Status.Phase = pushv1alpha1.PhaseInitializing
+
+	err := r.client.Status().Update(context.TODO(), instance)
+	if err != nil {
+		log.Error(err, "Unable to update status")
+	}
+
+	return reconcile.Result{RequeueAfter: requeueDelay}, nil
+}
+
+func (r *ReconcileWebPushVariant) manageSuccess(instance *pushv1alpha1.WebPushVariant) (reconcile.Result, error) {
+	instance.Status.Message = ""
+	instance.Status.Phase = pushv1alpha1.PhaseReconciling
+
+	err := r.client.Status().Update(context.TODO(), instance)
+	if err != nil {
+		log.Error(err, "Unable to update status")
+		return reconcile.Result{
+			RequeueAfter: requeueErrorDelay,
+			Requeue:      true,
+		}, nil
+	}
+
+	log.Info("Reconcile successful", "WebPushVariant.Namespace", instance.Namespace, "WebPushVariant.Name", instance.Name)
+	return reconcile.Result{}, nil
+}
+
+// generatedCredentials returns the owned Secret with the generated
+// VAPID key pair of the variant, creating it with a new key pair if it
+// doesn't exist yet
+func (r *ReconcileWebPushVariant) generatedCredentials(cr *pushv1alpha1.WebPushVariant) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: credentialsSecretName(cr), Namespace: cr.Namespace}, secret)
+	if err == nil {
+		return secret, nil
+	}
+	if !errors.IsNotFound(err) {
+		return nil, err
+	}
+
+	publicKey, privateKey, err := generateVAPIDKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	secret = newCredentialsSecret(cr, publicKey, privateKey)
+
+	// Set WebPushVariant instance as the owner and controller
+	if err := controllerutil.SetControllerReference(cr, secret, r.scheme); err != nil {
+		return nil, err
+	}
+
+	log.Info("Creating a new Secret with a generated VAPID key pair", "Secret.Namespace", secret.Namespace, "Secret.Name", secret.Name)
+	if err := r.client.Create(context.TODO(), secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+func credentialsSecretName(cr *pushv1alpha1.WebPushVariant) string {
+	return fmt.Sprintf("%s-webpushvariant", cr.Name)
+}
+
+func newCredentialsSecret(cr *pushv1alpha1.WebPushVariant, publicKey string, privateKey string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      credentialsSecretName(cr),
+			Namespace: cr.Namespace,
+			Labels: map[string]string{
+				"webpushvariant": cr.Name,
+			},
+		},
+		Data: map[string][]byte{
+			"publicKey":  []byte(publicKey),
+			"privateKey": []byte(privateKey),
+		},
+	}
+}
+
+// newWebPushVariant builds the UPS representation of the variant from
+// the CR and its credentials Secret
+func newWebPushVariant(cr *pushv1alpha1.WebPushVariant, credentials *corev1.Secret) (unifiedpush.WebPushVariant, error) {
+	publicKey, ok := credentials.Data["publicKey"]
+	if !ok {
+		return unifiedpush.WebPushVariant{}, fmt.Errorf("Secret %s has no publicKey", credentials.Name)
+	}
+	privateKey, ok := credentials.Data["privateKey"]
+	if !ok {
+		return unifiedpush.WebPushVariant{}, fmt.Errorf("Secret %s has no privateKey", credentials.Name)
+	}
+
+	return unifiedpush.WebPushVariant{
+		Variant: unifiedpush.Variant{
+			Name:        util.NameInUPS(cr, cr.Spec.Name),
+			Description: cr.Spec.Description,
+		},
+		PublicKey:  string(publicKey),
+		PrivateKey: string(privateKey),
+		Alias:      cr.Spec.Alias,
+	}, nil
+}
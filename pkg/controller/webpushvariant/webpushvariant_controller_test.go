@@ -0,0 +1,228 @@
+package webpushvariant
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	pushv1alpha1 "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1"
+	"github.com/aerogear/unifiedpush-operator/pkg/controller/testutil"
+	"github.com/aerogear/unifiedpush-operator/pkg/unifiedpush"
+	"github.com/aerogear/unifiedpush-operator/pkg/unifiedpush/fake"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+//buildReconcileWithFakeClientWithMocks return reconcile with fake client, schemes and mock objects
+func buildReconcileWithFakeClientWithMocks(objs []runtime.Object, server *fake.Server, t *testing.T) *ReconcileWebPushVariant {
+	return &ReconcileWebPushVariant{
+		client:         testutil.NewFakeClient(t, objs...),
+		scheme:         scheme.Scheme,
+		recorder:       record.NewFakeRecorder(10),
+		unifiedpushURL: testutil.UnifiedPushURL(server),
+	}
+}
+
+func TestReconcileWebPushVariant_GeneratesKeys(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+
+	upsApp, err := unifiedpush.NewClient(server.URL).CreateApplication(unifiedpush.PushApplication{Name: "example-pushapplication"})
+	if err != nil {
+		t.Fatalf("create application: (%v)", err)
+	}
+
+	variant := webPushVariant()
+	r := buildReconcileWithFakeClientWithMocks([]runtime.Object{
+		testutil.ReadyUnifiedPushServer(),
+		testutil.PushApplication(upsApp.PushApplicationID),
+		variant,
+	}, server, t)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: variant.Name, Namespace: variant.Namespace}}
+
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+
+	found := &pushv1alpha1.WebPushVariant{}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, found); err != nil {
+		t.Fatalf("get WebPushVariant: (%v)", err)
+	}
+	if found.Status.Phase != pushv1alpha1.PhaseReconciling {
+		t.Fatalf("expected phase %s, got %s: %s", pushv1alpha1.PhaseReconciling, found.Status.Phase, found.Status.Message)
+	}
+	if found.Status.CredentialsSecret != credentialsSecretName(variant) {
+		t.Errorf("expected credentials Secret %s, got %s", credentialsSecretName(variant), found.Status.CredentialsSecret)
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: found.Status.CredentialsSecret, Namespace: variant.Namespace}, secret); err != nil {
+		t.Fatalf("get Secret: (%v)", err)
+	}
+	if len(secret.OwnerReferences) != 1 || secret.OwnerReferences[0].Name != variant.Name {
+		t.Errorf("expected Secret to be owned by the WebPushVariant, got %v", secret.OwnerReferences)
+	}
+	if string(secret.Data["publicKey"]) != found.Status.PublicKey {
+		t.Errorf("expected public key %s in status, got %s", secret.Data["publicKey"], found.Status.PublicKey)
+	}
+	publicKey, err := base64.RawURLEncoding.DecodeString(found.Status.PublicKey)
+	if err != nil || len(publicKey) != 65 || publicKey[0] != 4 {
+		t.Errorf("expected an uncompressed P-256 public key, got %v (%v)", publicKey, err)
+	}
+	privateKey, err := base64.RawURLEncoding.DecodeString(string(secret.Data["privateKey"]))
+	if err != nil || len(privateKey) != 32 {
+		t.Errorf("expected a 32 byte private key, got %v (%v)", privateKey, err)
+	}
+
+	upsVariant := &unifiedpush.WebPushVariant{}
+	if !server.Variant(found.Status.VariantId, upsVariant) {
+		t.Fatalf("variant %s was not created in UPS", found.Status.VariantId)
+	}
+	if upsVariant.PublicKey != found.Status.PublicKey || upsVariant.PrivateKey != string(secret.Data["privateKey"]) || upsVariant.Alias != variant.Spec.Alias {
+		t.Errorf("unexpected variant in UPS: %+v", upsVariant)
+	}
+
+	// The same keys are used on the next reconcile
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	upsVariant = &unifiedpush.WebPushVariant{}
+	server.Variant(found.Status.VariantId, upsVariant)
+	if upsVariant.PublicKey != found.Status.PublicKey {
+		t.Errorf("expected public key to stay the same, got %s", upsVariant.PublicKey)
+	}
+
+	// Delete
+	if err := r.client.Get(context.TODO(), req.NamespacedName, found); err != nil {
+		t.Fatalf("get WebPushVariant: (%v)", err)
+	}
+	now := metav1.Now()
+	found.DeletionTimestamp = &now
+	if err := r.client.Update(context.TODO(), found); err != nil {
+		t.Fatalf("update WebPushVariant: (%v)", err)
+	}
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	if ids := server.VariantIDs(upsApp.PushApplicationID, variantType); len(ids) != 0 {
+		t.Errorf("expected variant to be deleted from UPS, got %v", ids)
+	}
+}
+
+func TestReconcileWebPushVariant_SuppliedKeys(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+
+	upsApp, err := unifiedpush.NewClient(server.URL).CreateApplication(unifiedpush.PushApplication{Name: "example-pushapplication"})
+	if err != nil {
+		t.Fatalf("create application: (%v)", err)
+	}
+
+	credentials := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "example-vapid-keys",
+			Namespace: "unifiedpush",
+		},
+		Data: map[string][]byte{
+			"publicKey":  []byte("public-key"),
+			"privateKey": []byte("private-key"),
+		},
+	}
+	variant := webPushVariant()
+	variant.Spec.CredentialsSecret = credentials.Name
+	r := buildReconcileWithFakeClientWithMocks([]runtime.Object{
+		testutil.ReadyUnifiedPushServer(),
+		testutil.PushApplication(upsApp.PushApplicationID),
+		credentials,
+		variant,
+	}, server, t)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: variant.Name, Namespace: variant.Namespace}}
+
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+
+	found := &pushv1alpha1.WebPushVariant{}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, found); err != nil {
+		t.Fatalf("get WebPushVariant: (%v)", err)
+	}
+	if found.Status.PublicKey != "public-key" || found.Status.CredentialsSecret != credentials.Name {
+		t.Errorf("unexpected status: %+v", found.Status)
+	}
+
+	generated := &corev1.Secret{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: credentialsSecretName(variant), Namespace: variant.Namespace}, generated); err == nil {
+		t.Errorf("expected no key pair to be generated")
+	}
+
+	upsVariant := &unifiedpush.WebPushVariant{}
+	if !server.Variant(found.Status.VariantId, upsVariant) {
+		t.Fatalf("variant %s was not created in UPS", found.Status.VariantId)
+	}
+	if upsVariant.PublicKey != "public-key" || upsVariant.PrivateKey != "private-key" {
+		t.Errorf("unexpected variant in UPS: %+v", upsVariant)
+	}
+
+	// UPS may leave the private key out of its responses, which
+	// doesn't make it look like it has changed
+	server.HideVariantFields("privateKey")
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	if updates := server.VariantUpdates(found.Status.VariantId); updates != 0 {
+		t.Errorf("expected the variant not to be updated while the private key is the same, got %d updates", updates)
+	}
+
+	credentials.Data["privateKey"] = []byte("new-private-key")
+	if err := r.client.Update(context.TODO(), credentials); err != nil {
+		t.Fatalf("update Secret: (%v)", err)
+	}
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	upsVariant = &unifiedpush.WebPushVariant{}
+	server.Variant(found.Status.VariantId, upsVariant)
+	if upsVariant.PrivateKey != "new-private-key" || server.VariantUpdates(found.Status.VariantId) != 1 {
+		t.Errorf("expected the new private key to be sent to UPS once, got %+v", upsVariant)
+	}
+}
+
+func TestReconcileWebPushVariant_PushApplicationChanged(t *testing.T) {
+	testutil.TestPushApplicationChanged(t, testutil.VariantController{
+		VariantType: variantType,
+		Variant:     webPushVariant(),
+		NewReconciler: func(c client.Client, server *fake.Server) reconcile.Reconciler {
+			return &ReconcileWebPushVariant{
+				client:         c,
+				scheme:         scheme.Scheme,
+				recorder:       record.NewFakeRecorder(10),
+				unifiedpushURL: testutil.UnifiedPushURL(server),
+			}
+		},
+		Status: func(o runtime.Object) (string, string) {
+			variant := o.(*pushv1alpha1.WebPushVariant)
+			return variant.Status.PushApplicationId, variant.Status.VariantId
+		},
+	})
+}
+
+func webPushVariant() *pushv1alpha1.WebPushVariant {
+	return &pushv1alpha1.WebPushVariant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "example-webpushvariant",
+			Namespace: "unifiedpush",
+		},
+		Spec: pushv1alpha1.WebPushVariantSpec{
+			Description:     "An example Web Push variant",
+			PushApplication: "example-pushapplication",
+			Alias:           "mailto:admin@example.com",
+		},
+	}
+}
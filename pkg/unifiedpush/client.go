@@ -0,0 +1,176 @@
+package unifiedpush
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	requestTimeout = 30 * time.Second
+	pageSize       = 50
+)
+
+// UnifiedpushClient is a small client for the UnifiedPush Server
+// REST API. Url is the base URL of the server, e.g.
+// "http://example-unifiedpush.unifiedpush.svc".
+type UnifiedpushClient struct {
+	Url        string
+	HttpClient *http.Client
+}
+
+// NewClient returns a UnifiedpushClient for the server at url
+func NewClient(url string) *UnifiedpushClient {
+	return &UnifiedpushClient{
+		Url:        url,
+		HttpClient: &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// PushApplication is the representation of a push application in
+// the UnifiedPush Server REST API
+type PushApplication struct {
+	PushApplicationID string `json:"pushApplicationID,omitempty"`
+	MasterSecret      string `json:"masterSecret,omitempty"`
+	Name              string `json:"name"`
+	Description       string `json:"description"`
+}
+
+// PushApplicationWithVariants is an application as listed by the
+// server, along with all of its variants. The variants are kept as
+// raw JSON, since their fields depend on their type; see VariantType.
+type PushApplicationWithVariants struct {
+	PushApplication
+	Variants []json.RawMessage `json:"variants"`
+}
+
+// ListApplications fetches all of the applications in the server,
+// along with their variants, a page at a time
+func (c *UnifiedpushClient) ListApplications() ([]PushApplicationWithVariants, error) {
+	apps := []PushApplicationWithVariants{}
+	for page := 0; ; page++ {
+		pageApps := []PushApplicationWithVariants{}
+		_, err := c.do(http.MethodGet, fmt.Sprintf("/rest/applications?page=%d&per_page=%d", page, pageSize), nil, &pageApps)
+		if err != nil {
+			return nil, errors.Wrap(err, "error listing push applications")
+		}
+		apps = append(apps, pageApps...)
+		if len(pageApps) < pageSize {
+			return apps, nil
+		}
+	}
+}
+
+// GetApplication fetches the application with the given id. It
+// returns nil if there is no such application.
+func (c *UnifiedpushClient) GetApplication(pushApplicationID string) (*PushApplication, error) {
+	app := &PushApplication{}
+	found, err := c.do(http.MethodGet, fmt.Sprintf("/rest/applications/%s", pushApplicationID), nil, app)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting push application")
+	}
+	if !found {
+		return nil, nil
+	}
+	return app, nil
+}
+
+// CreateApplication creates a new application and returns it as
+// stored in the server, including its id and master secret
+func (c *UnifiedpushClient) CreateApplication(app PushApplication) (*PushApplication, error) {
+	created := &PushApplication{}
+	if _, err := c.do(http.MethodPost, "/rest/applications", app, created); err != nil {
+		return nil, errors.Wrap(err, "error creating push application")
+	}
+	return created, nil
+}
+
+// UpdateApplication updates the name and description of an existing
+// application
+func (c *UnifiedpushClient) UpdateApplication(app PushApplication) error {
+	if _, err := c.do(http.MethodPut, fmt.Sprintf("/rest/applications/%s", app.PushApplicationID), app, nil); err != nil {
+		return errors.Wrap(err, "error updating push application")
+	}
+	return nil
+}
+
+// DeleteApplication deletes the application with the given id. It
+// is not an error if the application is already gone.
+func (c *UnifiedpushClient) DeleteApplication(pushApplicationID string) error {
+	if _, err := c.do(http.MethodDelete, fmt.Sprintf("/rest/applications/%s", pushApplicationID), nil, nil); err != nil {
+		return errors.Wrap(err, "error deleting push application")
+	}
+	return nil
+}
+
+// basicAuth holds the credentials that some endpoints, like the
+// sender, require instead of an admin login
+type basicAuth struct {
+	username string
+	password string
+}
+
+// do sends a request with an optional JSON body and decodes the JSON
+// response into out, if given. The response is stored as is, without
+// being decoded, when out is a *[]byte. It returns false if the server
+// responded with 404.
+func (c *UnifiedpushClient) do(method string, path string, in interface{}, out interface{}) (bool, error) {
+	return c.doWithAuth(nil, method, path, in, out)
+}
+
+// doWithAuth is like do, but authenticates with basic auth if auth is
+// not nil
+func (c *UnifiedpushClient) doWithAuth(auth *basicAuth, method string, path string, in interface{}, out interface{}) (bool, error) {
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return false, err
+		}
+		body = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequest(method, c.Url+path, body)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if auth != nil {
+		req.SetBasicAuth(auth.username, auth.password)
+	}
+
+	resp, err := c.HttpClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return false, errors.Wrap(err, "error reading response")
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return false, fmt.Errorf("unexpected response from UnifiedPush Server: %s %s: %d %s", method, path, resp.StatusCode, string(b))
+	}
+
+	if raw, ok := out.(*[]byte); ok {
+		*raw = b
+	} else if out != nil && len(b) > 0 {
+		if err := json.Unmarshal(b, out); err != nil {
+			return false, errors.Wrap(err, "error decoding response")
+		}
+	}
+	return true, nil
+}
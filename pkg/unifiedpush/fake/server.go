@@ -0,0 +1,369 @@
+// Package fake provides an in-memory UnifiedPush Server REST API that
+// can be used to test controllers without a running server.
+package fake
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/aerogear/unifiedpush-operator/pkg/unifiedpush"
+	"github.com/google/uuid"
+)
+
+// Server is a fake UnifiedPush Server. Use URL as the base URL of a
+// unifiedpush.UnifiedpushClient, and Close it when done.
+type Server struct {
+	*httptest.Server
+
+	mu           sync.Mutex
+	applications map[string]*unifiedpush.PushApplication
+	variants     map[string]*variant
+	messages     []SentMessage
+	devices      map[string][]json.RawMessage
+
+	// sendResponse replaces the JSON body of the responses of the
+	// sender endpoint when it's set
+	sendResponse *string
+
+	// hiddenFields are left out of the variants that are returned,
+	// the way UPS may leave out key material
+	hiddenFields []string
+	updates      map[string]int
+}
+
+// SentMessage is a message that was accepted by the sender endpoint
+type SentMessage struct {
+	PushApplicationID string
+	Message           unifiedpush.PushMessage
+}
+
+// variant is a variant of any type, kept as decoded JSON so that the
+// type specific fields survive a round trip
+type variant struct {
+	pushApplicationID string
+	variantType       string
+	fields            map[string]interface{}
+}
+
+// NewServer starts a new fake UnifiedPush Server with no applications
+func NewServer() *Server {
+	s := &Server{
+		applications: map[string]*unifiedpush.PushApplication{},
+		variants:     map[string]*variant{},
+		devices:      map[string][]json.RawMessage{},
+		updates:      map[string]int{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// Application returns a copy of the application with the given id, or
+// nil if there is none
+func (s *Server) Application(id string) *unifiedpush.PushApplication {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	app, ok := s.applications[id]
+	if !ok {
+		return nil
+	}
+	a := *app
+	return &a
+}
+
+// Applications returns a copy of all of the applications in the server
+func (s *Server) Applications() []unifiedpush.PushApplication {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	apps := []unifiedpush.PushApplication{}
+	for _, app := range s.applications {
+		apps = append(apps, *app)
+	}
+	return apps
+}
+
+// Variant decodes the variant with the given id into out, which
+// should be a pointer to one of the unifiedpush variant types. It
+// returns false if there is no such variant.
+func (s *Server) Variant(id string, out interface{}) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.variants[id]
+	if !ok {
+		return false
+	}
+	b, _ := json.Marshal(v.fields)
+	return json.Unmarshal(b, out) == nil
+}
+
+// VariantIDs returns the ids of all of the variants of the given type
+// in an application
+func (s *Server) VariantIDs(pushApplicationID string, variantType string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids := []string{}
+	for id, v := range s.variants {
+		if v.pushApplicationID == pushApplicationID && v.variantType == variantType {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// Messages returns a copy of all of the messages sent so far
+func (s *Server) Messages() []SentMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]SentMessage{}, s.messages...)
+}
+
+// SetSendResponse makes the sender endpoint accept messages with the
+// given body instead of JSON, e.g. an empty or plain text one
+func (s *Server) SetSendResponse(body string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sendResponse = &body
+}
+
+// HideVariantFields leaves the given fields out of the variants that
+// the server returns, e.g. privateKey
+func (s *Server) HideVariantFields(fields ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hiddenFields = fields
+}
+
+// VariantUpdates returns the number of times that the variant with the
+// given id has been updated
+func (s *Server) VariantUpdates(id string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.updates[id]
+}
+
+// Devices returns a copy of the installations imported into a variant
+func (s *Server) Devices(variantID string) []json.RawMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]json.RawMessage{}, s.devices[variantID]...)
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/rest"), "/"), "/")
+	if len(path) == 1 && path[0] == "sender" && r.Method == http.MethodPost {
+		s.send(w, r)
+		return
+	}
+	if strings.Join(path, "/") == "registry/device/importer" && r.Method == http.MethodPost {
+		s.importDevices(w, r)
+		return
+	}
+	if len(path) == 0 || path[0] != "applications" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	switch {
+	case len(path) == 1 && r.Method == http.MethodGet:
+		s.listApplications(w, r)
+	case len(path) == 1 && r.Method == http.MethodPost:
+		app := &unifiedpush.PushApplication{}
+		if !decode(w, r, app) {
+			return
+		}
+		app.PushApplicationID = newID()
+		app.MasterSecret = newID()
+		s.applications[app.PushApplicationID] = app
+		writeJSON(w, http.StatusCreated, app)
+	case len(path) == 2:
+		app, ok := s.applications[path[1]]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, app)
+		case http.MethodPut:
+			update := &unifiedpush.PushApplication{}
+			if !decode(w, r, update) {
+				return
+			}
+			app.Name = update.Name
+			app.Description = update.Description
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodDelete:
+			delete(s.applications, path[1])
+			for id, v := range s.variants {
+				if v.pushApplicationID == path[1] {
+					delete(s.variants, id)
+				}
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	case len(path) == 3 && r.Method == http.MethodPost:
+		if _, ok := s.applications[path[1]]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fields := map[string]interface{}{}
+		if !decode(w, r, &fields) {
+			return
+		}
+		fields["variantID"] = newID()
+		fields["secret"] = newID()
+		fields["type"] = path[2]
+		s.variants[fields["variantID"].(string)] = &variant{
+			pushApplicationID: path[1],
+			variantType:       path[2],
+			fields:            fields,
+		}
+		writeJSON(w, http.StatusCreated, fields)
+	case len(path) == 4:
+		v, ok := s.variants[path[3]]
+		if !ok || v.pushApplicationID != path[1] || v.variantType != path[2] {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			fields := map[string]interface{}{}
+			for key, value := range v.fields {
+				fields[key] = value
+			}
+			for _, key := range s.hiddenFields {
+				delete(fields, key)
+			}
+			writeJSON(w, http.StatusOK, fields)
+		case http.MethodPut:
+			fields := map[string]interface{}{}
+			if !decode(w, r, &fields) {
+				return
+			}
+			fields["variantID"] = v.fields["variantID"]
+			fields["secret"] = v.fields["secret"]
+			fields["type"] = v.fields["type"]
+			v.fields = fields
+			s.updates[path[3]]++
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodDelete:
+			delete(s.variants, path[3])
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// listApplications returns a page of applications, sorted by id, with
+// all of their variants
+func (s *Server) listApplications(w http.ResponseWriter, r *http.Request) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	perPage, err := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if err != nil || perPage <= 0 {
+		perPage = 25
+	}
+
+	ids := []string{}
+	for id := range s.applications {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	apps := []map[string]interface{}{}
+	for i := page * perPage; i < len(ids) && i < (page+1)*perPage; i++ {
+		app := s.applications[ids[i]]
+		variants := []map[string]interface{}{}
+		for _, v := range s.variants {
+			if v.pushApplicationID == app.PushApplicationID {
+				variants = append(variants, v.fields)
+			}
+		}
+		apps = append(apps, map[string]interface{}{
+			"pushApplicationID": app.PushApplicationID,
+			"masterSecret":      app.MasterSecret,
+			"name":              app.Name,
+			"description":       app.Description,
+			"variants":          variants,
+		})
+	}
+	writeJSON(w, http.StatusOK, apps)
+}
+
+// send handles the sender endpoint, which authenticates with the
+// application id and master secret
+func (s *Server) send(w http.ResponseWriter, r *http.Request) {
+	id, secret, ok := r.BasicAuth()
+	app, found := s.applications[id]
+	if !ok || !found || app.MasterSecret != secret {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	msg := unifiedpush.PushMessage{}
+	if !decode(w, r, &msg) {
+		return
+	}
+	s.messages = append(s.messages, SentMessage{PushApplicationID: id, Message: msg})
+	if s.sendResponse != nil {
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(*s.sendResponse))
+		return
+	}
+	writeJSON(w, http.StatusAccepted, map[string]string{"pushMessageId": newID()})
+}
+
+// importDevices handles the registry importer endpoint, which
+// authenticates with the variant id and secret
+func (s *Server) importDevices(w http.ResponseWriter, r *http.Request) {
+	id, secret, ok := r.BasicAuth()
+	v, found := s.variants[id]
+	if !ok || !found || v.fields["secret"] != secret {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	installations := []json.RawMessage{}
+	if err := json.NewDecoder(file).Decode(&installations); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	s.devices[id] = append(s.devices[id], installations...)
+	w.WriteHeader(http.StatusOK)
+}
+
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func newID() string {
+	return uuid.New().String()
+}
@@ -0,0 +1,71 @@
+package unifiedpush
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// ImportRejectedError is returned by ImportDevices when UPS responds
+// with a 4xx, i.e. when sending the same batch again won't help
+type ImportRejectedError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *ImportRejectedError) Error() string {
+	return fmt.Sprintf("UnifiedPush Server rejected the devices: %d %s", e.StatusCode, e.Body)
+}
+
+// ImportDevices registers a batch of installations with a variant,
+// through the registry importer endpoint, authenticating with the
+// variant id and secret. Each installation is passed through as is,
+// so any field that UPS knows about (deviceToken, alias, categories,
+// ...) can be used.
+func (c *UnifiedpushClient) ImportDevices(variantID string, secret string, installations []json.RawMessage) error {
+	file, err := json.Marshal(installations)
+	if err != nil {
+		return err
+	}
+
+	body := &bytes.Buffer{}
+	form := multipart.NewWriter(body)
+	part, err := form.CreateFormFile("file", "devices.json")
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(file); err != nil {
+		return err
+	}
+	if err := form.Close(); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.Url+"/rest/registry/device/importer", body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	req.SetBasicAuth(variantID, secret)
+
+	resp, err := c.HttpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "error importing devices")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 {
+		b, _ := ioutil.ReadAll(resp.Body)
+		return &ImportRejectedError{StatusCode: resp.StatusCode, Body: string(b)}
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		b, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected response from UnifiedPush Server: importing devices: %d %s", resp.StatusCode, string(b))
+	}
+	return nil
+}
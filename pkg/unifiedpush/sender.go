@@ -0,0 +1,49 @@
+package unifiedpush
+
+import (
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// PushMessage is the body of a request to the sender endpoint of the
+// UnifiedPush Server REST API
+type PushMessage struct {
+	Message  Message  `json:"message"`
+	Criteria Criteria `json:"criteria,omitempty"`
+	TTL      int      `json:"ttl,omitempty"`
+}
+
+// Message is the notification to be sent to the devices
+type Message struct {
+	Alert    string            `json:"alert,omitempty"`
+	Sound    string            `json:"sound,omitempty"`
+	Badge    int               `json:"badge,omitempty"`
+	UserData map[string]string `json:"user-data,omitempty"`
+}
+
+// Criteria selects the devices that a message will be sent to. A
+// message with no criteria is sent to every device of the
+// application.
+type Criteria struct {
+	Aliases    []string `json:"alias,omitempty"`
+	Categories []string `json:"categories,omitempty"`
+	Variants   []string `json:"variants,omitempty"`
+}
+
+// SendMessage sends a message to the devices of an application,
+// authenticating with its id and master secret. It returns the body
+// of the response as is, so that it can be recorded. The message has
+// been accepted once the server responds with any 2xx status, whatever
+// the body, so that it's never sent twice.
+func (c *UnifiedpushClient) SendMessage(pushApplicationID string, masterSecret string, msg PushMessage) (string, error) {
+	response := []byte{}
+	found, err := c.doWithAuth(&basicAuth{username: pushApplicationID, password: masterSecret}, http.MethodPost, "/rest/sender", msg, &response)
+	if err != nil {
+		return "", errors.Wrap(err, "error sending push message")
+	}
+	if !found {
+		return "", errors.New("error sending push message: sender endpoint not found")
+	}
+	return string(response), nil
+}
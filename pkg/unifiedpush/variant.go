@@ -0,0 +1,184 @@
+package unifiedpush
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// Variant holds the fields that are common to all variant types in
+// the UnifiedPush Server REST API
+type Variant struct {
+	VariantID   string `json:"variantID,omitempty"`
+	Secret      string `json:"secret,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// VariantType returns the type of a variant in the raw JSON returned
+// by ListApplications, e.g. "android", "ios_token" or "web_push"
+func VariantType(variant json.RawMessage) string {
+	v := struct {
+		Type string `json:"type"`
+	}{}
+	if err := json.Unmarshal(variant, &v); err != nil {
+		return ""
+	}
+	return v.Type
+}
+
+// AndroidVariant is an FCM variant. GoogleKey is the FCM server key
+// and ProjectNumber is the sender id.
+type AndroidVariant struct {
+	Variant
+	GoogleKey     string `json:"googleKey"`
+	ProjectNumber string `json:"projectNumber"`
+}
+
+// GetAndroidVariant fetches an Android variant of an application. It
+// returns nil if there is no such variant.
+func (c *UnifiedpushClient) GetAndroidVariant(pushApplicationID string, variantID string) (*AndroidVariant, error) {
+	variant := &AndroidVariant{}
+	found, err := c.getVariant(pushApplicationID, "android", variantID, variant)
+	if err != nil || !found {
+		return nil, err
+	}
+	return variant, nil
+}
+
+// CreateAndroidVariant creates a new Android variant in an
+// application and returns it as stored in the server, including its
+// id and secret
+func (c *UnifiedpushClient) CreateAndroidVariant(pushApplicationID string, variant AndroidVariant) (*AndroidVariant, error) {
+	created := &AndroidVariant{}
+	if err := c.createVariant(pushApplicationID, "android", variant, created); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// UpdateAndroidVariant updates an existing Android variant
+func (c *UnifiedpushClient) UpdateAndroidVariant(pushApplicationID string, variant AndroidVariant) error {
+	return c.updateVariant(pushApplicationID, "android", variant.VariantID, variant)
+}
+
+// DeleteVariant deletes a variant of the given type ("android",
+// "ios_token", ...) from an application. It is not an error if the
+// variant is already gone.
+func (c *UnifiedpushClient) DeleteVariant(pushApplicationID string, variantType string, variantID string) error {
+	if _, err := c.do(http.MethodDelete, variantPath(pushApplicationID, variantType, variantID), nil, nil); err != nil {
+		return errors.Wrapf(err, "error deleting %s variant", variantType)
+	}
+	return nil
+}
+
+func (c *UnifiedpushClient) getVariant(pushApplicationID string, variantType string, variantID string, out interface{}) (bool, error) {
+	found, err := c.do(http.MethodGet, variantPath(pushApplicationID, variantType, variantID), nil, out)
+	if err != nil {
+		return false, errors.Wrapf(err, "error getting %s variant", variantType)
+	}
+	return found, nil
+}
+
+func (c *UnifiedpushClient) createVariant(pushApplicationID string, variantType string, in interface{}, out interface{}) error {
+	found, err := c.do(http.MethodPost, fmt.Sprintf("/rest/applications/%s/%s", pushApplicationID, variantType), in, out)
+	if err != nil {
+		return errors.Wrapf(err, "error creating %s variant", variantType)
+	}
+	if !found {
+		return fmt.Errorf("error creating %s variant: push application %s not found", variantType, pushApplicationID)
+	}
+	return nil
+}
+
+func (c *UnifiedpushClient) updateVariant(pushApplicationID string, variantType string, variantID string, in interface{}) error {
+	found, err := c.do(http.MethodPut, variantPath(pushApplicationID, variantType, variantID), in, nil)
+	if err != nil {
+		return errors.Wrapf(err, "error updating %s variant", variantType)
+	}
+	if !found {
+		return fmt.Errorf("error updating %s variant: variant %s not found", variantType, variantID)
+	}
+	return nil
+}
+
+func variantPath(pushApplicationID string, variantType string, variantID string) string {
+	return fmt.Sprintf("/rest/applications/%s/%s/%s", pushApplicationID, variantType, variantID)
+}
+
+// IOSTokenVariant is an APNs variant that uses token based
+// authentication with a .p8 private key
+type IOSTokenVariant struct {
+	Variant
+	Production bool   `json:"production"`
+	PrivateKey string `json:"privateKey"`
+	KeyID      string `json:"keyId"`
+	TeamID     string `json:"teamId"`
+	BundleID   string `json:"bundleId"`
+}
+
+// GetIOSTokenVariant fetches an iOS token variant of an application.
+// It returns nil if there is no such variant.
+func (c *UnifiedpushClient) GetIOSTokenVariant(pushApplicationID string, variantID string) (*IOSTokenVariant, error) {
+	variant := &IOSTokenVariant{}
+	found, err := c.getVariant(pushApplicationID, "ios_token", variantID, variant)
+	if err != nil || !found {
+		return nil, err
+	}
+	return variant, nil
+}
+
+// CreateIOSTokenVariant creates a new iOS token variant in an
+// application and returns it as stored in the server, including its
+// id and secret
+func (c *UnifiedpushClient) CreateIOSTokenVariant(pushApplicationID string, variant IOSTokenVariant) (*IOSTokenVariant, error) {
+	created := &IOSTokenVariant{}
+	if err := c.createVariant(pushApplicationID, "ios_token", variant, created); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// UpdateIOSTokenVariant updates an existing iOS token variant
+func (c *UnifiedpushClient) UpdateIOSTokenVariant(pushApplicationID string, variant IOSTokenVariant) error {
+	return c.updateVariant(pushApplicationID, "ios_token", variant.VariantID, variant)
+}
+
+// WebPushVariant is a Web Push variant. PublicKey and PrivateKey are
+// the VAPID key pair, and Alias is a contact URL or "mailto:" address
+// for the push services.
+type WebPushVariant struct {
+	Variant
+	PublicKey  string `json:"publicKey"`
+	PrivateKey string `json:"privateKey"`
+	Alias      string `json:"alias"`
+}
+
+// GetWebPushVariant fetches a Web Push variant of an application. It
+// returns nil if there is no such variant.
+func (c *UnifiedpushClient) GetWebPushVariant(pushApplicationID string, variantID string) (*WebPushVariant, error) {
+	variant := &WebPushVariant{}
+	found, err := c.getVariant(pushApplicationID, "web_push", variantID, variant)
+	if err != nil || !found {
+		return nil, err
+	}
+	return variant, nil
+}
+
+// CreateWebPushVariant creates a new Web Push variant in an
+// application and returns it as stored in the server, including its
+// id and secret
+func (c *UnifiedpushClient) CreateWebPushVariant(pushApplicationID string, variant WebPushVariant) (*WebPushVariant, error) {
+	created := &WebPushVariant{}
+	if err := c.createVariant(pushApplicationID, "web_push", variant, created); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// UpdateWebPushVariant updates an existing Web Push variant
+func (c *UnifiedpushClient) UpdateWebPushVariant(pushApplicationID string, variant WebPushVariant) error {
+	return c.updateVariant(pushApplicationID, "web_push", variant.VariantID, variant)
+}
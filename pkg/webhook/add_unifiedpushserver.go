@@ -0,0 +1,10 @@
+package webhook
+
+import (
+	"github.com/aerogear/unifiedpush-operator/pkg/webhook/unifiedpushserver"
+)
+
+func init() {
+	// AddToManagerFuncs is a list of functions to create webhooks and add them to a manager.
+	AddToManagerFuncs = append(AddToManagerFuncs, unifiedpushserver.NewDefaultingWebhook, unifiedpushserver.NewValidatingWebhook)
+}
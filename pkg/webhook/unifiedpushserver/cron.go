@@ -0,0 +1,115 @@
+package unifiedpushserver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronField is the range of values, and their names if any, that a
+// field of a cron schedule accepts
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}},
+	{name: "day of week", min: 0, max: 6, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}},
+}
+
+var cronDescriptors = map[string]bool{
+	"@yearly":   true,
+	"@annually": true,
+	"@monthly":  true,
+	"@weekly":   true,
+	"@daily":    true,
+	"@midnight": true,
+	"@hourly":   true,
+}
+
+// validateSchedule checks that a schedule can be parsed by the
+// CronJob controller, which accepts the standard 5 field format and
+// the predefined @ descriptors
+func validateSchedule(schedule string) error {
+	schedule = strings.TrimSpace(schedule)
+	if strings.HasPrefix(schedule, "@") {
+		if cronDescriptors[schedule] {
+			return nil
+		}
+		if strings.HasPrefix(schedule, "@every ") {
+			_, err := time.ParseDuration(strings.TrimPrefix(schedule, "@every "))
+			return err
+		}
+		return fmt.Errorf("unrecognized descriptor %q", schedule)
+	}
+
+	fields := strings.Fields(schedule)
+	if len(fields) != len(cronFields) {
+		return fmt.Errorf("expected %d fields, found %d", len(cronFields), len(fields))
+	}
+	for i, f := range fields {
+		for _, expr := range strings.Split(f, ",") {
+			if err := cronFields[i].validate(expr); err != nil {
+				return fmt.Errorf("%s: %v", cronFields[i].name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validate checks a single range of a field, i.e. *, ?, a value or a
+// range of values, optionally followed by a step
+func (f cronField) validate(expr string) error {
+	rangeExpr, step := expr, ""
+	if i := strings.Index(expr, "/"); i >= 0 {
+		rangeExpr, step = expr[:i], expr[i+1:]
+		if n, err := strconv.Atoi(step); err != nil || n <= 0 {
+			return fmt.Errorf("invalid step %q", step)
+		}
+	}
+	if rangeExpr == "*" || rangeExpr == "?" {
+		return nil
+	}
+
+	bounds := strings.Split(rangeExpr, "-")
+	if len(bounds) > 2 {
+		return fmt.Errorf("invalid range %q", rangeExpr)
+	}
+	values := make([]int, len(bounds))
+	for i, b := range bounds {
+		value, err := f.value(b)
+		if err != nil {
+			return err
+		}
+		values[i] = value
+	}
+	if len(values) == 2 && values[0] > values[1] {
+		return fmt.Errorf("beginning of range %q is after its end", rangeExpr)
+	}
+	return nil
+}
+
+func (f cronField) value(s string) (int, error) {
+	if value, ok := f.names[strings.ToLower(s)]; ok {
+		return value, nil
+	}
+	value, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	if value < f.min || value > f.max {
+		return 0, fmt.Errorf("value %d out of range [%d, %d]", value, f.min, f.max)
+	}
+	return value, nil
+}
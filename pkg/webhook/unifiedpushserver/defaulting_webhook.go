@@ -0,0 +1,70 @@
+package unifiedpushserver
+
+import (
+	"context"
+	"net/http"
+
+	pushv1alpha1 "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1"
+
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	atypes "sigs.k8s.io/controller-runtime/pkg/webhook/admission/types"
+	webhooktypes "sigs.k8s.io/controller-runtime/pkg/webhook/types"
+)
+
+// DefaultingWebhookPath is the path that the defaulting webhook is
+// served on, which must match the MutatingWebhookConfiguration
+const DefaultingWebhookPath = "/mutate-push-aerogear-org-v1alpha1-unifiedpushserver"
+
+// NewDefaultingWebhook returns the webhook that writes the operator
+// defaults into the spec of new UnifiedPushServers
+func NewDefaultingWebhook() *admission.Webhook {
+	// Matches deploy/webhook.yaml, where requests are let through while
+	// the webhook isn't served
+	failurePolicy := admissionregistrationv1beta1.Ignore
+	return &admission.Webhook{
+		Name:          "defaulting.unifiedpushservers.push.aerogear.org",
+		Type:          webhooktypes.WebhookTypeMutating,
+		Path:          DefaultingWebhookPath,
+		FailurePolicy: &failurePolicy,
+		Rules: []admissionregistrationv1beta1.RuleWithOperations{{
+			Operations: []admissionregistrationv1beta1.OperationType{
+				admissionregistrationv1beta1.Create,
+			},
+			Rule: admissionregistrationv1beta1.Rule{
+				APIGroups:   []string{pushv1alpha1.SchemeGroupVersion.Group},
+				APIVersions: []string{pushv1alpha1.SchemeGroupVersion.Version},
+				Resources:   []string{"unifiedpushservers"},
+			},
+		}},
+		Handlers: []admission.Handler{&unifiedPushServerDefaulter{}},
+	}
+}
+
+// unifiedPushServerDefaulter sets the defaults of UnifiedPushServers
+// on create
+type unifiedPushServerDefaulter struct {
+	decoder atypes.Decoder
+}
+
+var _ admission.Handler = &unifiedPushServerDefaulter{}
+var _ inject.Decoder = &unifiedPushServerDefaulter{}
+
+// Handle patches the UnifiedPushServer with its defaults
+func (d *unifiedPushServerDefaulter) Handle(ctx context.Context, req atypes.Request) atypes.Response {
+	ups := &pushv1alpha1.UnifiedPushServer{}
+	if err := d.decoder.Decode(req, ups); err != nil {
+		return admission.ErrorResponse(http.StatusBadRequest, err)
+	}
+
+	defaulted := ups.DeepCopy()
+	defaulted.SetDefaults(cfg)
+	return admission.PatchResponse(ups, defaulted)
+}
+
+// InjectDecoder injects the decoder into the handler
+func (d *unifiedPushServerDefaulter) InjectDecoder(decoder atypes.Decoder) error {
+	d.decoder = decoder
+	return nil
+}
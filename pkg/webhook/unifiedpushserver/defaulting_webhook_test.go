@@ -0,0 +1,92 @@
+package unifiedpushserver
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	pushv1alpha1 "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestUnifiedPushServerDefaulter_Handle(t *testing.T) {
+	s := scheme.Scheme
+	s.AddKnownTypes(pushv1alpha1.SchemeGroupVersion, &pushv1alpha1.UnifiedPushServer{}, &pushv1alpha1.UnifiedPushServerList{})
+	decoder, err := admission.NewDecoder(s)
+	if err != nil {
+		t.Fatalf("create decoder: (%v)", err)
+	}
+
+	defaulter := &unifiedPushServerDefaulter{}
+	defaulter.InjectDecoder(decoder)
+
+	ups := unifiedPushServer()
+	ups.Spec.UnifiedPushResourceRequirements = corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+	}
+	req := admissionRequest(t, ups)
+	resp := defaulter.Handle(context.TODO(), req)
+	if !resp.Response.Allowed {
+		t.Fatalf("expected UnifiedPushServer to be allowed, got %v", resp.Response.Result)
+	}
+
+	b, err := json.Marshal(resp.Patches)
+	if err != nil {
+		t.Fatalf("marshal patches: (%v)", err)
+	}
+	patch, err := jsonpatch.DecodePatch(b)
+	if err != nil {
+		t.Fatalf("decode patch: (%v)", err)
+	}
+	patched, err := patch.Apply(req.AdmissionRequest.Object.Raw)
+	if err != nil {
+		t.Fatalf("apply patch: (%v)", err)
+	}
+	defaulted := &pushv1alpha1.UnifiedPushServer{}
+	if err := json.Unmarshal(patched, defaulted); err != nil {
+		t.Fatalf("unmarshal patched UnifiedPushServer: (%v)", err)
+	}
+
+	upsReqs := defaulted.Spec.UnifiedPushResourceRequirements
+	if memory := upsReqs.Limits[corev1.ResourceMemory]; memory.String() != "4Gi" {
+		t.Errorf("expected memory limit from the spec to be kept, got %s", memory.String())
+	}
+	if cpu := upsReqs.Limits[corev1.ResourceCPU]; cpu.String() != cfg.UPSCpuLimit {
+		t.Errorf("expected default cpu limit %s, got %s", cfg.UPSCpuLimit, cpu.String())
+	}
+	if memory := upsReqs.Requests[corev1.ResourceMemory]; memory.String() != cfg.UPSMemoryRequest {
+		t.Errorf("expected default memory request %s, got %s", cfg.UPSMemoryRequest, memory.String())
+	}
+	if cpu := defaulted.Spec.OAuthResourceRequirements.Requests[corev1.ResourceCPU]; cpu.String() != cfg.OauthCpuRequest {
+		t.Errorf("expected default oauth cpu request %s, got %s", cfg.OauthCpuRequest, cpu.String())
+	}
+	if memory := defaulted.Spec.PostgresResourceRequirements.Limits[corev1.ResourceMemory]; memory.String() != cfg.PostgresMemoryLimit {
+		t.Errorf("expected default postgres memory limit %s, got %s", cfg.PostgresMemoryLimit, memory.String())
+	}
+	if defaulted.Spec.PostgresPVCSize != cfg.PostgresPVCSize {
+		t.Errorf("expected default PVC size %s, got %s", cfg.PostgresPVCSize, defaulted.Spec.PostgresPVCSize)
+	}
+
+	// Nothing is left to default
+	if defaulted.SetDefaults(cfg) {
+		t.Errorf("expected defaulted spec not to change, got %+v", defaulted.Spec)
+	}
+}
+
+func TestSetDefaults_ExternalDB(t *testing.T) {
+	ups := unifiedPushServer()
+	ups.Spec.ExternalDB = true
+	ups.Spec.DatabaseSecret = "db-secret"
+
+	if !ups.SetDefaults(cfg) {
+		t.Fatal("expected spec to be defaulted")
+	}
+	if ups.Spec.PostgresPVCSize != "" || len(ups.Spec.PostgresResourceRequirements.Limits) != 0 {
+		t.Errorf("expected no PostgreSQL defaults with an external database, got %+v", ups.Spec)
+	}
+}
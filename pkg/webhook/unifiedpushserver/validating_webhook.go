@@ -0,0 +1,126 @@
+package unifiedpushserver
+
+import (
+	"context"
+	"net/http"
+
+	pushv1alpha1 "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1"
+	"github.com/aerogear/unifiedpush-operator/pkg/controller/util"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	atypes "sigs.k8s.io/controller-runtime/pkg/webhook/admission/types"
+	webhooktypes "sigs.k8s.io/controller-runtime/pkg/webhook/types"
+)
+
+var log = logf.Log.WithName("unifiedpushserver-webhook")
+
+// ValidatingWebhookPath is the path that the validating webhook is
+// served on, which must match the ValidatingWebhookConfiguration
+const ValidatingWebhookPath = "/validate-push-aerogear-org-v1alpha1-unifiedpushserver"
+
+// NewValidatingWebhook returns the webhook that rejects
+// UnifiedPushServers that fail ValidateUnifiedPushServer
+func NewValidatingWebhook() *admission.Webhook {
+	// Matches deploy/webhook.yaml, where requests are let through while
+	// the webhook isn't served
+	failurePolicy := admissionregistrationv1beta1.Ignore
+	return &admission.Webhook{
+		Name:          "validating.unifiedpushservers.push.aerogear.org",
+		Type:          webhooktypes.WebhookTypeValidating,
+		Path:          ValidatingWebhookPath,
+		FailurePolicy: &failurePolicy,
+		Rules: []admissionregistrationv1beta1.RuleWithOperations{{
+			Operations: []admissionregistrationv1beta1.OperationType{
+				admissionregistrationv1beta1.Create,
+				admissionregistrationv1beta1.Update,
+			},
+			Rule: admissionregistrationv1beta1.Rule{
+				APIGroups:   []string{pushv1alpha1.SchemeGroupVersion.Group},
+				APIVersions: []string{pushv1alpha1.SchemeGroupVersion.Version},
+				Resources:   []string{"unifiedpushservers"},
+			},
+		}},
+		Handlers: []admission.Handler{&unifiedPushServerValidator{}},
+	}
+}
+
+// unifiedPushServerValidator validates UnifiedPushServers on create
+// and update
+type unifiedPushServerValidator struct {
+	// reader reads straight from the apiserver, as the cache of the
+	// manager only holds the watched namespaces
+	reader  client.Reader
+	decoder atypes.Decoder
+}
+
+var _ admission.Handler = &unifiedPushServerValidator{}
+var _ inject.Config = &unifiedPushServerValidator{}
+var _ inject.Decoder = &unifiedPushServerValidator{}
+
+// Handle rejects the request with the field errors of the
+// UnifiedPushServer, if there are any
+func (v *unifiedPushServerValidator) Handle(ctx context.Context, req atypes.Request) atypes.Response {
+	ups := &pushv1alpha1.UnifiedPushServer{}
+	if err := v.decoder.Decode(req, ups); err != nil {
+		return admission.ErrorResponse(http.StatusBadRequest, err)
+	}
+	// The name isn't always set in the object on create
+	if ups.Name == "" {
+		ups.Name = req.AdmissionRequest.Name
+	}
+	if ups.Namespace == "" {
+		ups.Namespace = req.AdmissionRequest.Namespace
+	}
+
+	postgresClaim := &corev1.PersistentVolumeClaim{}
+	err := v.reader.Get(ctx, types.NamespacedName{Name: util.PostgresClaimName(ups), Namespace: ups.Namespace}, postgresClaim)
+	if errors.IsNotFound(err) {
+		postgresClaim = nil
+	} else if errors.IsForbidden(err) {
+		// The operator is only allowed to read the namespaces it watches
+		log.Info("Unable to read the PostgreSQL PersistentVolumeClaim, not checking its size", "Namespace", ups.Namespace, "Error", err.Error())
+		postgresClaim = nil
+	} else if err != nil {
+		return admission.ErrorResponse(http.StatusInternalServerError, err)
+	}
+
+	allErrs := ValidateUnifiedPushServer(ups, postgresClaim)
+	if len(allErrs) == 0 {
+		return admission.ValidationResponse(true, "")
+	}
+
+	status := errors.NewInvalid(schema.GroupKind{Group: pushv1alpha1.SchemeGroupVersion.Group, Kind: "UnifiedPushServer"}, ups.Name, allErrs).ErrStatus
+	return atypes.Response{
+		Response: &admissionv1beta1.AdmissionResponse{
+			Allowed: false,
+			Result:  &status,
+		},
+	}
+}
+
+// InjectConfig creates the client that the handler reads with from
+// the config of the manager
+func (v *unifiedPushServerValidator) InjectConfig(config *rest.Config) error {
+	c, err := client.New(config, client.Options{})
+	if err != nil {
+		return err
+	}
+	v.reader = c
+	return nil
+}
+
+// InjectDecoder injects the decoder into the handler
+func (v *unifiedPushServerValidator) InjectDecoder(d atypes.Decoder) error {
+	v.decoder = d
+	return nil
+}
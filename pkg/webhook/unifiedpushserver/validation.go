@@ -0,0 +1,157 @@
+package unifiedpushserver
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	pushv1alpha1 "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1"
+	"github.com/aerogear/unifiedpush-operator/pkg/config"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+var cfg = config.New()
+
+// ValidateUnifiedPushServer returns the problems with the spec of a
+// UnifiedPushServer that would otherwise only show up once it's
+// reconciled. postgresClaim is the existing PersistentVolumeClaim of
+// the PostgreSQL instance managed by the operator, or nil if there
+// isn't one yet.
+func ValidateUnifiedPushServer(ups *pushv1alpha1.UnifiedPushServer, postgresClaim *corev1.PersistentVolumeClaim) field.ErrorList {
+	allErrs := field.ErrorList{}
+	specPath := field.NewPath("spec")
+
+	//#region Database
+	if ups.Spec.Database != (pushv1alpha1.UnifiedPushServerDatabase{}) && ups.Spec.DatabaseSecret != "" {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("databaseSecret"), "only one of database or databaseSecret may be specified"))
+	}
+	if ups.Spec.DatabaseSecret != "" && !ups.Spec.ExternalDB {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("externalDB"), ups.Spec.ExternalDB, "must be true when databaseSecret is specified"))
+	}
+	//#endregion
+
+	//#region Replicas
+	replicas := int32(1)
+	if ups.Spec.Replicas != nil {
+		replicas = *ups.Spec.Replicas
+		if replicas < 1 {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("replicas"), replicas, "must be at least 1"))
+		}
+	}
+	if autoscaling := ups.Spec.Autoscaling; autoscaling != nil {
+		autoscalingPath := specPath.Child("autoscaling")
+		if autoscaling.MinReplicas != nil {
+			replicas = *autoscaling.MinReplicas
+			if replicas < 1 {
+				allErrs = append(allErrs, field.Invalid(autoscalingPath.Child("minReplicas"), replicas, "must be at least 1"))
+			}
+		}
+		if autoscaling.MaxReplicas < replicas {
+			allErrs = append(allErrs, field.Invalid(autoscalingPath.Child("maxReplicas"), autoscaling.MaxReplicas, fmt.Sprintf("must not be less than the minimum number of replicas (%d)", replicas)))
+		}
+		if target := autoscaling.TargetCPUUtilizationPercentage; target != nil && *target < 1 {
+			allErrs = append(allErrs, field.Invalid(autoscalingPath.Child("targetCPUUtilizationPercentage"), *target, "must be at least 1"))
+		}
+		if autoscaling.CustomMetric != nil && autoscaling.CustomMetric.Name == "" {
+			allErrs = append(allErrs, field.Required(autoscalingPath.Child("customMetric", "name"), "must be set when a custom metric is used"))
+		}
+	}
+	if ups.Spec.MinAvailable != nil {
+		minAvailable := *ups.Spec.MinAvailable
+		if minAvailable < 1 {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("minAvailable"), minAvailable, "must be at least 1"))
+		} else if minAvailable > replicas {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("minAvailable"), minAvailable, fmt.Sprintf("must not be greater than replicas (%d)", replicas)))
+		}
+	}
+	//#endregion
+
+	//#region Images
+	switch ups.Spec.ImagePullPolicy {
+	case "", corev1.PullAlways, corev1.PullIfNotPresent, corev1.PullNever:
+	default:
+		allErrs = append(allErrs, field.NotSupported(specPath.Child("imagePullPolicy"), ups.Spec.ImagePullPolicy, []string{string(corev1.PullAlways), string(corev1.PullIfNotPresent), string(corev1.PullNever)}))
+	}
+	//#endregion
+
+	//#region Message broker
+	if ups.Spec.UseMessageBroker && strings.Contains(ups.Name, ".") {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("metadata", "name"), ups.Name, "must not contain dots when useMessageBroker is true, since AMQ Online uses it as a prefix of the MessagingUser and Address names"))
+	}
+	//#endregion
+
+	//#region Auth
+	if auth := ups.Spec.Auth; auth != nil && auth.OpenShift != nil {
+		openshiftPath := specPath.Child("auth", "openshift")
+		if auth.OIDC != nil {
+			allErrs = append(allErrs, field.Forbidden(openshiftPath, "can't be set along with oidc"))
+		}
+		if sar := auth.OpenShift.SubjectAccessReview; sar != nil {
+			sarPath := openshiftPath.Child("subjectAccessReview")
+			if sar.Resource == "" {
+				allErrs = append(allErrs, field.Required(sarPath.Child("resource"), ""))
+			}
+			if sar.Verb == "" {
+				allErrs = append(allErrs, field.Required(sarPath.Child("verb"), ""))
+			}
+		}
+		for i, group := range auth.OpenShift.AllowedGroups {
+			if group == "" {
+				allErrs = append(allErrs, field.Invalid(openshiftPath.Child("allowedGroups").Index(i), group, "must not be empty"))
+			}
+		}
+		if auth.OpenShift.BearerTokens && auth.OpenShift.SubjectAccessReview == nil && len(auth.OpenShift.AllowedGroups) > 0 {
+			allErrs = append(allErrs, field.Required(openshiftPath.Child("subjectAccessReview"), "bearer tokens can't be checked against allowedGroups"))
+		}
+	}
+	if auth := ups.Spec.Auth; auth != nil && auth.OIDC != nil {
+		oidcPath := specPath.Child("auth", "oidc")
+		if issuerURL, err := url.Parse(auth.OIDC.IssuerURL); auth.OIDC.IssuerURL == "" {
+			allErrs = append(allErrs, field.Required(oidcPath.Child("issuerURL"), ""))
+		} else if err != nil || !issuerURL.IsAbs() || issuerURL.Host == "" {
+			allErrs = append(allErrs, field.Invalid(oidcPath.Child("issuerURL"), auth.OIDC.IssuerURL, "must be an absolute URL"))
+		}
+		if auth.OIDC.ClientSecret == "" {
+			allErrs = append(allErrs, field.Required(oidcPath.Child("clientSecret"), "the name of a Secret with the client-id and client-secret is required"))
+		}
+		for i, group := range auth.OIDC.AllowedGroups {
+			if group == "" {
+				allErrs = append(allErrs, field.Invalid(oidcPath.Child("allowedGroups").Index(i), group, "must not be empty"))
+			}
+		}
+	}
+	//#endregion
+
+	//#region Backups
+	for i, backup := range ups.Spec.Backups {
+		if err := validateSchedule(backup.Schedule); err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("backups").Index(i).Child("schedule"), backup.Schedule, err.Error()))
+		}
+	}
+	//#endregion
+
+	//#region Postgres PVC
+	if !ups.Spec.ExternalDB {
+		pvcSizePath := specPath.Child("postgresPVCSize")
+		pvcSize := ups.Spec.PostgresPVCSize
+		if pvcSize == "" {
+			pvcSize = cfg.PostgresPVCSize
+		}
+
+		size, err := resource.ParseQuantity(pvcSize)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(pvcSizePath, ups.Spec.PostgresPVCSize, err.Error()))
+		} else if postgresClaim != nil {
+			current := postgresClaim.Spec.Resources.Requests[corev1.ResourceStorage]
+			if size.Cmp(current) < 0 {
+				allErrs = append(allErrs, field.Invalid(pvcSizePath, pvcSize, fmt.Sprintf("must not be smaller than the existing PersistentVolumeClaim %s (%s)", postgresClaim.Name, current.String())))
+			}
+		}
+	}
+	//#endregion
+
+	return allErrs
+}
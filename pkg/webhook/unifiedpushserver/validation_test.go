@@ -0,0 +1,437 @@
+package unifiedpushserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	pushv1alpha1 "github.com/aerogear/unifiedpush-operator/pkg/apis/push/v1alpha1"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	atypes "sigs.k8s.io/controller-runtime/pkg/webhook/admission/types"
+)
+
+func TestValidateUnifiedPushServer(t *testing.T) {
+	cases := []struct {
+		Name           string
+		ObjectName     string
+		Spec           pushv1alpha1.UnifiedPushServerSpec
+		PostgresClaim  *corev1.PersistentVolumeClaim
+		ExpectedFields []string
+	}{
+		{
+			Name: "empty spec",
+		},
+		{
+			Name: "external DB details",
+			Spec: pushv1alpha1.UnifiedPushServerSpec{
+				ExternalDB: true,
+				Database:   pushv1alpha1.UnifiedPushServerDatabase{Name: "ups", Host: "postgres"},
+			},
+		},
+		{
+			Name: "external DB secret",
+			Spec: pushv1alpha1.UnifiedPushServerSpec{
+				ExternalDB:     true,
+				DatabaseSecret: "db-secret",
+			},
+		},
+		{
+			Name: "both database and databaseSecret",
+			Spec: pushv1alpha1.UnifiedPushServerSpec{
+				ExternalDB:     true,
+				Database:       pushv1alpha1.UnifiedPushServerDatabase{Name: "ups"},
+				DatabaseSecret: "db-secret",
+			},
+			ExpectedFields: []string{"spec.databaseSecret"},
+		},
+		{
+			Name: "databaseSecret without externalDB",
+			Spec: pushv1alpha1.UnifiedPushServerSpec{
+				DatabaseSecret: "db-secret",
+			},
+			ExpectedFields: []string{"spec.externalDB"},
+		},
+		{
+			Name: "valid backup schedules",
+			Spec: pushv1alpha1.UnifiedPushServerSpec{
+				Backups: []pushv1alpha1.UnifiedPushServerBackup{
+					{Name: "hourly", Schedule: "0 * * * *"},
+					{Name: "weekdays", Schedule: "*/15 1-5,22 ? JAN-dec mon-fri"},
+					{Name: "daily", Schedule: "@daily"},
+				},
+			},
+		},
+		{
+			Name: "invalid backup schedules",
+			Spec: pushv1alpha1.UnifiedPushServerSpec{
+				Backups: []pushv1alpha1.UnifiedPushServerBackup{
+					{Name: "valid", Schedule: "0 0 * * *"},
+					{Name: "too-few-fields", Schedule: "0 0 * *"},
+					{Name: "out-of-range", Schedule: "60 0 * * *"},
+					{Name: "bad-step", Schedule: "*/0 * * * *"},
+					{Name: "reversed-range", Schedule: "0 5-1 * * *"},
+					{Name: "bad-descriptor", Schedule: "@sometimes"},
+				},
+			},
+			ExpectedFields: []string{
+				"spec.backups[1].schedule",
+				"spec.backups[2].schedule",
+				"spec.backups[3].schedule",
+				"spec.backups[4].schedule",
+				"spec.backups[5].schedule",
+			},
+		},
+		{
+			Name: "several replicas",
+			Spec: pushv1alpha1.UnifiedPushServerSpec{
+				Replicas:     int32Ptr(3),
+				MinAvailable: int32Ptr(2),
+			},
+		},
+		{
+			Name: "no replicas",
+			Spec: pushv1alpha1.UnifiedPushServerSpec{
+				Replicas: int32Ptr(0),
+			},
+			ExpectedFields: []string{"spec.replicas"},
+		},
+		{
+			Name: "more available than replicas",
+			Spec: pushv1alpha1.UnifiedPushServerSpec{
+				Replicas:     int32Ptr(2),
+				MinAvailable: int32Ptr(3),
+			},
+			ExpectedFields: []string{"spec.minAvailable"},
+		},
+		{
+			Name: "none available",
+			Spec: pushv1alpha1.UnifiedPushServerSpec{
+				MinAvailable: int32Ptr(0),
+			},
+			ExpectedFields: []string{"spec.minAvailable"},
+		},
+		{
+			Name: "autoscaling",
+			Spec: pushv1alpha1.UnifiedPushServerSpec{
+				Autoscaling: &pushv1alpha1.UnifiedPushServerAutoscaling{
+					MinReplicas:  int32Ptr(2),
+					MaxReplicas:  10,
+					CustomMetric: &pushv1alpha1.UnifiedPushServerCustomMetric{Name: "push_requests_per_second", TargetAverageValue: resource.MustParse("10")},
+				},
+				MinAvailable: int32Ptr(2),
+			},
+		},
+		{
+			Name: "invalid autoscaling",
+			Spec: pushv1alpha1.UnifiedPushServerSpec{
+				Autoscaling: &pushv1alpha1.UnifiedPushServerAutoscaling{
+					MinReplicas:                    int32Ptr(3),
+					MaxReplicas:                    2,
+					TargetCPUUtilizationPercentage: int32Ptr(0),
+					CustomMetric:                   &pushv1alpha1.UnifiedPushServerCustomMetric{},
+				},
+			},
+			ExpectedFields: []string{
+				"spec.autoscaling.maxReplicas",
+				"spec.autoscaling.targetCPUUtilizationPercentage",
+				"spec.autoscaling.customMetric.name",
+			},
+		},
+		{
+			Name: "image overrides",
+			Spec: pushv1alpha1.UnifiedPushServerSpec{
+				Images: pushv1alpha1.UnifiedPushServerImages{
+					UnifiedPush: "mirror.example.com/aerogear/unifiedpush-configurable-container@sha256:9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+				},
+				ImagePullPolicy:  corev1.PullIfNotPresent,
+				ImagePullSecrets: []corev1.LocalObjectReference{{Name: "mirror"}},
+			},
+		},
+		{
+			Name: "invalid image pull policy",
+			Spec: pushv1alpha1.UnifiedPushServerSpec{
+				ImagePullPolicy: "Sometimes",
+			},
+			ExpectedFields: []string{"spec.imagePullPolicy"},
+		},
+		{
+			Name:          "growing the PVC",
+			Spec:          pushv1alpha1.UnifiedPushServerSpec{PostgresPVCSize: "10Gi"},
+			PostgresClaim: postgresClaim("5Gi"),
+		},
+		{
+			Name:           "shrinking the PVC",
+			Spec:           pushv1alpha1.UnifiedPushServerSpec{PostgresPVCSize: "1Gi"},
+			PostgresClaim:  postgresClaim("5Gi"),
+			ExpectedFields: []string{"spec.postgresPVCSize"},
+		},
+		{
+			Name:           "shrinking the PVC to the default size",
+			PostgresClaim:  postgresClaim("10Gi"),
+			ExpectedFields: []string{"spec.postgresPVCSize"},
+		},
+		{
+			Name:           "invalid PVC size",
+			Spec:           pushv1alpha1.UnifiedPushServerSpec{PostgresPVCSize: "lots"},
+			ExpectedFields: []string{"spec.postgresPVCSize"},
+		},
+		{
+			Name: "PVC size with external DB",
+			Spec: pushv1alpha1.UnifiedPushServerSpec{
+				ExternalDB:      true,
+				DatabaseSecret:  "db-secret",
+				PostgresPVCSize: "1Gi",
+			},
+			PostgresClaim: postgresClaim("5Gi"),
+		},
+		{
+			Name: "OIDC auth",
+			Spec: pushv1alpha1.UnifiedPushServerSpec{
+				Auth: &pushv1alpha1.UnifiedPushServerAuth{
+					OIDC: &pushv1alpha1.UnifiedPushServerOIDC{
+						IssuerURL:     "https://keycloak.example.com/auth/realms/push",
+						ClientSecret:  "ups-oidc-client",
+						AllowedGroups: []string{"push-admins"},
+					},
+				},
+			},
+		},
+		{
+			Name: "OIDC auth without issuer or client",
+			Spec: pushv1alpha1.UnifiedPushServerSpec{
+				Auth: &pushv1alpha1.UnifiedPushServerAuth{
+					OIDC: &pushv1alpha1.UnifiedPushServerOIDC{},
+				},
+			},
+			ExpectedFields: []string{"spec.auth.oidc.issuerURL", "spec.auth.oidc.clientSecret"},
+		},
+		{
+			Name: "OIDC auth with a relative issuer and an empty group",
+			Spec: pushv1alpha1.UnifiedPushServerSpec{
+				Auth: &pushv1alpha1.UnifiedPushServerAuth{
+					OIDC: &pushv1alpha1.UnifiedPushServerOIDC{
+						IssuerURL:     "/auth/realms/push",
+						ClientSecret:  "ups-oidc-client",
+						AllowedGroups: []string{""},
+					},
+				},
+			},
+			ExpectedFields: []string{"spec.auth.oidc.issuerURL", "spec.auth.oidc.allowedGroups[0]"},
+		},
+		{
+			Name: "OpenShift auth",
+			Spec: pushv1alpha1.UnifiedPushServerSpec{
+				Auth: &pushv1alpha1.UnifiedPushServerAuth{
+					OpenShift: &pushv1alpha1.UnifiedPushServerOpenShiftAuth{
+						SubjectAccessReview: &pushv1alpha1.UnifiedPushServerSubjectAccessReview{
+							Group:    "push.aerogear.org",
+							Resource: "pushapplications",
+							Verb:     "update",
+						},
+						AllowedGroups: []string{"push-admins"},
+					},
+				},
+			},
+		},
+		{
+			Name: "OpenShift auth with an incomplete review and an empty group",
+			Spec: pushv1alpha1.UnifiedPushServerSpec{
+				Auth: &pushv1alpha1.UnifiedPushServerAuth{
+					OpenShift: &pushv1alpha1.UnifiedPushServerOpenShiftAuth{
+						SubjectAccessReview: &pushv1alpha1.UnifiedPushServerSubjectAccessReview{},
+						AllowedGroups:       []string{""},
+					},
+				},
+			},
+			ExpectedFields: []string{"spec.auth.openshift.subjectAccessReview.resource", "spec.auth.openshift.subjectAccessReview.verb", "spec.auth.openshift.allowedGroups[0]"},
+		},
+		{
+			Name: "OpenShift auth with bearer tokens and only groups",
+			Spec: pushv1alpha1.UnifiedPushServerSpec{
+				Auth: &pushv1alpha1.UnifiedPushServerAuth{
+					OpenShift: &pushv1alpha1.UnifiedPushServerOpenShiftAuth{
+						AllowedGroups: []string{"push-admins"},
+						BearerTokens:  true,
+					},
+				},
+			},
+			ExpectedFields: []string{"spec.auth.openshift.subjectAccessReview"},
+		},
+		{
+			Name: "OpenShift and OIDC auth",
+			Spec: pushv1alpha1.UnifiedPushServerSpec{
+				Auth: &pushv1alpha1.UnifiedPushServerAuth{
+					OpenShift: &pushv1alpha1.UnifiedPushServerOpenShiftAuth{
+						AllowedGroups: []string{"push-admins"},
+					},
+					OIDC: &pushv1alpha1.UnifiedPushServerOIDC{
+						IssuerURL:    "https://keycloak.example.com/auth/realms/push",
+						ClientSecret: "ups-oidc-client",
+					},
+				},
+			},
+			ExpectedFields: []string{"spec.auth.openshift"},
+		},
+		{
+			Name:       "message broker",
+			ObjectName: "example-ups",
+			Spec:       pushv1alpha1.UnifiedPushServerSpec{UseMessageBroker: true},
+		},
+		{
+			Name:           "message broker with a dot in the name",
+			ObjectName:     "example.ups",
+			Spec:           pushv1alpha1.UnifiedPushServerSpec{UseMessageBroker: true},
+			ExpectedFields: []string{"metadata.name"},
+		},
+		{
+			Name:       "dot in the name without a message broker",
+			ObjectName: "example.ups",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			ups := unifiedPushServer()
+			if tc.ObjectName != "" {
+				ups.Name = tc.ObjectName
+			}
+			ups.Spec = tc.Spec
+
+			allErrs := ValidateUnifiedPushServer(ups, tc.PostgresClaim)
+			if len(allErrs) != len(tc.ExpectedFields) {
+				t.Fatalf("expected errors for %v, got %v", tc.ExpectedFields, allErrs)
+			}
+			for i, err := range allErrs {
+				if err.Field != tc.ExpectedFields[i] {
+					t.Errorf("expected error for %s, got %v", tc.ExpectedFields[i], err)
+				}
+			}
+		})
+	}
+}
+
+func TestUnifiedPushServerValidator_Handle(t *testing.T) {
+	s := scheme.Scheme
+	s.AddKnownTypes(pushv1alpha1.SchemeGroupVersion, &pushv1alpha1.UnifiedPushServer{}, &pushv1alpha1.UnifiedPushServerList{})
+	decoder, err := admission.NewDecoder(s)
+	if err != nil {
+		t.Fatalf("create decoder: (%v)", err)
+	}
+
+	validator := &unifiedPushServerValidator{}
+	validator.reader = fakeclient.NewFakeClient(postgresClaim("5Gi"))
+	validator.InjectDecoder(decoder)
+
+	ups := unifiedPushServer()
+	ups.Spec.PostgresPVCSize = "10Gi"
+	resp := validator.Handle(context.TODO(), admissionRequest(t, ups))
+	if !resp.Response.Allowed {
+		t.Errorf("expected valid UnifiedPushServer to be allowed, got %v", resp.Response.Result)
+	}
+
+	ups.Spec.PostgresPVCSize = "1Gi"
+	ups.Spec.DatabaseSecret = "db-secret"
+	resp = validator.Handle(context.TODO(), admissionRequest(t, ups))
+	if resp.Response.Allowed {
+		t.Fatal("expected invalid UnifiedPushServer to be rejected")
+	}
+	if resp.Response.Result.Reason != metav1.StatusReasonInvalid || resp.Response.Result.Details == nil {
+		t.Fatalf("expected an Invalid status with details, got %v", resp.Response.Result)
+	}
+	fields := []string{}
+	for _, cause := range resp.Response.Result.Details.Causes {
+		fields = append(fields, cause.Field)
+	}
+	if len(fields) != 2 || fields[0] != "spec.externalDB" || fields[1] != "spec.postgresPVCSize" {
+		t.Errorf("expected causes for spec.externalDB and spec.postgresPVCSize, got %v", fields)
+	}
+}
+
+func TestUnifiedPushServerValidator_Handle_Forbidden(t *testing.T) {
+	s := scheme.Scheme
+	s.AddKnownTypes(pushv1alpha1.SchemeGroupVersion, &pushv1alpha1.UnifiedPushServer{}, &pushv1alpha1.UnifiedPushServerList{})
+	decoder, err := admission.NewDecoder(s)
+	if err != nil {
+		t.Fatalf("create decoder: (%v)", err)
+	}
+
+	// The operator isn't allowed to read claims outside of the
+	// namespaces it watches
+	validator := &unifiedPushServerValidator{reader: forbiddenReader{}}
+	validator.InjectDecoder(decoder)
+
+	ups := unifiedPushServer()
+	ups.Spec.PostgresPVCSize = "1Gi"
+	resp := validator.Handle(context.TODO(), admissionRequest(t, ups))
+	if !resp.Response.Allowed {
+		t.Errorf("expected UnifiedPushServer to be allowed without checking the claim, got %v", resp.Response.Result)
+	}
+}
+
+type forbiddenReader struct {
+	client.Reader
+}
+
+func (forbiddenReader) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	return errors.NewForbidden(schema.GroupResource{Resource: "persistentvolumeclaims"}, key.Name, fmt.Errorf("not allowed"))
+}
+
+func admissionRequest(t *testing.T, ups *pushv1alpha1.UnifiedPushServer) atypes.Request {
+	raw, err := json.Marshal(ups)
+	if err != nil {
+		t.Fatalf("marshal UnifiedPushServer: (%v)", err)
+	}
+	return atypes.Request{
+		AdmissionRequest: &admissionv1beta1.AdmissionRequest{
+			Name:      ups.Name,
+			Namespace: ups.Namespace,
+			Operation: admissionv1beta1.Update,
+			Object:    runtime.RawExtension{Raw: raw},
+		},
+	}
+}
+
+func unifiedPushServer() *pushv1alpha1.UnifiedPushServer {
+	return &pushv1alpha1.UnifiedPushServer{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: pushv1alpha1.SchemeGroupVersion.String(),
+			Kind:       "UnifiedPushServer",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "example-unifiedpushserver",
+			Namespace: "unifiedpush",
+		},
+	}
+}
+
+func postgresClaim(size string) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "example-unifiedpushserver-postgresql",
+			Namespace: "unifiedpush",
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse(size),
+				},
+			},
+		},
+	}
+}
+
+func int32Ptr(i int32) *int32 {
+	return &i
+}
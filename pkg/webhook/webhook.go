@@ -0,0 +1,71 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const (
+	// CertFile and KeyFile are the names of the serving certificate
+	// and key in the cert dir, as mounted from a kubernetes.io/tls Secret
+	CertFile = "tls.crt"
+	KeyFile  = "tls.key"
+)
+
+var log = logf.Log.WithName("webhook")
+
+// AddToManagerFuncs is a list of functions that create the admission webhooks
+var AddToManagerFuncs []func() *admission.Webhook
+
+// AddToManager adds all admission webhooks to the Manager, served over
+// HTTPS on the given port with the certificate in certDir. Nothing is
+// served if there is no certificate, e.g. when the operator is run
+// locally.
+func AddToManager(m manager.Manager, port int32, certDir string) error {
+	certFile := filepath.Join(certDir, CertFile)
+	keyFile := filepath.Join(certDir, KeyFile)
+	if _, err := os.Stat(certFile); os.IsNotExist(err) {
+		log.Info("No serving certificate found, admission webhooks are disabled", "CertFile", certFile)
+		return nil
+	}
+
+	mux := http.NewServeMux()
+	for _, f := range AddToManagerFuncs {
+		wh := f()
+		// Injects the client and decoder into the handlers
+		if err := m.SetFields(wh); err != nil {
+			return err
+		}
+		if err := wh.Validate(); err != nil {
+			return err
+		}
+		log.Info("Registering admission webhook", "Name", wh.GetName(), "Path", wh.GetPath())
+		mux.Handle(wh.GetPath(), wh.Handler())
+	}
+
+	server := &http.Server{
+		Addr:    fmt.Sprintf(":%d", port),
+		Handler: mux,
+	}
+	return m.Add(manager.RunnableFunc(func(stop <-chan struct{}) error {
+		go func() {
+			<-stop
+			if err := server.Shutdown(context.Background()); err != nil {
+				log.Error(err, "Error shutting down the webhook server")
+			}
+		}()
+
+		log.Info("Serving admission webhooks", "Addr", server.Addr)
+		if err := server.ListenAndServeTLS(certFile, keyFile); err != nil && err != http.ErrServerClosed {
+			return err
+		}
+		return nil
+	}))
+}